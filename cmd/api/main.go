@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	_ "time/tzdata" // user time zones must resolve even in images without zoneinfo
 
 	"github.com/joho/godotenv"
 	_ "lissanai.com/backend/docs" // <-- add this for swagger
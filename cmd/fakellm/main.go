@@ -0,0 +1,26 @@
+// Command fakellm serves the deterministic fake language model through the
+// OpenAI chat completions API, for running the app without real providers:
+//
+//	go run ./cmd/fakellm
+//	OPENAI_BASE_URL=http://localhost:8090/v1 go run ./cmd/api
+//
+// The model names "rate-limited" and "error" fail with 429 and 500.
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"lissanai.com/backend/internal/llm"
+)
+
+func main() {
+	addr := os.Getenv("FAKE_LLM_ADDR")
+	if addr == "" {
+		addr = ":8090"
+	}
+
+	log.Printf("Fake LLM listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, llm.NewFakeServer(llm.NewFake())))
+}
@@ -15,38 +15,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/learning/lessons/{id}": {
+        "/admin/audit-logs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch the content for a specific lesson",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Most recent admin actions first. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
+                    "Admin"
                 ],
-                "summary": "Get lesson content",
+                "summary": "List audit log entries",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Lesson ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by acting user ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by action, e.g. user.suspend",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.LessonResponse"
+                            "$ref": "#/definitions/domain.AuditLogListResponse"
                         }
                     },
                     "400": {
@@ -67,12 +87,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -82,44 +96,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/learning/lessons/{id}/complete": {
-            "post": {
+        "/admin/jobs": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark a lesson as completed for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Background jobs with their cron schedule (UTC), next and last run, the instance running them now, and their 10 most recent runs. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
-                ],
-                "summary": "Mark lesson as completed",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Lesson ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "List scheduled jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ScheduledJob"
+                            }
                         }
                     },
                     "401": {
@@ -134,12 +133,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -149,32 +142,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/learning/paths": {
-            "get": {
+        "/admin/jobs/{name}/run": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all available learning paths with user progress if enrolled",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Start a scheduled job immediately, in the background, without changing its schedule. Follow the run in the job list. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
+                    "Admin"
+                ],
+                "summary": "Run a job now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name, e.g. streak-expiry",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all learning paths",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.LearningPathResponse"
-                            }
+                            "$ref": "#/definitions/domain.JobRun"
                         }
                     },
                     "401": {
@@ -183,8 +179,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -192,14 +200,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/learning/paths/{id}/enroll": {
+        "/admin/learning/lessons": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Enroll the authenticated user in a specific learning path",
+                "description": "Add a lesson to a learning path. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -207,23 +215,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
+                    "Admin"
                 ],
-                "summary": "Enroll in a learning path",
+                "summary": "Create a lesson",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Learning Path ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Lesson",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.LessonRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.SuccessResponse"
+                            "$ref": "#/definitions/domain.Lesson"
                         }
                     },
                     "400": {
@@ -238,14 +248,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -253,14 +263,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/learning/paths/{id}/progress": {
-            "get": {
+        "/admin/learning/lessons/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the authenticated user's progress for a specific learning path",
+                "description": "Changing path_id moves the lesson to another learning path. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -268,23 +278,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
+                    "Admin"
                 ],
-                "summary": "Get user progress for a learning path",
+                "summary": "Update a lesson",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Learning Path ID",
+                        "description": "Lesson ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Lesson",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.LessonRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ProgressResponse"
+                            "$ref": "#/definitions/domain.Lesson"
                         }
                     },
                     "400": {
@@ -299,68 +318,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/learning/quizzes/{id}/submit": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Submit user's answers to a quiz for grading",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a lesson together with its quiz. Content editors and admins only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Learning"
+                    "Admin"
                 ],
-                "summary": "Submit quiz answers",
+                "summary": "Delete a lesson",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Quiz ID",
+                        "description": "Lesson ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Quiz answers",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.QuizSubmissionRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.QuizResultResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ErrorResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
                     "401": {
@@ -380,24 +379,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/api/v1/pronunciation/activity": {
+        "/admin/learning/paths": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Record that a user completed a pronunciation practice session for streak tracking",
+                "description": "Content editors and admins only",
                 "consumes": [
                     "application/json"
                 ],
@@ -405,25 +398,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pronunciation"
+                    "Admin"
                 ],
-                "summary": "Record pronunciation practice activity",
+                "summary": "Create a learning path",
                 "parameters": [
                     {
-                        "description": "Pronunciation session data",
+                        "description": "Learning path",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/domain.LearningPathRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.SuccessResponse"
+                            "$ref": "#/definitions/domain.LearningPath"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "401": {
@@ -432,8 +431,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -441,14 +440,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/streak/activity": {
-            "post": {
+        "/admin/learning/paths/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Record a user activity to maintain their streak - this is called internally by other services",
+                "description": "Content editors and admins only",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,28 +455,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streak"
+                    "Admin"
                 ],
-                "summary": "Record activity (Internal)",
+                "summary": "Update a learning path",
                 "parameters": [
                     {
-                        "enum": [
-                            "lesson_completed",
-                            "quiz_passed",
-                            "daily_goal_met"
-                        ],
                         "type": "string",
-                        "description": "Type of activity",
-                        "name": "activity_type",
-                        "in": "query",
+                        "description": "Learning path ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Learning path",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.LearningPathRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.SuccessResponse"
+                            "$ref": "#/definitions/domain.LearningPath"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "401": {
@@ -486,44 +495,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/streak/calendar": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get GitHub-like activity calendar showing daily learning activities",
+                "description": "Delete an empty learning path; its lessons must be deleted first. Content editors and admins only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Streak"
+                    "Admin"
                 ],
-                "summary": "Get activity calendar",
+                "summary": "Delete a learning path",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 2025,
-                        "description": "Year (default: current year)",
-                        "name": "year",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Learning path ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ActivityCalendarResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
                     "401": {
@@ -532,8 +545,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -541,14 +566,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/streak/freeze": {
+        "/admin/learning/quizzes": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Freeze the user's current streak to prevent it from being lost due to inactivity (limited uses per month)",
+                "description": "Attach a quiz to a lesson; a lesson has at most one quiz. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -556,24 +581,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Streak"
+                    "Admin"
                 ],
-                "summary": "Freeze streak",
+                "summary": "Create a quiz",
                 "parameters": [
                     {
-                        "description": "Freeze reason",
+                        "description": "Quiz",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.FreezeStreakRequest"
+                            "$ref": "#/definitions/domain.QuizRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.SuccessResponse"
+                            "$ref": "#/definitions/domain.Quiz"
                         }
                     },
                     "400": {
@@ -588,45 +614,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/streak/info": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get the current user's streak information including current streak, longest streak, and freeze status",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Streak"
-                ],
-                "summary": "Get user streak information",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.StreakInfo"
-                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -634,9 +635,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/forgot-password": {
-            "post": {
-                "description": "Send password reset link to user's email",
+        "/admin/learning/quizzes/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a quiz's title and questions. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -644,17 +650,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Request password reset",
+                "summary": "Update a quiz",
                 "parameters": [
                     {
-                        "description": "User Email",
-                        "name": "email",
+                        "type": "string",
+                        "description": "Quiz ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quiz",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ForgotPasswordRequest"
+                            "$ref": "#/definitions/domain.QuizRequest"
                         }
                     }
                 ],
@@ -662,7 +675,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MessageResponse"
+                            "$ref": "#/definitions/domain.Quiz"
                         }
                     },
                     "400": {
@@ -670,49 +683,71 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user with email and password",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Content editors and admins only",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Login user",
+                "summary": "Delete a quiz",
                 "parameters": [
                     {
-                        "description": "User Login Credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Quiz ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.AuthResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -720,39 +755,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/admin/prompts": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invalidate user's session token",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Every prompt the AI features use, with all its versions and locale variants and the rollout deciding which version users get. Content editors and admins only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Logout user",
-                "parameters": [
-                    {
-                        "description": "Refresh Token (optional)",
-                        "name": "refreshToken",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/domain.RefreshTokenRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "List AI prompts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MessageResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Prompt"
+                            }
                         }
                     },
                     "401": {
@@ -760,13 +785,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "Use refresh token to get a new access token",
+        "/admin/prompts/{name}/rollout": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Serve a version of the prompt (0 for the highest version in the prompt files), optionally with an experiment splitting signed-in users between versions by weight. Leaving out the experiment ends it. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -774,17 +816,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Set a prompt's rollout",
                 "parameters": [
                     {
-                        "description": "Refresh Token",
-                        "name": "refreshToken",
+                        "type": "string",
+                        "description": "Prompt name, e.g. grammar",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollout",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.RefreshTokenRequest"
+                            "$ref": "#/definitions/domain.PromptRolloutRequest"
                         }
                     }
                 ],
@@ -792,7 +841,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.TokenResponse"
+                            "$ref": "#/definitions/domain.PromptRollout"
                         }
                     },
                     "400": {
@@ -806,13 +855,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/admin/prompts/{name}/templates": {
             "post": {
-                "description": "Creates a new user account with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Without a version, adds the next version of the prompt; it serves nobody until the rollout or an experiment names it. With a version, adds a locale variant of it, e.g. \"am\". Templates use Go template syntax over the prompt's variables. Content editors and admins only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -820,17 +886,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Add a prompt template",
                 "parameters": [
                     {
-                        "description": "User Registration Information",
-                        "name": "userInfo",
+                        "type": "string",
+                        "description": "Prompt name, e.g. grammar",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.RegisterRequest"
+                            "$ref": "#/definitions/domain.PromptTemplateRequest"
                         }
                     }
                 ],
@@ -838,7 +911,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.AuthResponse"
+                            "$ref": "#/definitions/domain.PromptTemplate"
                         }
                     },
                     "400": {
@@ -847,44 +920,90 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/reset-password": {
-            "post": {
-                "description": "Set new password using reset token",
-                "consumes": [
-                    "application/json"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Search users by name or email, optionally filtered by role and suspension state. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Reset password",
+                "summary": "Search users",
                 "parameters": [
                     {
-                        "description": "Password Reset Information",
-                        "name": "resetInfo",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ResetPasswordRequest"
-                        }
+                        "type": "string",
+                        "description": "Name or email contains",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "learner",
+                            "content_editor",
+                            "admin"
+                        ],
+                        "type": "string",
+                        "description": "Role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Suspension state",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MessageResponse"
+                            "$ref": "#/definitions/domain.UserSearchResponse"
                         }
                     },
                     "400": {
@@ -898,13 +1017,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/auth/social": {
-            "post": {
-                "description": "Authenticate or register user using social providers (Google, Apple)",
+        "/admin/users/{id}/roles": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the roles of a user. Takes effect when the user's access token is next refreshed. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -912,17 +1048,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Social authentication",
+                "summary": "Set user roles",
                 "parameters": [
                     {
-                        "description": "Social Authentication Information",
-                        "name": "socialInfo",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Roles",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.SocialAuthRequest"
+                            "$ref": "#/definitions/domain.UpdateRolesRequest"
                         }
                     }
                 ],
@@ -930,7 +1073,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.AuthResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
                     "400": {
@@ -944,13 +1087,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/email/edit": {
+        "/admin/users/{id}/suspend": {
             "post": {
-                "description": "Corrects and improves a user's drafted email to make it more professional.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspend an account and log it out of every device. Suspended users cannot log in or refresh tokens. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -958,17 +1118,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Admin"
                 ],
-                "summary": "Edit an existing email",
+                "summary": "Suspend a user",
                 "parameters": [
                     {
-                        "description": "The user's email draft and optional tone/template.",
-                        "name": "editRequest",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Suspension reason",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entities.EditEmailRequest"
+                            "$ref": "#/definitions/domain.SuspendUserRequest"
                         }
                     }
                 ],
@@ -976,98 +1143,96 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.EmailResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/email/generate": {
+        "/admin/users/{id}/unsuspend": {
             "post": {
-                "description": "Generates a complete, professional email from a user's prompt (which can be in English or Amharic).",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Allow a suspended user to log in again. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Email"
+                    "Admin"
                 ],
-                "summary": "Generate a new email",
+                "summary": "Lift a suspension",
                 "parameters": [
                     {
-                        "description": "The user's prompt and optional tone/template.",
-                        "name": "generateRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entities.GenerateEmailRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.EmailResponse"
+                            "$ref": "#/definitions/domain.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/grammar/check": {
-            "post": {
+        "/api/v1/learning/lessons/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Analyzes text for grammatical errors and returns corrections and explanations.",
+                "description": "Fetch the content for a specific lesson",
                 "consumes": [
                     "application/json"
                 ],
@@ -1075,151 +1240,133 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Grammar"
+                    "Learning"
                 ],
-                "summary": "Check Grammar",
+                "summary": "Get lesson content",
                 "parameters": [
                     {
-                        "description": "Text to be checked",
-                        "name": "text",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.GrammarRequest"
-                        }
+                        "type": "string",
+                        "description": "Lesson ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns corrected text and explanation",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GrammarResponse"
+                            "$ref": "#/definitions/domain.LessonResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/interview/answer": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Submit an answer for the current session question",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Interview"
-                ],
-                "summary": "Submit user's answer",
-                "parameters": [
-                    {
-                        "description": "Answer input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SubmitAnswerRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Answer submitted successfully",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.Feedback"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/interview/question": {
-            "get": {
+        "/api/v1/learning/lessons/{id}/complete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves the next question for the current session",
+                "description": "Mark a lesson as completed for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Interview"
+                    "Learning"
                 ],
-                "summary": "Get the next interview question",
+                "summary": "Mark lesson as completed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
-                        "name": "session_id",
-                        "in": "query",
+                        "description": "Lesson ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Next question returned",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.NextQuestionReturn"
+                            "$ref": "#/definitions/domain.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/interview/start": {
-            "post": {
+        "/api/v1/learning/paths": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new interview session for the authenticated user",
+                "description": "Retrieve all available learning paths with user progress if enrolled",
                 "consumes": [
                     "application/json"
                 ],
@@ -1227,103 +1374,119 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Interview"
+                    "Learning"
                 ],
-                "summary": "Start a new interview session",
+                "summary": "Get all learning paths",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionReturn"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.LearningPathResponse"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/interview/{session_id}/end": {
+        "/api/v1/learning/paths/{id}/enroll": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Ends the session and returns the final summary",
+                "description": "Enroll the authenticated user in a specific learning path",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Interview"
+                    "Learning"
                 ],
-                "summary": "End an interview session",
+                "summary": "Enroll in a learning path",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
-                        "name": "session_id",
+                        "description": "Learning Path ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Session summary returned",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionSummary"
+                            "$ref": "#/definitions/domain.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/pronunciation/assess": {
-            "post": {
-                "description": "Accepts a target sentence and the user's recorded audio. It analyzes the user's speech against the target text and returns detailed feedback on their pronunciation. This is a multipart/form-data request.",
+        "/api/v1/learning/paths/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's progress for a specific learning path",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Pronunciation"
+                    "Learning"
                 ],
-                "summary": "Assess user's pronunciation",
+                "summary": "Get user progress for a learning path",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The exact sentence the user was asked to say.",
-                        "name": "target_text",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "The user's recorded audio file (e.g., in ogg, flac, or wav format).",
-                        "name": "audio_data",
-                        "in": "formData",
+                        "description": "Learning Path ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1331,85 +1494,83 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.PronunciationFeedback"
+                            "$ref": "#/definitions/domain.ProgressResponse"
                         }
                     },
                     "400": {
-                        "description": "Returns an error if the form data is invalid or missing.",
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Returns an error if the AI service fails during assessment.",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/pronunciation/sentence": {
-            "get": {
-                "description": "Dynamically generates a single, new English sentence tailored for pronunciation practice for Amharic speakers. Each request returns a unique sentence.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Pronunciation"
-                ],
-                "summary": "Get a practice sentence",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/entities.PracticeSentence"
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Returns an error if the AI service fails to generate a sentence.",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/me": {
-            "get": {
+        "/api/v1/learning/quizzes/{id}/submit": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the profile of the authenticated user",
+                "description": "Submit user's answers to a quiz for grading",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Learning"
                 ],
-                "summary": "Get user profile",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.User"
+                "summary": "Submit quiz answers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quiz ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quiz answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.QuizSubmissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.QuizResultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1418,33 +1579,61 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/pronunciation/activity": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allow a user to delete their account",
+                "description": "Record that a user completed a pronunciation practice session for streak tracking",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Pronunciation"
+                ],
+                "summary": "Record pronunciation practice activity",
+                "parameters": [
+                    {
+                        "description": "Pronunciation session data",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
                 ],
-                "summary": "Delete user account",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MessageResponse"
+                            "$ref": "#/definitions/domain.SuccessResponse"
                         }
                     },
                     "401": {
@@ -1453,21 +1642,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/streak/activity": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update parts of the user's profile (name, settings)",
+                "description": "Record a user activity to maintain their streak, for manual testing by admins. Features record their own activities; manual ones earn no badges or XP",
                 "consumes": [
                     "application/json"
                 ],
@@ -1475,25 +1666,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Streak"
                 ],
-                "summary": "Update user profile",
+                "summary": "Record activity (Internal)",
                 "parameters": [
                     {
-                        "description": "Profile Update Information",
-                        "name": "profileInfo",
-                        "in": "body",
-                        "required": true,
+                        "enum": [
+                            "lesson_completed",
+                            "quiz_passed",
+                            "daily_goal_met"
+                        ],
+                        "type": "string",
+                        "description": "Type of activity",
+                        "name": "activity_type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.UpdateProfileRequest"
+                            "$ref": "#/definitions/domain.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/streak/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get GitHub-like activity calendar showing daily learning activities, as JSON or as an SVG or PNG image shaded by activity count. Dates are days in the time zone from the user's settings; days without activity that a streak freeze covered are marked frozen",
+                "produces": [
+                    "application/json",
+                    "image/svg+xml",
+                    "image/png"
+                ],
+                "tags": [
+                    "Streak"
+                ],
+                "summary": "Get activity calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 2025,
+                        "description": "Year (default: current year)",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "svg",
+                            "png"
+                        ],
+                        "type": "string",
+                        "description": "json (default), or svg or png for an image of the calendar",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.ActivityCalendarResponse"
                         }
                     },
                     "400": {
@@ -1508,8 +1767,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
@@ -1517,14 +1776,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me/push-token": {
+        "/api/v1/streak/freeze": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Register a device token (FCM/APNs) for push notifications",
+                "description": "Use a streak freeze from the inventory for today, so the streak survives without practice today. Freezes are otherwise used automatically for missed days; they are earned at every 7-day milestone and granted monthly, up to the inventory size",
                 "consumes": [
                     "application/json"
                 ],
@@ -1532,17 +1791,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Streak"
                 ],
-                "summary": "Register push token",
+                "summary": "Freeze streak",
                 "parameters": [
                     {
-                        "description": "Push Token Information",
-                        "name": "tokenInfo",
+                        "description": "Freeze reason",
+                        "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.PushTokenRequest"
+                            "$ref": "#/definitions/domain.FreezeStreakRequest"
                         }
                     }
                 ],
@@ -1550,7 +1808,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MessageResponse"
+                            "$ref": "#/definitions/domain.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1564,163 +1822,2829 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/domain.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/ws/conversation": {
+        "/api/v1/streak/freezes": {
             "get": {
-                "description": "Establishes a WebSocket for a real-time, voice-based conversation with an AI. The connection automatically terminates after 3 minutes.\n\n### Conversation Lifecycle:\n1. **Connect**: The client establishes a WebSocket connection to this endpoint.\n2. **Speak**: The user speaks. The client continuously streams their voice as binary audio messages.\n3. **Pause**: The user stops speaking. After ~2-3 seconds of silence, the client sends a final text message.\n4. **Process**: The server receives the signal and immediately sends back a text message ` + "`" + `{\"status\": \"processing\"}` + "`" + `. The frontend UI should update to show this.\n5. **Respond**: The server, after finishing the AI processing, sends the AI's spoken response back as a single binary audio message. The frontend plays this audio.\n6. **Repeat**: The process repeats from step 2.\n7. **Timeout**: The connection is automatically and forcefully closed by the server after 3 minutes.\n\n### Client Responsibilities:\n- **Must** stream user's voice as raw ` + "`" + `BinaryMessage` + "`" + ` chunks.\n- **Must** implement silence detection (~2-3 seconds).\n- **Must** send a ` + "`" + `TextMessage` + "`" + ` with the JSON ` + "`" + `{\"type\": \"end_of_speech\"}` + "`" + ` after detecting silence.\n- **Must** handle incoming ` + "`" + `TextMessage` + "`" + ` status updates (e.g., ` + "`" + `{\"status\": \"processing\"}` + "`" + `) to update the UI.\n- **Must** be able to receive and play back ` + "`" + `BinaryMessage` + "`" + ` audio from the server.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the user's streak freeze inventory and the history of freezes earned and used, newest first",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Conversation"
+                    "Streak"
                 ],
-                "summary": "Real-time AI Voice Conversation",
+                "summary": "Get streak freezes",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/domain.StreakFreezesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.ActivityCalendarDay": {
-            "type": "object",
-            "properties": {
-                "activity_count": {
-                    "description": "Number of activities on this day",
-                    "type": "integer"
-                },
-                "activity_types": {
-                    "description": "Types of activities done",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "date": {
-                    "description": "YYYY-MM-DD format",
-                    "type": "string"
-                },
-                "has_activity": {
-                    "description": "Whether user was active on this day",
-                    "type": "boolean"
-                }
-            }
         },
-        "domain.ActivityCalendarResponse": {
-            "type": "object",
-            "properties": {
-                "active_days": {
-                    "type": "integer"
-                },
-                "current_streak": {
-                    "type": "integer"
-                },
-                "longest_streak": {
-                    "type": "integer"
-                },
-                "summary": {
-                    "$ref": "#/definitions/domain.ActivityCalendarSummary"
-                },
-                "total_days": {
-                    "type": "integer"
-                },
-                "weeks": {
-                    "type": "array",
-                    "items": {
+        "/api/v1/streak/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's streak information including current streak, longest streak, and freeze status. Days are counted in the time zone from the user's settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Streak"
+                ],
+                "summary": "Get user streak information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StreakInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Send password reset link to user's email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "User Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password. If the account has two-factor authentication enabled, the response only carries mfa_required and an mfa_token to exchange at /auth/mfa/verify.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "User Login Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invalidate user's session token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout user",
+                "parameters": [
+                    {
+                        "description": "Refresh Token (optional)",
+                        "name": "refreshToken",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Exchange the mfa_token from login and an authenticator or recovery code for access and refresh tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Complete two-factor login",
+                "parameters": [
+                    {
+                        "description": "MFA token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFAVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. The presented refresh token is rotated and must not be used again; replaying it revokes the whole session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "refreshToken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Creates a new user account with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User Registration Information",
+                        "name": "userInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
+                "description": "Send a new verification link to an unverified account's email address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend verification email",
+                "parameters": [
+                    {
+                        "description": "User Email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ResendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set new password using reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Password Reset Information",
+                        "name": "resetInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/social": {
+            "post": {
+                "description": "Authenticate or register user using social providers (Google, Apple, Facebook). The provider token is verified with the provider before any account is created or linked. Accounts with two-factor authentication get an mfa_token, as with password login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Social authentication",
+                "parameters": [
+                    {
+                        "description": "Social Authentication Information",
+                        "name": "socialInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SocialAuthRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Confirm the user's email address using the token from the verification email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "description": "Verification Token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/edit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Corrects and improves a user's drafted email to make it more professional. Signing in is optional unless verified emails are required; signed-in drafts count as activity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email"
+                ],
+                "summary": "Edit an existing email",
+                "parameters": [
+                    {
+                        "description": "The user's email draft and optional tone/template.",
+                        "name": "editRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.EditEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmailResponse"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT, MISS, or SHARED with a concurrent identical request"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token, or none when verified emails are required",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/email/generate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a complete, professional email from a user's prompt (which can be in English or Amharic). Signing in is optional unless verified emails are required; signed-in drafts count as activity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email"
+                ],
+                "summary": "Generate a new email",
+                "parameters": [
+                    {
+                        "description": "The user's prompt and optional tone/template.",
+                        "name": "generateRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.GenerateEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmailResponse"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT, MISS, or SHARED with a concurrent identical request"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token, or none when verified emails are required",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/grammar/check": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Analyzes text for grammatical errors and returns corrections and explanations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Grammar"
+                ],
+                "summary": "Check Grammar",
+                "parameters": [
+                    {
+                        "description": "Text to be checked",
+                        "name": "text",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GrammarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns corrected text and explanation",
+                        "schema": {
+                            "$ref": "#/definitions/models.GrammarResponse"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT, MISS, or SHARED with a concurrent identical request"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "The AI kept returning invalid output",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/interview/answer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit an answer for the current session question",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interview"
+                ],
+                "summary": "Submit user's answer",
+                "parameters": [
+                    {
+                        "description": "Answer input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubmitAnswerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Answer submitted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feedback"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "The AI kept returning invalid output",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/interview/question": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the next question for the current session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interview"
+                ],
+                "summary": "Get the next interview question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Next question returned",
+                        "schema": {
+                            "$ref": "#/definitions/models.NextQuestionReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/interview/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new interview session for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interview"
+                ],
+                "summary": "Start a new interview session",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionReturn"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/interview/{session_id}/end": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends the session and returns the final summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interview"
+                ],
+                "summary": "End an interview session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session summary returned",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboards/friends": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rank the authenticated user and their friends by the XP earned this week. Friends who hide themselves from leaderboards are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Get friends leaderboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FriendsLeaderboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboards/league": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get this week's leaderboard of the authenticated user's league group, which they join with their first XP of the week. When the week ends the ranks up to promotion_rank move up a league and those from demotion_rank move down. Users who hide themselves from leaderboards get no entries.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Get league leaderboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LeagueLeaderboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pronunciation/assess": {
+            "post": {
+                "description": "Accepts a target sentence and the user's recorded audio. It analyzes the user's speech against the target text and returns detailed feedback on their pronunciation. This is a multipart/form-data request.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pronunciation"
+                ],
+                "summary": "Assess user's pronunciation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The exact sentence the user was asked to say.",
+                        "name": "target_text",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "The user's recorded audio file (e.g., in ogg, flac, or wav format).",
+                        "name": "audio_data",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PronunciationFeedback"
+                        }
+                    },
+                    "400": {
+                        "description": "Returns an error if the form data is invalid or missing.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Returns an error if the AI service fails during assessment.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Returns an error if the AI kept returning invalid output.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/pronunciation/sentence": {
+            "get": {
+                "description": "Dynamically generates a single, new English sentence tailored for pronunciation practice for Amharic speakers. Each request returns a unique sentence.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pronunciation"
+                ],
+                "summary": "Get a practice sentence",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PracticeSentence"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT, MISS, or SHARED with a concurrent identical request"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Returns an error if the AI service fails to generate a sentence.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the profile of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the authenticated user's account and all data stored about it: interview sessions, learning progress, quiz submissions, streak activity, devices and tokens. When a grace period is configured the deletion is only scheduled and every device is logged out; logging in again and calling /users/me/restore before the date cancels it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AccountDeletionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update parts of the user's profile (name, handle, settings). Only the settings fields present are changed; invalid values are rejected. The handle names the public profile at /u/{handle}, shown when settings.public_profile is on; it is 3-30 letters, digits or underscores and unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile Update Information",
+                        "name": "profileInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/achievements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every achievement badge with whether the authenticated user earned it and their progress towards it. Titles and descriptions are in the user's language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get achievements",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AchievementsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download everything stored about the authenticated user as a JSON document, or as a ZIP archive with one JSON file per collection. Password hashes, two-factor secrets and one-time tokens are left out.",
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Export my data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Archive format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/friends": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's friends, the friend requests sent to them and the requests they sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "List friends",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FriendsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ask the user with this email to be friends. If they already asked the authenticated user, their request is accepted instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Send friend request",
+                "parameters": [
+                    {
+                        "description": "Email of the user to add",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.FriendRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Friendship"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/friends/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a friend, or decline or withdraw a friend request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Remove friend",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Friendship ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/friends/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept a friend request sent to the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Accept friend request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Friendship ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Friendship"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn on two-factor authentication with a code from the authenticator app. Returns the recovery codes; they are shown only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirm two-factor enrollment",
+                "parameters": [
+                    {
+                        "description": "Authenticator code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires a current authenticator code or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Turn off two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Authenticator or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a TOTP secret and the otpauth:// provisioning URI to show as a QR code. Nothing changes until the enrollment is confirmed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Start two-factor enrollment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFAEnrollResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace all recovery codes. Requires a current authenticator code or a recovery code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "Authenticator or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/push-token": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a device token for push notifications. Provider says who issued it: \"fcm\" (default) or \"apns\" for iOS apps registering with APNs directly",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Register push token",
+                "parameters": [
+                    {
+                        "description": "Push Token Information",
+                        "name": "tokenInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PushTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop sending push notifications to a device. Without a token in the body, the token registered by the current device is removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unregister push token",
+                "parameters": [
+                    {
+                        "description": "Push token to remove",
+                        "name": "tokenInfo",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RemovePushTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a scheduled account deletion during its grace period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Restore account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the devices the authenticated user is logged in on. The session of the calling token is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List active sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.SessionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every session of the authenticated user except the one making the request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out everywhere else",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log out a single device. Its refresh tokens stop working and its push token is removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's settings. Users who never changed them get the defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change some of the authenticated user's settings. Only the fields present are changed. Language is en or am, daily goal 5-240 minutes, reminder time HH:MM in the user's time zone (empty turns reminders off), time zone an IANA name, CEFR level A1-C2, TTS voice female or male, reminder channel auto, push or email, quiet hours HH:MM start and end (both empty turns them off), and hide_from_leaderboards keeps the user out of leagues and friends' leaderboards. public_profile publishes streaks, badges and the activity calendar at /u/{handle} once the user has a handle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update settings",
+                "parameters": [
+                    {
+                        "description": "Settings to change",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/xp": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's total XP, the XP earned this week, their league and their 20 most recent XP awards. Weeks start on Monday at midnight UTC.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboards"
+                ],
+                "summary": "Get XP",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.XPSummaryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/conversation": {
+            "get": {
+                "description": "Establishes a WebSocket for a real-time, voice-based conversation with an AI. The connection automatically terminates after 3 minutes.\n\n### Conversation Lifecycle:\n1. **Connect**: The client establishes a WebSocket connection to this endpoint.\n2. **Speak**: The user speaks. The client continuously streams their voice as binary audio messages.\n3. **Pause**: The user stops speaking. After ~2-3 seconds of silence, the client sends a final text message.\n4. **Process**: The server receives the signal and immediately sends back a text message ` + "`" + `{\"status\": \"processing\"}` + "`" + `. The frontend UI should update to show this.\n5. **Respond**: The server, after finishing the AI processing, sends the AI's spoken response back as a single binary audio message. The frontend plays this audio.\n6. **Repeat**: The process repeats from step 2.\n7. **Timeout**: The connection is automatically and forcefully closed by the server after 3 minutes.\n\n### Client Responsibilities:\n- **Must** stream user's voice as raw ` + "`" + `BinaryMessage` + "`" + ` chunks.\n- **Must** implement silence detection (~2-3 seconds).\n- **Must** send a ` + "`" + `TextMessage` + "`" + ` with the JSON ` + "`" + `{\"type\": \"end_of_speech\"}` + "`" + ` after detecting silence.\n- **Must** handle incoming ` + "`" + `TextMessage` + "`" + ` status updates (e.g., ` + "`" + `{\"status\": \"processing\"}` + "`" + `) to update the UI.\n- **Must** be able to receive and play back ` + "`" + `BinaryMessage` + "`" + ` audio from the server.",
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Real-time AI Voice Conversation",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "deletion_scheduled_for": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Account scheduled for deletion"
+                }
+            }
+        },
+        "domain.AchievementStatus": {
+            "type": "object",
+            "properties": {
+                "awarded_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Practice 7 days in a row"
+                },
+                "earned": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "🔥"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "streak_7"
+                },
+                "progress": {
+                    "description": "towards Target, at most Target",
+                    "type": "integer",
+                    "example": 4
+                },
+                "target": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Week Streak"
+                }
+            }
+        },
+        "domain.AchievementsResponse": {
+            "type": "object",
+            "properties": {
+                "achievements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AchievementStatus"
+                    }
+                },
+                "earned": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "domain.ActivityCalendarDay": {
+            "type": "object",
+            "properties": {
+                "activity_count": {
+                    "description": "Number of activities on this day",
+                    "type": "integer"
+                },
+                "activity_types": {
+                    "description": "Types of activities done",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "date": {
+                    "description": "YYYY-MM-DD format",
+                    "type": "string"
+                },
+                "frozen": {
+                    "description": "A streak freeze covered this day",
+                    "type": "boolean"
+                },
+                "has_activity": {
+                    "description": "Whether user was active on this day",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.ActivityCalendarResponse": {
+            "type": "object",
+            "properties": {
+                "active_days": {
+                    "type": "integer"
+                },
+                "current_streak": {
+                    "type": "integer"
+                },
+                "frozen_days": {
+                    "description": "Days covered by a streak freeze",
+                    "type": "integer"
+                },
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/domain.ActivityCalendarSummary"
+                },
+                "total_days": {
+                    "type": "integer"
+                },
+                "weeks": {
+                    "type": "array",
+                    "items": {
                         "$ref": "#/definitions/domain.ActivityCalendarWeek"
                     }
                 },
-                "year": {
-                    "type": "integer"
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ActivityCalendarSummary": {
+            "type": "object",
+            "properties": {
+                "activity_breakdown": {
+                    "description": "Count by activity type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "consecutive_weeks": {
+                    "description": "Weeks with at least one activity",
+                    "type": "integer"
+                },
+                "most_active_count": {
+                    "description": "Max activities in a single day",
+                    "type": "integer"
+                },
+                "most_active_day": {
+                    "description": "Date with most activities",
+                    "type": "string"
+                },
+                "total_activities": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ActivityCalendarWeek": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ActivityCalendarDay"
+                    }
+                }
+            }
+        },
+        "domain.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "user.suspend"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "failed": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "domain.AuditLogListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuditLog"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/domain.User"
+                }
+            }
+        },
+        "domain.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@lissanai.com"
+                }
+            }
+        },
+        "domain.FreezeStreakRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Vacation"
+                }
+            }
+        },
+        "domain.Friend": {
+            "type": "object",
+            "properties": {
+                "friendship_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Abebe"
+                },
+                "since": {
+                    "description": "accepted or, for requests, sent",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FriendRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "friend@example.com"
+                }
+            }
+        },
+        "domain.FriendsLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LeaderboardEntry"
+                    }
+                },
+                "week": {
+                    "type": "string",
+                    "example": "2026-W42"
                 }
             }
         },
-        "domain.ActivityCalendarSummary": {
+        "domain.FriendsResponse": {
             "type": "object",
             "properties": {
-                "activity_breakdown": {
-                    "description": "Count by activity type",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Friend"
                     }
                 },
-                "consecutive_weeks": {
-                    "description": "Weeks with at least one activity",
-                    "type": "integer"
+                "incoming": {
+                    "description": "requests to accept",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Friend"
+                    }
                 },
-                "most_active_count": {
-                    "description": "Max activities in a single day",
-                    "type": "integer"
+                "outgoing": {
+                    "description": "requests awaiting the other user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Friend"
+                    }
+                }
+            }
+        },
+        "domain.Friendship": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
                 },
-                "most_active_day": {
-                    "description": "Date with most activities",
+                "addressee_id": {
                     "type": "string"
                 },
-                "total_activities": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "requester_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.JobRun": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
                     "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "job": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "trigger": {
+                    "type": "string",
+                    "enum": [
+                        "schedule",
+                        "manual"
+                    ]
+                },
+                "triggered_by": {
+                    "description": "admin user ID for manual runs",
+                    "type": "string"
                 }
             }
         },
-        "domain.ActivityCalendarWeek": {
+        "domain.LeaderboardEntry": {
             "type": "object",
             "properties": {
-                "days": {
+                "is_you": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Abebe"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "xp": {
+                    "type": "integer",
+                    "example": 320
+                }
+            }
+        },
+        "domain.LeagueLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "demotion_rank": {
+                    "type": "integer",
+                    "example": 26
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "empty until the user earns XP this week",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.ActivityCalendarDay"
+                        "$ref": "#/definitions/domain.LeaderboardEntry"
                     }
+                },
+                "hidden_by_user": {
+                    "description": "the user opted out of leaderboards",
+                    "type": "boolean"
+                },
+                "last_week": {
+                    "$ref": "#/definitions/domain.LeagueMembership"
+                },
+                "league": {
+                    "type": "string",
+                    "example": "silver"
+                },
+                "promotion_rank": {
+                    "description": "Ranks up to PromotionRank move up a league and ranks from DemotionRank\nmove down; 0 when nobody does",
+                    "type": "integer",
+                    "example": 5
+                },
+                "week": {
+                    "type": "string",
+                    "example": "2026-W42"
                 }
             }
         },
-        "domain.AuthResponse": {
+        "domain.LeagueMembership": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "league": {
                     "type": "string"
                 },
-                "expires_in": {
+                "rank": {
                     "type": "integer"
                 },
-                "refresh_token": {
+                "result": {
+                    "type": "string",
+                    "enum": [
+                        "promoted",
+                        "stayed",
+                        "demoted"
+                    ]
+                },
+                "week": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/domain.User"
+                "xp": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.ErrorResponse": {
+        "domain.LearningPath": {
             "type": "object",
             "properties": {
-                "error": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lesson_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "description": "beginner, intermediate, advanced",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "domain.ForgotPasswordRequest": {
+        "domain.LearningPathRequest": {
             "type": "object",
             "required": [
-                "email"
+                "level",
+                "title"
             ],
             "properties": {
-                "email": {
+                "category": {
                     "type": "string",
-                    "example": "john@lissanai.com"
-                }
-            }
-        },
-        "domain.FreezeStreakRequest": {
-            "type": "object",
-            "properties": {
-                "reason": {
+                    "example": "interview"
+                },
+                "description": {
                     "type": "string",
-                    "example": "Vacation"
+                    "example": "Prepare for English job interviews"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "beginner",
+                        "intermediate",
+                        "advanced"
+                    ],
+                    "example": "intermediate"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Job Interview English"
                 }
             }
         },
@@ -1768,8 +4692,94 @@ const docTemplate = `{
                 "updated_at": {
                     "type": "string"
                 },
-                "user_progress": {
-                    "type": "number"
+                "user_progress": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Lesson": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order": {
+                    "type": "integer"
+                },
+                "path_id": {
+                    "type": "string"
+                },
+                "quiz_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "video, text, quiz, exercise",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LessonRequest": {
+            "type": "object",
+            "required": [
+                "path_id",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Learn to answer 'Tell me about yourself'"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 15
+                },
+                "order": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "path_id": {
+                    "type": "string",
+                    "example": "68a85f301197d981baa0f301"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Introducing Yourself"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "video",
+                        "text",
+                        "quiz",
+                        "exercise"
+                    ],
+                    "example": "text"
                 }
             }
         },
@@ -1826,6 +4836,10 @@ const docTemplate = `{
                 "password"
             ],
             "properties": {
+                "device_name": {
+                    "type": "string",
+                    "example": "Abebe's iPhone"
+                },
                 "email": {
                     "type": "string",
                     "example": "john@lissanai.com"
@@ -1833,6 +4847,102 @@ const docTemplate = `{
                 "password": {
                     "type": "string",
                     "example": "strongpassword123"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                }
+            }
+        },
+        "domain.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "mfa_expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/domain.User"
+                }
+            }
+        },
+        "domain.MFACodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "domain.MFAEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/LissanAI:john@lissanai.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=LissanAI"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "domain.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k7m2q-x9rtp",
+                        "a3vbn-w8hcz"
+                    ]
+                }
+            }
+        },
+        "domain.MFAVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "device_name": {
+                    "type": "string",
+                    "example": "Abebe's iPhone"
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
                 }
             }
         },
@@ -1876,6 +4986,140 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.Prompt": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "rollout": {
+                    "$ref": "#/definitions/domain.PromptRollout"
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PromptTemplate"
+                    }
+                }
+            }
+        },
+        "domain.PromptExperiment": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "grammar-amharic-explanations"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PromptVariant"
+                    }
+                }
+            }
+        },
+        "domain.PromptRollout": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "$ref": "#/definitions/domain.PromptExperiment"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "grammar"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "domain.PromptRolloutRequest": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "$ref": "#/definitions/domain.PromptExperiment"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "domain.PromptTemplate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "am"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "grammar"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "file",
+                        "mongo"
+                    ]
+                },
+                "system": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "domain.PromptTemplateRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "locale": {
+                    "type": "string",
+                    "example": "am"
+                },
+                "system": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "domain.PromptVariant": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "weight": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
         "domain.PushTokenRequest": {
             "type": "object",
             "required": [
@@ -1887,6 +5131,14 @@ const docTemplate = `{
                     "type": "string",
                     "example": "ios"
                 },
+                "provider": {
+                    "type": "string",
+                    "enum": [
+                        "fcm",
+                        "apns"
+                    ],
+                    "example": "fcm"
+                },
                 "token": {
                     "type": "string",
                     "example": "fcm_token_123"
@@ -1946,6 +5198,31 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.QuizRequest": {
+            "type": "object",
+            "required": [
+                "lesson_id",
+                "questions",
+                "title"
+            ],
+            "properties": {
+                "lesson_id": {
+                    "type": "string",
+                    "example": "68a85f301197d981baa0f302"
+                },
+                "questions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/domain.Question"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Self Introduction Check"
+                }
+            }
+        },
         "domain.QuizResultResponse": {
             "type": "object",
             "properties": {
@@ -2009,6 +5286,14 @@ const docTemplate = `{
                 "refresh_token"
             ],
             "properties": {
+                "device_name": {
+                    "type": "string",
+                    "example": "Abebe's iPhone"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                },
                 "refresh_token": {
                     "type": "string",
                     "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
@@ -2023,6 +5308,10 @@ const docTemplate = `{
                 "password"
             ],
             "properties": {
+                "device_name": {
+                    "type": "string",
+                    "example": "Abebe's iPhone"
+                },
                 "email": {
                     "type": "string",
                     "example": "john@lissanai.com"
@@ -2035,24 +5324,132 @@ const docTemplate = `{
                     "type": "string",
                     "minLength": 8,
                     "example": "strongpassword123"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                }
+            }
+        },
+        "domain.RemovePushTokenRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "fcm_token_123"
+                }
+            }
+        },
+        "domain.ResendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@lissanai.com"
+                }
+            }
+        },
+        "domain.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "newstrongpassword123"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "reset_token_123"
+                }
+            }
+        },
+        "domain.ScheduledJob": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "last_status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "lease_owner": {
+                    "description": "instance running it now",
+                    "type": "string"
+                },
+                "lease_until": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "streak-expiry"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "recent_runs": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.JobRun"
+                    }
+                },
+                "schedule": {
+                    "description": "cron expression, UTC",
+                    "type": "string",
+                    "example": "0 * * * *"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "domain.ResetPasswordRequest": {
+        "domain.SessionResponse": {
             "type": "object",
-            "required": [
-                "new_password",
-                "token"
-            ],
             "properties": {
-                "new_password": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "device_name": {
                     "type": "string",
-                    "minLength": 8,
-                    "example": "newstrongpassword123"
+                    "example": "Abebe's iPhone"
                 },
-                "token": {
+                "id": {
                     "type": "string",
-                    "example": "reset_token_123"
+                    "example": "68a85f301197d981baa0f301"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "196.188.12.34"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                },
+                "user_agent": {
+                    "type": "string"
                 }
             }
         },
@@ -2065,22 +5462,91 @@ const docTemplate = `{
             "properties": {
                 "access_token": {
                     "type": "string",
-                    "example": "ya29.a0AfH6SMC..."
+                    "example": "eyJhbGciOiJSUzI1NiIsImtpZCI6..."
+                },
+                "device_name": {
+                    "type": "string",
+                    "example": "Abebe's iPhone"
                 },
                 "email": {
+                    "description": "Deprecated: ignored, the verified token email is used",
                     "type": "string",
                     "example": "john@lissanai.com"
                 },
                 "name": {
+                    "description": "Used only when the provider does not share a name (Apple)",
                     "type": "string",
                     "example": "John Doe"
                 },
+                "platform": {
+                    "type": "string",
+                    "example": "ios"
+                },
                 "provider": {
                     "type": "string",
+                    "enum": [
+                        "google",
+                        "apple",
+                        "facebook"
+                    ],
                     "example": "google"
                 }
             }
         },
+        "domain.StreakFreeze": {
+            "type": "object",
+            "properties": {
+                "earned_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "description": "reason given for a manual freeze",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "milestone",
+                        "monthly"
+                    ]
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "used_on": {
+                    "description": "the frozen day",
+                    "type": "string"
+                },
+                "used_reason": {
+                    "type": "string",
+                    "enum": [
+                        "missed_day",
+                        "manual"
+                    ]
+                }
+            }
+        },
+        "domain.StreakFreezesResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "freezes": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StreakFreeze"
+                    }
+                },
+                "max": {
+                    "type": "integer"
+                }
+            }
+        },
         "domain.StreakInfo": {
             "type": "object",
             "properties": {
@@ -2091,11 +5557,17 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "days_until_loss": {
+                    "description": "days that can still be missed, counting freezes",
                     "type": "integer"
                 },
-                "freeze_count": {
+                "freezes_available": {
+                    "description": "freezes in the inventory",
                     "type": "integer"
                 },
+                "frozen_until": {
+                    "description": "last day covered by a freeze",
+                    "type": "string"
+                },
                 "last_activity_date": {
                     "type": "string"
                 },
@@ -2103,9 +5575,11 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "max_freezes": {
+                    "description": "inventory size",
                     "type": "integer"
                 },
                 "streak_frozen": {
+                    "description": "a freeze is keeping the streak alive",
                     "type": "boolean"
                 }
             }
@@ -2118,6 +5592,18 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.SuspendUserRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Abusive behaviour in interview sessions"
+                }
+            }
+        },
         "domain.TokenResponse": {
             "type": "object",
             "properties": {
@@ -2126,19 +5612,98 @@ const docTemplate = `{
                 },
                 "expires_in": {
                     "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
                 }
             }
         },
         "domain.UpdateProfileRequest": {
             "type": "object",
             "properties": {
+                "handle": {
+                    "description": "public profile handle; can be changed but not removed",
+                    "type": "string",
+                    "example": "john_doe"
+                },
                 "name": {
                     "type": "string",
                     "example": "John Updated"
                 },
                 "settings": {
-                    "type": "object",
-                    "additionalProperties": true
+                    "$ref": "#/definitions/domain.UpdateSettingsRequest"
+                }
+            }
+        },
+        "domain.UpdateRolesRequest": {
+            "type": "object",
+            "required": [
+                "roles"
+            ],
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "learner",
+                        "content_editor"
+                    ]
+                }
+            }
+        },
+        "domain.UpdateSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "cefr_level": {
+                    "type": "string",
+                    "example": "B1"
+                },
+                "daily_goal_minutes": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "hide_from_leaderboards": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "type": "string",
+                    "example": "am"
+                },
+                "public_profile": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "quiet_hours_end": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "quiet_hours_start": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "reminder_channel": {
+                    "type": "string",
+                    "example": "push"
+                },
+                "reminder_time": {
+                    "type": "string",
+                    "example": "19:30"
+                },
+                "target_job_role": {
+                    "type": "string",
+                    "example": "Software Engineer"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Africa/Addis_Ababa"
+                },
+                "tts_voice": {
+                    "type": "string",
+                    "example": "female"
                 }
             }
         },
@@ -2152,11 +5717,25 @@ const docTemplate = `{
                     "description": "Streak System",
                     "type": "integer"
                 },
+                "deletion_requested_at": {
+                    "description": "Account deletion. During the grace period the account can still log in\nand be restored; after DeletionScheduledFor all its data is purged.",
+                    "type": "string"
+                },
+                "deletion_scheduled_for": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
-                "freeze_count": {
-                    "type": "integer"
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "email_verified_at": {
+                    "type": "string"
+                },
+                "handle": {
+                    "description": "Unique, names the public profile",
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
@@ -2164,24 +5743,220 @@ const docTemplate = `{
                 "last_activity_date": {
                     "type": "string"
                 },
+                "league": {
+                    "type": "string"
+                },
                 "longest_streak": {
                     "type": "integer"
                 },
+                "mfa_enabled": {
+                    "description": "Two-factor authentication (TOTP). Recovery codes are stored as hashes.",
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
                 "provider": {
                     "type": "string"
                 },
+                "roles": {
+                    "description": "Access control",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "settings": {
-                    "type": "object",
-                    "additionalProperties": true
+                    "$ref": "#/definitions/domain.UserSettings"
                 },
-                "streak_frozen": {
+                "streak_frozen_until": {
+                    "description": "Last missed day covered by a streak freeze; the streak is kept through\nthe later of this and LastActivityDate",
+                    "type": "string"
+                },
+                "suspended": {
                     "type": "boolean"
                 },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "suspension_reason": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "xp": {
+                    "description": "XP earned in total, and the league the user competes in each week\n(empty until their first weekly rollover means the lowest league)",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.UserSearchResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.User"
+                    }
+                }
+            }
+        },
+        "domain.UserSettings": {
+            "type": "object",
+            "properties": {
+                "cefr_level": {
+                    "description": "empty until assessed",
+                    "type": "string",
+                    "enum": [
+                        "A1",
+                        "A2",
+                        "B1",
+                        "B2",
+                        "C1",
+                        "C2"
+                    ],
+                    "example": "B1"
+                },
+                "daily_goal_minutes": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "hide_from_leaderboards": {
+                    "description": "Keeps the user out of leagues and friends' leaderboards; XP is still earned",
+                    "type": "boolean"
+                },
+                "language": {
+                    "description": "UI language",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "am"
+                    ],
+                    "example": "am"
+                },
+                "public_profile": {
+                    "description": "Publishes the user's streaks, badges and activity calendar at\n/u/{handle}; needs a handle",
+                    "type": "boolean"
+                },
+                "quiet_hours_end": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "quiet_hours_start": {
+                    "description": "No notifications are sent between these local times (HH:MM, may wrap\npast midnight); both empty disables quiet hours",
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "reminder_channel": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "push",
+                        "email"
+                    ],
+                    "example": "auto"
+                },
+                "reminder_time": {
+                    "description": "HH:MM local time; empty disables reminders",
+                    "type": "string",
+                    "example": "19:30"
+                },
+                "target_job_role": {
+                    "type": "string",
+                    "example": "Software Engineer"
+                },
+                "time_zone": {
+                    "description": "IANA name",
+                    "type": "string",
+                    "example": "Africa/Addis_Ababa"
+                },
+                "tts_voice": {
+                    "type": "string",
+                    "enum": [
+                        "female",
+                        "male"
+                    ],
+                    "example": "female"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "domain.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "verification_token_123"
+                }
+            }
+        },
+        "domain.XPEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "reference": {
+                    "description": "quiz ID, interview question or day/award number",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "activity type or XPSource*",
+                    "type": "string",
+                    "example": "lesson_completed"
+                },
+                "week": {
+                    "description": "ISO week, UTC",
+                    "type": "string",
+                    "example": "2026-W42"
+                }
+            }
+        },
+        "domain.XPSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "league": {
+                    "type": "string",
+                    "example": "silver"
+                },
+                "recent": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.XPEvent"
+                    }
+                },
+                "this_week": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "week": {
+                    "type": "string",
+                    "example": "2026-W42"
                 }
             }
         },
@@ -2208,6 +5983,9 @@ const docTemplate = `{
                 "body": {
                     "type": "string"
                 },
+                "prompt_version": {
+                    "type": "string"
+                },
                 "subject": {
                     "type": "string"
                 }
@@ -2236,6 +6014,9 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "prompt_version": {
+                    "type": "string"
+                },
                 "text": {
                     "type": "string"
                 }
@@ -2254,7 +6035,12 @@ const docTemplate = `{
                     }
                 },
                 "overall_accuracy_score": {
-                    "type": "number"
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "prompt_version": {
+                    "type": "string"
                 }
             }
         },
@@ -2315,8 +6101,17 @@ const docTemplate = `{
                 "overall_summary": {
                     "type": "string"
                 },
+                "prompt_version": {
+                    "description": "Prompt template that produced the feedback, e.g. \"interview_feedback@v2\"",
+                    "type": "string"
+                },
+                "rubric": {
+                    "$ref": "#/definitions/models.ScoreRubric"
+                },
                 "score_percentage": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
                 }
             }
         },
@@ -2330,8 +6125,12 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "type": {
-                    "description": "grammar, pronunciation, structure",
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "grammar",
+                        "pronunciation",
+                        "structure"
+                    ]
                 }
             }
         },
@@ -2347,6 +6146,10 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/models.Correction"
                     }
+                },
+                "prompt_version": {
+                    "type": "string",
+                    "example": "grammar@v1"
                 }
             }
         },
@@ -2358,6 +6161,31 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ScoreRubric": {
+            "type": "object",
+            "properties": {
+                "clarity": {
+                    "type": "integer",
+                    "maximum": 25,
+                    "minimum": 0
+                },
+                "fluency": {
+                    "type": "integer",
+                    "maximum": 25,
+                    "minimum": 0
+                },
+                "grammar": {
+                    "type": "integer",
+                    "maximum": 25,
+                    "minimum": 0
+                },
+                "relevance": {
+                    "type": "integer",
+                    "maximum": 25,
+                    "minimum": 0
+                }
+            }
+        },
         "models.SessionReturn": {
             "type": "object",
             "properties": {
@@ -2382,6 +6210,9 @@ const docTemplate = `{
                     "description": "out of 100",
                     "type": "integer"
                 },
+                "prompt_version": {
+                    "type": "string"
+                },
                 "session_id": {
                     "type": "string"
                 },
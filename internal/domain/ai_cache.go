@@ -0,0 +1,14 @@
+// internal/domain/ai_cache.go
+package domain
+
+import "time"
+
+// AICacheEntry is a cached AI reply, stored as JSON under a key derived
+// from everything that determines it.
+type AICacheEntry struct {
+	Key       string    `bson:"_id"`
+	Feature   string    `bson:"feature"`
+	Value     string    `bson:"value"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"` // TTL; the entry is dropped after this
+}
@@ -13,8 +13,9 @@ type EditEmailRequest struct {
 }
 
 type EmailResponse struct {
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
+	Subject       string `json:"subject"`
+	Body          string `json:"body"`
+	PromptVersion string `json:"prompt_version,omitempty" llm:"-"`
 }
 
 type Correction struct {
@@ -24,7 +25,8 @@ type Correction struct {
 }
 
 type EditEmailResponse struct {
-	Subject     string       `json:"subject"`
-	Body        string       `json:"body"`
-	Corrections []Correction `json:"corrections"`
+	Subject       string       `json:"subject"`
+	Body          string       `json:"body"`
+	Corrections   []Correction `json:"corrections"`
+	PromptVersion string       `json:"prompt_version,omitempty" llm:"-"`
 }
@@ -2,13 +2,15 @@ package entities
 
 // PracticeSentence is the data for the "Listen" feature, providing pre-generated audio.
 type PracticeSentence struct {
-	ID   string `json:"id"`
-	Text string `json:"text"`
+	ID            string `json:"id"`
+	Text          string `json:"text"`
+	PromptVersion string `json:"prompt_version,omitempty"`
 }
 
 // PronunciationFeedback is the final, structured response for a user's attempt.
 type PronunciationFeedback struct {
-	OverallAccuracyScore float32  `json:"overall_accuracy_score"`
+	OverallAccuracyScore float32  `json:"overall_accuracy_score" minimum:"0" maximum:"100"`
 	MispronouncedWords   []string `json:"mispronouncedwords"`
 	FullFeedbackSummary  string   `json:"full_feedback_summary"`
+	PromptVersion        string   `json:"prompt_version,omitempty" llm:"-"`
 }
@@ -1,6 +1,10 @@
 package interfaces
 
-import "lissanai.com/backend/internal/domain/models"
+import (
+	"context"
+
+	"lissanai.com/backend/internal/domain/models"
+)
 
 // SessionRepository defines operations for sessions
 type SessionRepository interface {
@@ -24,9 +28,9 @@ type AiService interface {
 
 	// GenerateFeedback analyzes the user's answer and returns
 	// structured feedback (grammar, pronunciation, clarity, etc).
-	GenerateFeedback(sessionID string, question string, answer string) (*models.Feedback, error)
+	GenerateFeedback(ctx context.Context, sessionID string, question string, answer string) (*models.Feedback, error)
 
 	// SummarizeSession creates a final session summary
 	// including strengths, weaknesses, and overall score.
-	SummarizeSession(session *models.Session, Messages []models.Message) (*models.SessionSummary, error)
+	SummarizeSession(ctx context.Context, session *models.Session, Messages []models.Message) (*models.SessionSummary, error)
 }
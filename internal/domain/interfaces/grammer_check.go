@@ -1,7 +1,11 @@
 package interfaces
 
-import "lissanai.com/backend/internal/domain/models"
+import (
+	"context"
+
+	"lissanai.com/backend/internal/domain/models"
+)
 
 type AiServiceInterface interface {
-	CheckGrammar(text string) (*models.GrammarResponse, error)
+	CheckGrammar(ctx context.Context, text string) (*models.GrammarResponse, error)
 }
@@ -2,53 +2,130 @@
 package domain
 
 import (
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"errors"
+	"regexp"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ClientInfo describes the device a session is started or used from.
+// DeviceName and Platform are sent by the client; IPAddress and UserAgent are
+// filled in by the handler from the HTTP request.
+type ClientInfo struct {
+	DeviceName string `json:"device_name,omitempty" example:"Abebe's iPhone"`
+	Platform   string `json:"platform,omitempty" example:"ios"`
+	IPAddress  string `json:"-"`
+	UserAgent  string `json:"-"`
+}
+
 // Auth requests
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"john@lissanai.com"`
 	Password string `json:"password" binding:"required,min=8" example:"strongpassword123"`
+	ClientInfo
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"john@lissanai.com"`
 	Password string `json:"password" binding:"required" example:"strongpassword123"`
+	ClientInfo
 }
 
+// SocialAuthRequest carries the provider token to verify. For Google and Apple
+// this is the OpenID Connect ID token; for Facebook it is the user access token.
+// Identity (subject, email) is always taken from the verified token.
 type SocialAuthRequest struct {
-	Provider    string `json:"provider" binding:"required" example:"google"`
-	AccessToken string `json:"access_token" binding:"required" example:"ya29.a0AfH6SMC..."`
-	Name        string `json:"name,omitempty" example:"John Doe"`
-	Email       string `json:"email,omitempty" example:"john@lissanai.com"`
+	Provider    string `json:"provider" binding:"required,oneof=google apple facebook" example:"google"`
+	AccessToken string `json:"access_token" binding:"required" example:"eyJhbGciOiJSUzI1NiIsImtpZCI6..."`
+	Name        string `json:"name,omitempty" example:"John Doe"`           // Used only when the provider does not share a name (Apple)
+	Email       string `json:"email,omitempty" example:"john@lissanai.com"` // Deprecated: ignored, the verified token email is used
+	ClientInfo
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ClientInfo
 }
 
 type ForgotPasswordRequest struct {
-	Email string `json:"email" binding:"required,email" example:"john@lissanai.com"`
+	Email     string `json:"email" binding:"required,email" example:"john@lissanai.com"`
+	IPAddress string `json:"-"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"verification_token_123"`
+}
+
+type ResendVerificationRequest struct {
+	Email     string `json:"email" binding:"required,email" example:"john@lissanai.com"`
+	IPAddress string `json:"-"`
 }
 
 type ResetPasswordRequest struct {
 	Token       string `json:"token" binding:"required" example:"reset_token_123"`
 	NewPassword string `json:"new_password" binding:"required,min=8" example:"newstrongpassword123"`
+	IPAddress   string `json:"-"`
 }
 
 type UpdateProfileRequest struct {
 	Name     *string                `json:"name,omitempty" example:"John Updated"`
-	Settings map[string]interface{} `json:"settings,omitempty"`
+	Handle   *string                `json:"handle,omitempty" example:"john_doe"` // public profile handle; can be changed but not removed
+	Settings *UpdateSettingsRequest `json:"settings,omitempty"`
 }
 
+// PushTokenRequest registers a device token. Provider is the service that
+// issued it, "fcm" (the default) or "apns" for iOS apps registering with
+// APNs directly.
 type PushTokenRequest struct {
-	Token    string `json:"token" binding:"required" example:"fcm_token_123"`
-	Platform string `json:"platform" binding:"required" example:"ios"`
+	Token     string `json:"token" binding:"required" example:"fcm_token_123"`
+	Platform  string `json:"platform" binding:"required" example:"ios"`
+	Provider  string `json:"provider,omitempty" binding:"omitempty,oneof=fcm apns" example:"fcm"`
+	SessionID string `json:"-"` // Set from the access token by the handler
+}
+
+// RemovePushTokenRequest unregisters a device token. Without a token, the
+// token registered by the current device session is removed.
+type RemovePushTokenRequest struct {
+	Token     string `json:"token,omitempty" example:"fcm_token_123"`
+	SessionID string `json:"-"` // Set from the access token by the handler
+}
+
+// MFAVerifyRequest completes a login that returned an mfa_token. Code is
+// either the current authenticator code or an unused recovery code.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIs..."`
+	Code     string `json:"code" binding:"required" example:"123456"`
+	ClientInfo
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
 }
 
 // Responses
+
+// LoginResponse is returned by password and social login. Accounts without
+// two-factor authentication get the AuthResponse fields straight away; the
+// others get MFARequired and an MFAToken to exchange at /auth/mfa/verify.
+type LoginResponse struct {
+	*AuthResponse
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+	MFAExpires  int64  `json:"mfa_expires_in,omitempty"` // seconds
+}
+
+type MFAEnrollResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/LissanAI:john@lissanai.com?secret=JBSWY3DPEHPK3PXP&issuer=LissanAI"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k7m2q-x9rtp,a3vbn-w8hcz"`
+}
+
 type AuthResponse struct {
 	User         *User  `json:"user"`
 	AccessToken  string `json:"access_token"`
@@ -57,14 +134,30 @@ type AuthResponse struct {
 }
 
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int64  `json:"expires_in"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
 }
 
 type MessageResponse struct {
 	Message string `json:"message"`
 }
 
+// AccountDeletionResponse is returned by DELETE /users/me. DeletionScheduledFor
+// is set when a grace period applies; otherwise the account is already gone.
+type AccountDeletionResponse struct {
+	Message              string     `json:"message" example:"Account scheduled for deletion"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
+}
+
+// UserDataExport is everything stored about a user, keyed by collection.
+// Credentials and one-time tokens are left out.
+type UserDataExport struct {
+	UserID      primitive.ObjectID       `json:"user_id"`
+	ExportedAt  time.Time                `json:"exported_at"`
+	Collections map[string][]primitive.M `json:"collections"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -74,46 +167,228 @@ type User struct {
 	ID           primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
 	Name         string                 `json:"name" bson:"name"`
 	Email        string                 `json:"email" bson:"email"`
+	Handle       string                 `json:"handle,omitempty" bson:"handle,omitempty"` // Unique, names the public profile
 	PasswordHash string                 `json:"-" bson:"password_hash,omitempty"`
 	Provider     string                 `json:"provider,omitempty" bson:"provider,omitempty"`
 	ProviderID   string                 `json:"-" bson:"provider_id,omitempty"`
-	Settings     map[string]interface{} `json:"settings,omitempty" bson:"settings,omitempty"`
+	Settings     *UserSettings          `json:"settings,omitempty" bson:"settings,omitempty"`
 	PushTokens   []PushToken            `json:"-" bson:"push_tokens,omitempty"`
+
+	EmailVerified   bool       `json:"email_verified" bson:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" bson:"email_verified_at,omitempty"`
+
+	// Access control
+	Roles            []string   `json:"roles" bson:"roles,omitempty"`
+	Suspended        bool       `json:"suspended" bson:"suspended"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty" bson:"suspended_at,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty" bson:"suspension_reason,omitempty"`
+
+	// Two-factor authentication (TOTP). Recovery codes are stored as hashes.
+	MFAEnabled       bool     `json:"mfa_enabled" bson:"mfa_enabled"`
+	MFASecret        string   `json:"-" bson:"mfa_secret,omitempty"`
+	MFAPendingSecret string   `json:"-" bson:"mfa_pending_secret,omitempty"` // awaiting confirmation
+	MFARecoveryCodes []string `json:"-" bson:"mfa_recovery_codes,omitempty"`
+	MFALastUsedStep  int64    `json:"-" bson:"mfa_last_used_step,omitempty"` // last accepted TOTP time step
+
+	// Account deletion. During the grace period the account can still log in
+	// and be restored; after DeletionScheduledFor all its data is purged.
+	DeletionRequestedAt  *time.Time `json:"deletion_requested_at,omitempty" bson:"deletion_requested_at,omitempty"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty" bson:"deletion_scheduled_for,omitempty"`
 	
 	// Streak System
 	CurrentStreak    int       `json:"current_streak" bson:"current_streak"`
 	LongestStreak    int       `json:"longest_streak" bson:"longest_streak"`
 	LastActivityDate time.Time `json:"last_activity_date" bson:"last_activity_date"`
-	StreakFrozen     bool      `json:"streak_frozen" bson:"streak_frozen"`
-	FreezeCount      int       `json:"freeze_count" bson:"freeze_count"`
+	// Last missed day covered by a streak freeze; the streak is kept through
+	// the later of this and LastActivityDate
+	StreakFrozenUntil time.Time `json:"streak_frozen_until,omitempty" bson:"streak_frozen_until,omitempty"`
+
+	// Last daily practice reminder; at most one is sent per day
+	ReminderSentAt *time.Time `json:"-" bson:"reminder_sent_at,omitempty"`
+
+	// XP earned in total, and the league the user competes in each week
+	// (empty until their first weekly rollover means the lowest league)
+	XP     int    `json:"xp" bson:"xp"`
+	League string `json:"league,omitempty" bson:"league,omitempty"`
 	
 	CreatedAt    time.Time              `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time              `json:"updated_at" bson:"updated_at"`
 }
 
+// User roles. Users stored without any role are learners.
+const (
+	RoleLearner       = "learner"
+	RoleContentEditor = "content_editor"
+	RoleAdmin         = "admin"
+)
+
+// IsValidRole reports whether role is one of the known user roles.
+func IsValidRole(role string) bool {
+	return role == RoleLearner || role == RoleContentEditor || role == RoleAdmin
+}
+
+var handlePattern = regexp.MustCompile(`^[a-z0-9_]{3,30}$`)
+
+// NormalizeHandle lowercases a profile handle, dropping a leading @, and
+// checks that it is 3-30 letters, digits or underscores.
+func NormalizeHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+	if !handlePattern.MatchString(handle) {
+		return "", errors.New("invalid handle: must be 3-30 letters, digits or underscores")
+	}
+	return handle, nil
+}
+
+// EffectiveRoles returns the user's roles, defaulting to learner.
+func (u *User) EffectiveRoles() []string {
+	if len(u.Roles) == 0 {
+		return []string{RoleLearner}
+	}
+	return u.Roles
+}
+
+// Push token providers
+const (
+	PushProviderFCM  = "fcm"
+	PushProviderAPNs = "apns"
+)
+
 type PushToken struct {
 	Token     string    `json:"token" bson:"token"`
 	Platform  string    `json:"platform" bson:"platform"`
+	Provider  string    `json:"provider,omitempty" bson:"provider,omitempty"`     // empty for tokens saved before providers were recorded, all FCM
+	SessionID string    `json:"session_id,omitempty" bson:"session_id,omitempty"` // Device session that registered the token
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 }
 
+// RefreshToken is a single link in a token family. Every /auth/refresh rotates
+// the presented token (RotatedAt, ReplacedBy) and issues a new one in the same
+// family; presenting a rotated token again revokes the whole family.
 type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	Token      string             `bson:"token"`
+	FamilyID   string             `bson:"family_id"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	RotatedAt  *time.Time         `bson:"rotated_at,omitempty"`
+	ReplacedBy string             `bson:"replaced_by,omitempty"`
+	Revoked    bool               `bson:"revoked"`
+}
+
+// UserSession is a logged-in device. Its ID doubles as the FamilyID of the
+// refresh tokens issued to that device, and as the "sid" access token claim.
+type UserSession struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	DeviceName string             `bson:"device_name,omitempty"`
+	Platform   string             `bson:"platform,omitempty"`
+	IPAddress  string             `bson:"ip_address,omitempty"`
+	UserAgent  string             `bson:"user_agent,omitempty"`
+	PushToken  string             `bson:"push_token,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	LastUsedAt time.Time          `bson:"last_used_at"`
+	ExpiresAt  time.Time          `bson:"expires_at"` // when the newest refresh token expires
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id" example:"68a85f301197d981baa0f301"`
+	DeviceName string    `json:"device_name,omitempty" example:"Abebe's iPhone"`
+	Platform   string    `json:"platform,omitempty" example:"ios"`
+	IPAddress  string    `json:"ip_address,omitempty" example:"196.188.12.34"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+type PasswordReset struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	UserID    primitive.ObjectID `bson:"user_id"`
 	Token     string             `bson:"token"`
 	ExpiresAt time.Time          `bson:"expires_at"`
 	CreatedAt time.Time          `bson:"created_at"`
+	Used      bool               `bson:"used"`
 }
 
-type PasswordReset struct {
+// AuthAttempt counts recent failed attempts for a throttling key such as
+// "login:email:<address>" or "login:ip:<address>".
+type AuthAttempt struct {
+	Key            string     `bson:"_id"`
+	Failures       int        `bson:"failures"`
+	FirstFailureAt time.Time  `bson:"first_failure_at"`
+	LastFailureAt  time.Time  `bson:"last_failure_at"`
+	LockedUntil    *time.Time `bson:"locked_until,omitempty"`
+	ExpiresAt      time.Time  `bson:"expires_at"` // TTL; the record is forgotten after this
+}
+
+type EmailVerification struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	UserID    primitive.ObjectID `bson:"user_id"`
+	Email     string             `bson:"email"`
 	Token     string             `bson:"token"`
 	ExpiresAt time.Time          `bson:"expires_at"`
 	CreatedAt time.Time          `bson:"created_at"`
 	Used      bool               `bson:"used"`
 }
 
+// Outbox email statuses
+const (
+	OutboxPending = "pending" // waiting for its next attempt
+	OutboxSending = "sending" // claimed by a worker until LockedUntil
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed" // gave up after the last attempt or a permanent error
+)
+
+// OutboxEmail is a rendered email waiting in the outbox. Messages are
+// rendered when queued, so a worker only has to deliver them.
+type OutboxEmail struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `bson:"user_id,omitempty"`
+	To            string             `bson:"to"`
+	Template      string             `bson:"template"`
+	Locale        string             `bson:"locale"`
+	Subject       string             `bson:"subject"`
+	HTMLBody      string             `bson:"html_body"`
+	TextBody      string             `bson:"text_body"`
+	Status        string             `bson:"status"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty"`
+	LastError     string             `bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty"`
+	ExpiresAt     *time.Time         `bson:"expires_at,omitempty"` // TTL; set once the message is sent or failed
+}
+
+// Push notification categories, used by clients to route a tapped notification
+const (
+	PushCategoryStreak      = "streak"
+	PushCategoryLesson      = "lesson"
+	PushCategoryInterview   = "interview"
+	PushCategoryAchievement = "achievement"
+)
+
+// PushNotification is a notification queued for every device of a user. It
+// uses the same statuses as OutboxEmail.
+type PushNotification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `bson:"user_id"`
+	Category      string             `bson:"category"`
+	Title         string             `bson:"title"`
+	Body          string             `bson:"body"`
+	Data          map[string]string  `bson:"data,omitempty"`
+	Status        string             `bson:"status"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	DeliverBy     time.Time          `bson:"deliver_by"` // dropped if still undelivered by then
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty"`
+	LastError     string             `bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty"`
+	ExpiresAt     *time.Time         `bson:"expires_at,omitempty"` // TTL; set once the notification is sent or failed
+}
+
 // Learning Path Models
 type LearningPath struct {
 	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
@@ -235,20 +510,22 @@ type QuizResultResponse struct {
 
 // Streak Models
 type StreakInfo struct {
-	CurrentStreak    int       `json:"current_streak"`
-	LongestStreak    int       `json:"longest_streak"`
-	LastActivityDate time.Time `json:"last_activity_date"`
-	StreakFrozen     bool      `json:"streak_frozen"`
-	FreezeCount      int       `json:"freeze_count"`
-	MaxFreezes       int       `json:"max_freezes"`
-	CanFreeze        bool      `json:"can_freeze"`
-	DaysUntilLoss    int       `json:"days_until_loss"`
+	CurrentStreak    int        `json:"current_streak"`
+	LongestStreak    int        `json:"longest_streak"`
+	LastActivityDate time.Time  `json:"last_activity_date"`
+	StreakFrozen     bool       `json:"streak_frozen"`          // a freeze is keeping the streak alive
+	FrozenUntil      *time.Time `json:"frozen_until,omitempty"` // last day covered by a freeze
+	FreezesAvailable int        `json:"freezes_available"`      // freezes in the inventory
+	MaxFreezes       int        `json:"max_freezes"`            // inventory size
+	CanFreeze        bool       `json:"can_freeze"`
+	DaysUntilLoss    int        `json:"days_until_loss"` // days that can still be missed, counting freezes
 }
 
 type StreakActivity struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
 	ActivityType string             `json:"activity_type" bson:"activity_type"` // lesson_completed, quiz_passed, daily_goal_met
+	Manual       bool               `json:"manual,omitempty" bson:"manual,omitempty"` // recorded through the manual endpoint; earns no badges or XP
 	Date         time.Time          `json:"date" bson:"date"`
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
 }
@@ -257,11 +534,184 @@ type FreezeStreakRequest struct {
 	Reason string `json:"reason,omitempty" example:"Vacation"`
 }
 
+// How a streak freeze was earned
+const (
+	FreezeSourceMilestone = "milestone" // every 7-day streak milestone
+	FreezeSourceMonthly   = "monthly"   // granted at the start of each month
+)
+
+// Why a streak freeze was used
+const (
+	FreezeUseMissedDay = "missed_day" // consumed automatically for a day without practice
+	FreezeUseManual    = "manual"     // used in advance by the user
+)
+
+// StreakFreeze is one freeze in a user's inventory. It is available until
+// UsedOn is set to the day it kept the streak alive.
+type StreakFreeze struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"-" bson:"user_id"`
+	Source     string             `json:"source" bson:"source" enums:"milestone,monthly"`
+	SourceKey  string             `json:"-" bson:"source_key"` // one freeze per milestone day or month
+	Slot       *int               `json:"-" bson:"slot,omitempty"` // inventory slot held while unused
+	EarnedAt   time.Time          `json:"earned_at" bson:"earned_at"`
+	UsedOn     *time.Time         `json:"used_on,omitempty" bson:"used_on,omitempty"` // the frozen day
+	UsedReason string             `json:"used_reason,omitempty" bson:"used_reason,omitempty" enums:"missed_day,manual"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"` // reason given for a manual freeze
+	UsedAt     *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+}
+
+type StreakFreezesResponse struct {
+	Available int            `json:"available"`
+	Max       int            `json:"max"`
+	Freezes   []StreakFreeze `json:"freezes"` // newest first
+}
+
+// UserAchievement is a badge awarded to a user. Each badge is awarded once;
+// the rules that award them are declared in service/achievements/rules.json.
+type UserAchievement struct {
+	ID            primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `json:"-" bson:"user_id"`
+	AchievementID string             `json:"achievement_id" bson:"achievement_id"`
+	AwardedAt     time.Time          `json:"awarded_at" bson:"awarded_at"`
+}
+
+// AchievementStatus is one achievement as shown to a user, in their language.
+type AchievementStatus struct {
+	ID          string     `json:"id" example:"streak_7"`
+	Title       string     `json:"title" example:"Week Streak"`
+	Description string     `json:"description" example:"Practice 7 days in a row"`
+	Icon        string     `json:"icon" example:"🔥"`
+	Earned      bool       `json:"earned"`
+	AwardedAt   *time.Time `json:"awarded_at,omitempty"`
+	Progress    int        `json:"progress" example:"4"` // towards Target, at most Target
+	Target      int        `json:"target" example:"7"`
+}
+
+type AchievementsResponse struct {
+	Earned       int                 `json:"earned" example:"3"`
+	Total        int                 `json:"total" example:"12"`
+	Achievements []AchievementStatus `json:"achievements"`
+}
+
+// XP sources besides activity types: points scaled by a score in percent
+const (
+	XPSourceQuizScore      = "quiz_score"
+	XPSourceInterviewScore = "interview_score"
+)
+
+// Leagues from lowest to highest. Each week the best of a league group move
+// up one league and the last move down one.
+var Leagues = []string{"bronze", "silver", "gold", "sapphire", "diamond"}
+
+// Weekly league results
+const (
+	LeagueResultPromoted = "promoted"
+	LeagueResultStayed   = "stayed"
+	LeagueResultDemoted  = "demoted"
+)
+
+// XPEvent is one entry of a user's XP ledger.
+type XPEvent struct {
+	ID        primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"-" bson:"user_id"`
+	Source    string             `json:"source" bson:"source" example:"lesson_completed"` // activity type or XPSource*
+	Points    int                `json:"points" bson:"points" example:"20"`
+	Reference string             `json:"reference,omitempty" bson:"reference,omitempty"` // quiz ID, interview question or day/award number
+	Week      string             `json:"week" bson:"week" example:"2026-W42"`            // ISO week, UTC
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type XPSummaryResponse struct {
+	Total    int       `json:"total" example:"1250"`
+	ThisWeek int       `json:"this_week" example:"180"`
+	League   string    `json:"league" example:"silver"`
+	Week     string    `json:"week" example:"2026-W42"`
+	Recent   []XPEvent `json:"recent"` // newest first
+}
+
+// LeagueMembership places a user in a league group for one week. Users join
+// the week's group with their first XP; Rank and Result are set when the
+// week is rolled over.
+type LeagueMembership struct {
+	ID       primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID   primitive.ObjectID `json:"-" bson:"user_id"`
+	Week     string             `json:"week" bson:"week"`
+	League   string             `json:"league" bson:"league"`
+	Group    int                `json:"-" bson:"group"`
+	XP       int                `json:"xp" bson:"xp"`
+	JoinedAt time.Time          `json:"-" bson:"joined_at"`
+	Rank     int                `json:"rank,omitempty" bson:"rank,omitempty"`
+	Result   string             `json:"result,omitempty" bson:"result,omitempty" enums:"promoted,stayed,demoted"`
+}
+
+type LeaderboardEntry struct {
+	Rank   int    `json:"rank" example:"1"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name" example:"Abebe"`
+	XP     int    `json:"xp" example:"320"`
+	IsYou  bool   `json:"is_you"`
+}
+
+type LeagueLeaderboardResponse struct {
+	Week   string    `json:"week" example:"2026-W42"`
+	League string    `json:"league" example:"silver"`
+	EndsAt time.Time `json:"ends_at"`
+	// Ranks up to PromotionRank move up a league and ranks from DemotionRank
+	// move down; 0 when nobody does
+	PromotionRank int                `json:"promotion_rank" example:"5"`
+	DemotionRank  int                `json:"demotion_rank" example:"26"`
+	HiddenByUser  bool               `json:"hidden_by_user"` // the user opted out of leaderboards
+	Entries       []LeaderboardEntry `json:"entries"`        // empty until the user earns XP this week
+	LastWeek      *LeagueMembership  `json:"last_week,omitempty"`
+}
+
+type FriendsLeaderboardResponse struct {
+	Week    string             `json:"week" example:"2026-W42"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// Friendship statuses
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+)
+
+// Friendship links two users once the addressee accepts the request.
+type Friendship struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RequesterID primitive.ObjectID `json:"requester_id" bson:"requester_id"`
+	AddresseeID primitive.ObjectID `json:"addressee_id" bson:"addressee_id"`
+	Pair        string             `json:"-" bson:"pair"` // both user IDs in order, one friendship per pair
+	Status      string             `json:"status" bson:"status"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	AcceptedAt  *time.Time         `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+}
+
+type FriendRequest struct {
+	Email string `json:"email" binding:"required,email" example:"friend@example.com"`
+}
+
+// Friend is the other user of a friendship, as shown to the user.
+type Friend struct {
+	FriendshipID string    `json:"friendship_id"`
+	UserID       string    `json:"user_id"`
+	Name         string    `json:"name" example:"Abebe"`
+	Since        time.Time `json:"since"` // accepted or, for requests, sent
+}
+
+type FriendsResponse struct {
+	Friends  []Friend `json:"friends"`
+	Incoming []Friend `json:"incoming"` // requests to accept
+	Outgoing []Friend `json:"outgoing"` // requests awaiting the other user
+}
+
 // Activity Calendar Models (GitHub-like contribution graph)
 type ActivityCalendarDay struct {
 	Date         string `json:"date"`         // YYYY-MM-DD format
 	ActivityCount int    `json:"activity_count"` // Number of activities on this day
 	HasActivity  bool   `json:"has_activity"`   // Whether user was active on this day
+	Frozen       bool   `json:"frozen,omitempty"` // A streak freeze covered this day
 	ActivityTypes []string `json:"activity_types,omitempty"` // Types of activities done
 }
 
@@ -273,6 +723,7 @@ type ActivityCalendarResponse struct {
 	Year         int                    `json:"year"`
 	TotalDays    int                    `json:"total_days"`
 	ActiveDays   int                    `json:"active_days"`
+	FrozenDays   int                    `json:"frozen_days"` // Days covered by a streak freeze
 	CurrentStreak int                   `json:"current_streak"`
 	LongestStreak int                   `json:"longest_streak"`
 	Weeks        []ActivityCalendarWeek `json:"weeks"`
@@ -287,6 +738,23 @@ type ActivityCalendarSummary struct {
 	ConsecutiveWeeks    int            `json:"consecutive_weeks"`    // Weeks with at least one activity
 }
 
+// PublicProfile is what an opted-in user shares at /u/{handle}. Only the
+// handle identifies them; name and email stay private.
+type PublicProfile struct {
+	Handle        string        `json:"handle" example:"john_doe"`
+	CurrentStreak int           `json:"current_streak" example:"12"`
+	LongestStreak int           `json:"longest_streak" example:"30"`
+	Badges        []PublicBadge `json:"badges"` // earned badges, oldest first
+	CalendarURL   string        `json:"calendar_url" example:"/u/john_doe/calendar.svg"`
+}
+
+type PublicBadge struct {
+	ID        string    `json:"id" example:"streak_7"`
+	Title     string    `json:"title" example:"Week Streak"`
+	Icon      string    `json:"icon" example:"🔥"`
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
 // Daily Activity Summary (for efficient querying)
 type DailyActivitySummary struct {
 	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
@@ -304,3 +772,125 @@ type DailyActivitySummary struct {
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
+
+// Admin Models
+type UserSearchFilter struct {
+	Query     string // matches name or email
+	Role      string
+	Suspended *bool
+}
+
+type UserSearchResponse struct {
+	Users []*User `json:"users"`
+	Total int64   `json:"total"`
+	Page  int     `json:"page"`
+	Limit int     `json:"limit"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Abusive behaviour in interview sessions"`
+}
+
+type UpdateRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,min=1,dive,oneof=learner content_editor admin" example:"learner,content_editor"`
+}
+
+type LearningPathRequest struct {
+	Title       string `json:"title" binding:"required" example:"Job Interview English"`
+	Description string `json:"description" example:"Prepare for English job interviews"`
+	Level       string `json:"level" binding:"required,oneof=beginner intermediate advanced" example:"intermediate"`
+	Category    string `json:"category" example:"interview"`
+	Duration    int    `json:"duration" binding:"min=0" example:"120"` // in minutes
+}
+
+type LessonRequest struct {
+	PathID      string `json:"path_id" binding:"required" example:"68a85f301197d981baa0f301"`
+	Title       string `json:"title" binding:"required" example:"Introducing Yourself"`
+	Description string `json:"description" example:"Learn to answer 'Tell me about yourself'"`
+	Content     string `json:"content"`
+	Type        string `json:"type" binding:"required,oneof=video text quiz exercise" example:"text"`
+	Duration    int    `json:"duration" binding:"min=0" example:"15"` // in minutes
+	Order       int    `json:"order" binding:"min=0" example:"1"`
+}
+
+type QuizRequest struct {
+	LessonID  string     `json:"lesson_id" binding:"required" example:"68a85f301197d981baa0f302"`
+	Title     string     `json:"title" binding:"required" example:"Self Introduction Check"`
+	Questions []Question `json:"questions" binding:"required,min=1"`
+}
+
+// Audit Log Models
+
+// AuditActor identifies who performed an admin action and from where.
+type AuditActor struct {
+	UserID    primitive.ObjectID
+	IPAddress string
+}
+
+// AuditLog records a single admin action. It is written before the action
+// is applied; Failed marks actions that then failed. Entries are otherwise
+// append-only.
+type AuditLog struct {
+	ID         primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
+	ActorID    primitive.ObjectID     `json:"actor_id" bson:"actor_id"`
+	ActorIP    string                 `json:"actor_ip,omitempty" bson:"actor_ip,omitempty"`
+	Action     string                 `json:"action" bson:"action" example:"user.suspend"`
+	TargetType string                 `json:"target_type,omitempty" bson:"target_type,omitempty" example:"user"`
+	TargetID   string                 `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty" bson:"details,omitempty"`
+	Failed     bool                   `json:"failed,omitempty" bson:"failed,omitempty"`
+	CreatedAt  time.Time              `json:"created_at" bson:"created_at"`
+}
+
+type AuditLogFilter struct {
+	ActorID  *primitive.ObjectID
+	Action   string
+	TargetID string
+}
+
+type AuditLogListResponse struct {
+	Logs  []*AuditLog `json:"logs"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+// Job run triggers and outcomes
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// ScheduledJob is the shared state of a background job run by the jobs
+// scheduler. Whichever instance holds the lease runs it.
+type ScheduledJob struct {
+	Name        string     `json:"name" bson:"_id" example:"streak-expiry"`
+	Schedule    string     `json:"schedule" bson:"schedule" example:"0 * * * *"` // cron expression, UTC
+	Description string     `json:"description" bson:"description"`
+	NextRunAt   time.Time  `json:"next_run_at" bson:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty" bson:"last_run_at,omitempty"`
+	LastStatus  string     `json:"last_status,omitempty" bson:"last_status,omitempty" enums:"succeeded,failed"`
+	LastError   string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	LeaseOwner  string     `json:"lease_owner,omitempty" bson:"lease_owner,omitempty"` // instance running it now
+	LeaseUntil  *time.Time `json:"lease_until,omitempty" bson:"lease_until,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	RecentRuns  []*JobRun  `json:"recent_runs,omitempty" bson:"-"` // newest first
+}
+
+// JobRun is one run of a scheduled job, kept as its run history.
+type JobRun struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Job         string             `json:"job" bson:"job"`
+	Trigger     string             `json:"trigger" bson:"trigger" enums:"schedule,manual"`
+	TriggeredBy string             `json:"triggered_by,omitempty" bson:"triggered_by,omitempty"` // admin user ID for manual runs
+	Instance    string             `json:"instance" bson:"instance"`
+	Status      string             `json:"status" bson:"status" enums:"running,succeeded,failed"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt   time.Time          `json:"started_at" bson:"started_at"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+	DurationMS  int64              `json:"duration_ms,omitempty" bson:"duration_ms,omitempty"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,15 +19,40 @@ type Session struct {
 }
 
 type FeedbackPoint struct {
-	Type        string `bson:"type" json:"type"` // grammar, pronunciation, structure
+	Type        string `bson:"type" json:"type" enums:"grammar,pronunciation,structure"`
 	FocusPhrase string `bson:"focus_phrase" json:"focus_phrase"`
 	Suggestion  string `bson:"suggestion" json:"suggestion"`
 }
 
+// ScoreRubric is how an answer is scored: up to 25 points for each
+// criterion, and the score is their sum.
+type ScoreRubric struct {
+	Grammar   int `bson:"grammar" json:"grammar" minimum:"0" maximum:"25"`
+	Clarity   int `bson:"clarity" json:"clarity" minimum:"0" maximum:"25"`
+	Fluency   int `bson:"fluency" json:"fluency" minimum:"0" maximum:"25"`
+	Relevance int `bson:"relevance" json:"relevance" minimum:"0" maximum:"25"`
+}
+
+func (r ScoreRubric) Total() int {
+	return r.Grammar + r.Clarity + r.Fluency + r.Relevance
+}
+
 type Feedback struct {
 	OverallSummary string          `bson:"overall_summary" json:"overall_summary"`
 	FeedbackPoints []FeedbackPoint `bson:"feedback_points" json:"feedback_points"`
-	ScorePercent   int             `bson:"score_percentage" json:"score_percentage"`
+	Rubric         ScoreRubric     `bson:"rubric" json:"rubric"`
+	ScorePercent   int             `bson:"score_percentage" json:"score_percentage" minimum:"0" maximum:"100"`
+	// Prompt template that produced the feedback, e.g. "interview_feedback@v2"
+	PromptVersion string `bson:"prompt_version,omitempty" json:"prompt_version,omitempty" llm:"-"`
+}
+
+// Check makes sure the score is the one the rubric gives, so an answer
+// cannot talk the model into a score it did not earn.
+func (f *Feedback) Check() []string {
+	if total := f.Rubric.Total(); f.ScorePercent != total {
+		return []string{fmt.Sprintf("score_percentage: must be the sum of the rubric scores, %d, not %d", total, f.ScorePercent)}
+	}
+	return nil
 }
 
 type Message struct {
@@ -36,6 +62,8 @@ type Message struct {
 	Feedback  *Feedback          `bson:"feedback,omitempty" json:"feedback,omitempty"`
 	Question  string             `bson:"question" json:"question"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	// Index of the question in the session, starting at 0
+	QuestionNumber int `bson:"question_number" json:"question_number"`
 }
 
 type SessionSummary struct {
@@ -46,6 +74,7 @@ type SessionSummary struct {
 	Weaknesses     []string `bson:"weaknesses" json:"weaknesses"`
 	FinalScore     int      `bson:"final_score" json:"final_score"` // out of 100
 	CreatedAt      int64    `bson:"created_at" json:"created_at"`
+	PromptVersion  string   `bson:"prompt_version,omitempty" json:"prompt_version,omitempty"`
 }
 
 type SessionReturn struct {
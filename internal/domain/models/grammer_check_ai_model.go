@@ -15,4 +15,5 @@ type Correction struct {
 type GrammarResponse struct {
 	CorrectedText string       `json:"corrected_text" example:"He has two cats"`
 	Corrections   []Correction `json:"corrections"`
+	PromptVersion string       `json:"prompt_version,omitempty" llm:"-" example:"grammar@v1"`
 }
@@ -0,0 +1,135 @@
+// internal/domain/prompts.go
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Where a prompt template was loaded from
+const (
+	PromptSourceFile  = "file"
+	PromptSourceMongo = "mongo"
+)
+
+// PromptTemplate is one version of a named prompt in one locale; an empty
+// locale is the default (English) text, used when no variant for the user's
+// language exists. Text and System are Go text/template sources over the
+// variables the feature passes, e.g. {{.Text}}. Versions are never changed
+// once created, so a version identifies the exact prompt behind a result.
+type PromptTemplate struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name" example:"grammar"`
+	Version   int                `json:"version" bson:"version" example:"2"`
+	Locale    string             `json:"locale,omitempty" bson:"locale" example:"am"`
+	System    string             `json:"system,omitempty" bson:"system,omitempty"`
+	Text      string             `json:"text" bson:"text"`
+	Source    string             `json:"source" bson:"-" enums:"file,mongo"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at"`
+}
+
+// Ref identifies the template in results, e.g. "grammar@v2" or
+// "grammar@v2.am".
+func (t *PromptTemplate) Ref() string {
+	ref := fmt.Sprintf("%s@v%d", t.Name, t.Version)
+	if t.Locale != "" {
+		ref += "." + t.Locale
+	}
+	return ref
+}
+
+// PromptRollout decides which version of a prompt is served. Signed-in
+// users in an experiment are split between its variants by a stable hash
+// of their ID; everyone else gets Version, or if it is 0 the highest version
+// shipped in the prompt files. Versions added through the admin API are
+// never served until a rollout or experiment names them.
+type PromptRollout struct {
+	Name       string            `json:"name" bson:"_id" example:"grammar"`
+	Version    int               `json:"version" bson:"version" example:"1"`
+	Experiment *PromptExperiment `json:"experiment,omitempty" bson:"experiment,omitempty"`
+	UpdatedAt  time.Time         `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// PromptExperiment splits users between prompt versions. Changing the ID
+// reshuffles the buckets.
+type PromptExperiment struct {
+	ID       string          `json:"id" bson:"id" example:"grammar-amharic-explanations"`
+	Variants []PromptVariant `json:"variants" bson:"variants"`
+}
+
+// PromptVariant serves a version to Weight percent of the users.
+type PromptVariant struct {
+	Version int `json:"version" bson:"version" example:"2"`
+	Weight  int `json:"weight" bson:"weight" example:"50"`
+}
+
+// Prompt is a named prompt with all its templates and the rollout in effect.
+type Prompt struct {
+	Name      string            `json:"name"`
+	Rollout   PromptRollout     `json:"rollout"`
+	Templates []*PromptTemplate `json:"templates"`
+}
+
+// PromptTemplateRequest adds a template to a prompt. Without a version it
+// creates the next version, which must be the default locale; with one it
+// adds a locale variant to that version.
+type PromptTemplateRequest struct {
+	Version int    `json:"version,omitempty" example:"2"`
+	Locale  string `json:"locale,omitempty" example:"am"`
+	System  string `json:"system,omitempty"`
+	Text    string `json:"text" binding:"required"`
+}
+
+// PromptRolloutRequest sets the version a prompt serves and its experiment;
+// leaving out the experiment ends it.
+type PromptRolloutRequest struct {
+	Version    int               `json:"version" example:"1"`
+	Experiment *PromptExperiment `json:"experiment,omitempty"`
+}
+
+// PromptValidationError reports a prompt template or rollout that is not
+// allowed.
+type PromptValidationError struct {
+	Message string
+}
+
+func (e *PromptValidationError) Error() string {
+	return e.Message
+}
+
+func invalidPrompt(format string, args ...interface{}) error {
+	return &PromptValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate checks the rollout against the versions the prompt has.
+func (r *PromptRollout) Validate(versions map[int]bool) error {
+	if r.Version != 0 && !versions[r.Version] {
+		return invalidPrompt("invalid rollout: %s has no version %d", r.Name, r.Version)
+	}
+	if r.Experiment == nil {
+		return nil
+	}
+
+	if r.Experiment.ID == "" {
+		return invalidPrompt("invalid experiment: an id is required")
+	}
+	if len(r.Experiment.Variants) < 2 {
+		return invalidPrompt("invalid experiment: at least two variants are required")
+	}
+	total := 0
+	for _, variant := range r.Experiment.Variants {
+		if !versions[variant.Version] {
+			return invalidPrompt("invalid experiment: %s has no version %d", r.Name, variant.Version)
+		}
+		if variant.Weight <= 0 {
+			return invalidPrompt("invalid experiment: weights must be positive")
+		}
+		total += variant.Weight
+	}
+	if total != 100 {
+		return invalidPrompt("invalid experiment: weights must add up to 100, not %d", total)
+	}
+	return nil
+}
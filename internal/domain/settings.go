@@ -0,0 +1,429 @@
+// internal/domain/settings.go
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CurrentSettingsVersion is the schema version written with every settings
+// document. Bump it together with a new entry in settingsMigrations.
+const CurrentSettingsVersion = 2
+
+// Supported setting values
+var (
+	SettingsLanguages  = []string{"en", "am"}
+	SettingsCEFRLevels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+	SettingsTTSVoices  = []string{"female", "male"}
+	// auto sends push notifications to users with a registered device and email otherwise
+	SettingsReminderChannels = []string{"auto", "push", "email"}
+)
+
+const (
+	MinDailyGoalMinutes = 5
+	MaxDailyGoalMinutes = 240
+	maxTargetJobRoleLen = 100
+	DefaultTimeZone     = "Africa/Addis_Ababa"
+)
+
+var reminderTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// UserSettings are the learner's preferences, stored on the user document.
+// Documents written with an older schema are migrated when read.
+type UserSettings struct {
+	Version          int    `json:"version" bson:"version" example:"2"`
+	Language         string `json:"language" bson:"language" enums:"en,am" example:"am"` // UI language
+	DailyGoalMinutes int    `json:"daily_goal_minutes" bson:"daily_goal_minutes" example:"15"`
+	ReminderTime     string `json:"reminder_time" bson:"reminder_time" example:"19:30"`      // HH:MM local time; empty disables reminders
+	TimeZone         string `json:"time_zone" bson:"time_zone" example:"Africa/Addis_Ababa"` // IANA name
+	TargetJobRole    string `json:"target_job_role,omitempty" bson:"target_job_role,omitempty" example:"Software Engineer"`
+	CEFRLevel        string `json:"cefr_level,omitempty" bson:"cefr_level,omitempty" enums:"A1,A2,B1,B2,C1,C2" example:"B1"` // empty until assessed
+	TTSVoice         string `json:"tts_voice" bson:"tts_voice" enums:"female,male" example:"female"`
+	ReminderChannel  string `json:"reminder_channel" bson:"reminder_channel" enums:"auto,push,email" example:"auto"`
+	// No notifications are sent between these local times (HH:MM, may wrap
+	// past midnight); both empty disables quiet hours
+	QuietHoursStart string `json:"quiet_hours_start" bson:"quiet_hours_start" example:"22:00"`
+	QuietHoursEnd   string `json:"quiet_hours_end" bson:"quiet_hours_end" example:"07:00"`
+	// Keeps the user out of leagues and friends' leaderboards; XP is still earned
+	HideFromLeaderboards bool `json:"hide_from_leaderboards" bson:"hide_from_leaderboards"`
+	// Publishes the user's streaks, badges and activity calendar at
+	// /u/{handle}; needs a handle
+	PublicProfile bool `json:"public_profile" bson:"public_profile"`
+}
+
+// DefaultSettings returns the settings of a new user.
+func DefaultSettings() UserSettings {
+	return UserSettings{
+		Version:          CurrentSettingsVersion,
+		Language:         "en",
+		DailyGoalMinutes: 10,
+		ReminderTime:     "19:00",
+		TimeZone:         DefaultTimeZone,
+		TTSVoice:         "female",
+		ReminderChannel:  "auto",
+		QuietHoursStart:  "22:00",
+		QuietHoursEnd:    "07:00",
+	}
+}
+
+// CurrentSettings returns the user's settings, or the defaults if none are stored.
+func (u *User) CurrentSettings() UserSettings {
+	if u.Settings == nil {
+		return DefaultSettings()
+	}
+	return *u.Settings
+}
+
+// UpdateSettingsRequest changes only the fields that are present.
+type UpdateSettingsRequest struct {
+	Language             *string `json:"language,omitempty" example:"am"`
+	DailyGoalMinutes     *int    `json:"daily_goal_minutes,omitempty" example:"15"`
+	ReminderTime         *string `json:"reminder_time,omitempty" example:"19:30"`
+	TimeZone             *string `json:"time_zone,omitempty" example:"Africa/Addis_Ababa"`
+	TargetJobRole        *string `json:"target_job_role,omitempty" example:"Software Engineer"`
+	CEFRLevel            *string `json:"cefr_level,omitempty" example:"B1"`
+	TTSVoice             *string `json:"tts_voice,omitempty" example:"female"`
+	ReminderChannel      *string `json:"reminder_channel,omitempty" example:"push"`
+	QuietHoursStart      *string `json:"quiet_hours_start,omitempty" example:"22:00"`
+	QuietHoursEnd        *string `json:"quiet_hours_end,omitempty" example:"07:00"`
+	HideFromLeaderboards *bool   `json:"hide_from_leaderboards,omitempty" example:"false"`
+	PublicProfile        *bool   `json:"public_profile,omitempty" example:"true"`
+}
+
+// ApplyTo copies the present fields onto settings and validates the result.
+func (r *UpdateSettingsRequest) ApplyTo(settings *UserSettings) error {
+	if r.Language != nil {
+		settings.Language = strings.ToLower(strings.TrimSpace(*r.Language))
+	}
+	if r.DailyGoalMinutes != nil {
+		settings.DailyGoalMinutes = *r.DailyGoalMinutes
+	}
+	if r.ReminderTime != nil {
+		settings.ReminderTime = strings.TrimSpace(*r.ReminderTime)
+	}
+	if r.TimeZone != nil {
+		settings.TimeZone = strings.TrimSpace(*r.TimeZone)
+	}
+	if r.TargetJobRole != nil {
+		settings.TargetJobRole = strings.TrimSpace(*r.TargetJobRole)
+	}
+	if r.CEFRLevel != nil {
+		settings.CEFRLevel = strings.ToUpper(strings.TrimSpace(*r.CEFRLevel))
+	}
+	if r.TTSVoice != nil {
+		settings.TTSVoice = strings.ToLower(strings.TrimSpace(*r.TTSVoice))
+	}
+	if r.ReminderChannel != nil {
+		settings.ReminderChannel = strings.ToLower(strings.TrimSpace(*r.ReminderChannel))
+	}
+	if r.QuietHoursStart != nil {
+		settings.QuietHoursStart = strings.TrimSpace(*r.QuietHoursStart)
+	}
+	if r.QuietHoursEnd != nil {
+		settings.QuietHoursEnd = strings.TrimSpace(*r.QuietHoursEnd)
+	}
+	if r.HideFromLeaderboards != nil {
+		settings.HideFromLeaderboards = *r.HideFromLeaderboards
+	}
+	if r.PublicProfile != nil {
+		settings.PublicProfile = *r.PublicProfile
+	}
+	settings.Version = CurrentSettingsVersion
+	return settings.Validate()
+}
+
+// SettingsValidationError reports a settings value that is not allowed.
+type SettingsValidationError struct {
+	Message string
+}
+
+func (e *SettingsValidationError) Error() string {
+	return e.Message
+}
+
+func invalidSetting(format string, args ...interface{}) error {
+	return &SettingsValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// settingsChecks validate one field each. Validate reports the first
+// failure; reading a stored document resets failing fields to the default.
+var settingsChecks = []struct {
+	check func(s UserSettings) error
+	reset func(s *UserSettings, defaults UserSettings)
+}{
+	{
+		check: func(s UserSettings) error {
+			if !slices.Contains(SettingsLanguages, s.Language) {
+				return invalidSetting("invalid language: must be one of %s", strings.Join(SettingsLanguages, ", "))
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.Language = d.Language },
+	},
+	{
+		check: func(s UserSettings) error {
+			if s.DailyGoalMinutes < MinDailyGoalMinutes || s.DailyGoalMinutes > MaxDailyGoalMinutes {
+				return invalidSetting("invalid daily goal: must be between %d and %d minutes", MinDailyGoalMinutes, MaxDailyGoalMinutes)
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.DailyGoalMinutes = d.DailyGoalMinutes },
+	},
+	{
+		check: func(s UserSettings) error {
+			if s.ReminderTime != "" && !reminderTimePattern.MatchString(s.ReminderTime) {
+				return invalidSetting("invalid reminder time: must be HH:MM")
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.ReminderTime = d.ReminderTime },
+	},
+	{
+		check: func(s UserSettings) error {
+			// "" and "Local" load successfully but do not name a zone
+			if s.TimeZone == "" || s.TimeZone == "Local" {
+				return invalidSetting("invalid time zone: must be an IANA name such as %s", DefaultTimeZone)
+			}
+			if _, err := time.LoadLocation(s.TimeZone); err != nil {
+				return invalidSetting("invalid time zone: must be an IANA name such as %s", DefaultTimeZone)
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.TimeZone = d.TimeZone },
+	},
+	{
+		check: func(s UserSettings) error {
+			if utf8.RuneCountInString(s.TargetJobRole) > maxTargetJobRoleLen {
+				return invalidSetting("invalid target job role: must be at most %d characters", maxTargetJobRoleLen)
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.TargetJobRole = d.TargetJobRole },
+	},
+	{
+		check: func(s UserSettings) error {
+			if s.CEFRLevel != "" && !slices.Contains(SettingsCEFRLevels, s.CEFRLevel) {
+				return invalidSetting("invalid CEFR level: must be one of %s", strings.Join(SettingsCEFRLevels, ", "))
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.CEFRLevel = d.CEFRLevel },
+	},
+	{
+		check: func(s UserSettings) error {
+			if !slices.Contains(SettingsTTSVoices, s.TTSVoice) {
+				return invalidSetting("invalid TTS voice: must be one of %s", strings.Join(SettingsTTSVoices, ", "))
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.TTSVoice = d.TTSVoice },
+	},
+	{
+		check: func(s UserSettings) error {
+			if !slices.Contains(SettingsReminderChannels, s.ReminderChannel) {
+				return invalidSetting("invalid reminder channel: must be one of %s", strings.Join(SettingsReminderChannels, ", "))
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) { s.ReminderChannel = d.ReminderChannel },
+	},
+	{
+		check: func(s UserSettings) error {
+			if s.QuietHoursStart == "" && s.QuietHoursEnd == "" {
+				return nil
+			}
+			if !reminderTimePattern.MatchString(s.QuietHoursStart) || !reminderTimePattern.MatchString(s.QuietHoursEnd) {
+				return invalidSetting("invalid quiet hours: start and end must both be HH:MM, or both empty")
+			}
+			if s.QuietHoursStart == s.QuietHoursEnd {
+				return invalidSetting("invalid quiet hours: start and end must differ")
+			}
+			return nil
+		},
+		reset: func(s *UserSettings, d UserSettings) {
+			s.QuietHoursStart, s.QuietHoursEnd = d.QuietHoursStart, d.QuietHoursEnd
+		},
+	},
+}
+
+// Validate reports the first invalid field.
+func (s UserSettings) Validate() error {
+	for _, field := range settingsChecks {
+		if err := field.check(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InQuietHours reports whether the local clock time t ("HH:MM") falls in
+// the user's quiet hours.
+func (s UserSettings) InQuietHours(t string) bool {
+	if s.QuietHoursStart == "" || s.QuietHoursEnd == "" {
+		return false
+	}
+	// HH:MM strings compare in clock order
+	if s.QuietHoursStart < s.QuietHoursEnd {
+		return t >= s.QuietHoursStart && t < s.QuietHoursEnd
+	}
+	return t >= s.QuietHoursStart || t < s.QuietHoursEnd
+}
+
+// Location returns the user's time zone, falling back to the default zone.
+func (s UserSettings) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil && s.TimeZone != "" {
+		return loc
+	}
+	loc, err := time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Day returns the calendar day containing t in the user's time zone, as
+// midnight UTC of that date. Streak days are stored this way so that days of
+// users in different zones compare and step (AddDate) the same way.
+func (s UserSettings) Day(t time.Time) time.Time {
+	local := t.In(s.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// settingsMigrations[i] upgrades a stored settings document from version i to i+1.
+var settingsMigrations = []func(doc map[string]interface{}){
+	// 0 -> 1: free-form map written by clients before settings were typed.
+	// Known keys are kept (under their new names); everything else is dropped.
+	func(doc map[string]interface{}) {
+		renames := map[string]string{
+			"timezone":   "time_zone",
+			"daily_goal": "daily_goal_minutes",
+			"level":      "cefr_level",
+		}
+		for from, to := range renames {
+			if value, ok := doc[from]; ok {
+				if _, exists := doc[to]; !exists {
+					doc[to] = value
+				}
+			}
+		}
+		known := []string{"language", "daily_goal_minutes", "reminder_time", "time_zone", "target_job_role", "cefr_level", "tts_voice"}
+		for key := range doc {
+			if !slices.Contains(known, key) {
+				delete(doc, key)
+			}
+		}
+	},
+	// 1 -> 2: reminder channel and quiet hours. Quiet hours stay off for users
+	// whose reminder time falls in the default ones, so their reminders keep coming.
+	func(doc map[string]interface{}) {
+		defaults := DefaultSettings()
+		if _, ok := doc["reminder_channel"]; !ok {
+			doc["reminder_channel"] = defaults.ReminderChannel
+		}
+		_, hasStart := doc["quiet_hours_start"]
+		_, hasEnd := doc["quiet_hours_end"]
+		if hasStart || hasEnd {
+			return
+		}
+		reminderTime, ok := doc["reminder_time"].(string)
+		if !ok {
+			reminderTime = defaults.ReminderTime
+		}
+		if reminderTime != "" && defaults.InQuietHours(reminderTime) {
+			doc["quiet_hours_start"], doc["quiet_hours_end"] = "", ""
+			return
+		}
+		doc["quiet_hours_start"], doc["quiet_hours_end"] = defaults.QuietHoursStart, defaults.QuietHoursEnd
+	},
+}
+
+// UnmarshalBSON migrates settings stored with an older schema. Values that
+// are missing or no longer valid fall back to their defaults, so reading a
+// user never fails because of a bad settings document.
+func (s *UserSettings) UnmarshalBSON(data []byte) error {
+	var doc map[string]interface{}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*s = SettingsFromDocument(doc)
+	return nil
+}
+
+// SettingsFromDocument migrates a raw settings document to the current
+// version and reads it leniently.
+func SettingsFromDocument(doc map[string]interface{}) UserSettings {
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	version, _ := documentInt(doc, "version")
+	for v := version; v >= 0 && v < len(settingsMigrations); v++ {
+		settingsMigrations[v](doc)
+	}
+
+	defaults := DefaultSettings()
+	settings := defaults
+	if value, ok := doc["language"].(string); ok {
+		settings.Language = strings.ToLower(value)
+	}
+	if value, ok := documentInt(doc, "daily_goal_minutes"); ok {
+		settings.DailyGoalMinutes = value
+	}
+	if value, ok := doc["reminder_time"].(string); ok {
+		settings.ReminderTime = value
+	}
+	if value, ok := doc["time_zone"].(string); ok {
+		settings.TimeZone = value
+	}
+	if value, ok := doc["target_job_role"].(string); ok {
+		settings.TargetJobRole = value
+	}
+	if value, ok := doc["cefr_level"].(string); ok {
+		settings.CEFRLevel = strings.ToUpper(value)
+	}
+	if value, ok := doc["tts_voice"].(string); ok {
+		settings.TTSVoice = strings.ToLower(value)
+	}
+	if value, ok := doc["reminder_channel"].(string); ok {
+		settings.ReminderChannel = strings.ToLower(value)
+	}
+	if value, ok := doc["quiet_hours_start"].(string); ok {
+		settings.QuietHoursStart = value
+	}
+	if value, ok := doc["quiet_hours_end"].(string); ok {
+		settings.QuietHoursEnd = value
+	}
+	if value, ok := doc["hide_from_leaderboards"].(bool); ok {
+		settings.HideFromLeaderboards = value
+	}
+	if value, ok := doc["public_profile"].(bool); ok {
+		settings.PublicProfile = value
+	}
+
+	for _, field := range settingsChecks {
+		if field.check(settings) != nil {
+			field.reset(&settings, defaults)
+		}
+	}
+	return settings
+}
+
+func documentInt(doc map[string]interface{}, key string) (int, bool) {
+	switch value := doc[key].(type) {
+	case int32:
+		return int(value), true
+	case int64:
+		return int(value), true
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,174 @@
+// internal/handler/account_handler.go
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/usecase"
+)
+
+type AccountHandler struct {
+	accountUsecase usecase.AccountUsecase
+}
+
+func NewAccountHandler(accountUsecase usecase.AccountUsecase) *AccountHandler {
+	return &AccountHandler{accountUsecase: accountUsecase}
+}
+
+// ExportData godoc
+// @Summary      Export my data
+// @Description  Download everything stored about the authenticated user as a JSON document, or as a ZIP archive with one JSON file per collection. Password hashes, two-factor secrets and one-time tokens are left out.
+// @Tags         Users
+// @Produce      json
+// @Produce      application/zip
+// @Param        format query string false "Archive format" Enums(json, zip) default(json)
+// @Success      200 {file} file
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/export [get]
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "format must be json or zip"})
+		return
+	}
+
+	export, err := h.accountUsecase.ExportData(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("lissanai-export-%s-%s", userID.Hex(), export.ExportedAt.Format("20060102"))
+	c.Header("Cache-Control", "no-store")
+
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.IndentedJSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := writeExportZip(c.Writer, export); err != nil {
+		// Headers are already sent; the client sees a truncated archive
+		c.Error(err)
+	}
+}
+
+// writeExportZip writes a manifest plus one JSON file per collection.
+func writeExportZip(w http.ResponseWriter, export *domain.UserDataExport) error {
+	archive := zip.NewWriter(w)
+
+	names := make([]string, 0, len(export.Collections))
+	for name := range export.Collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := map[string]interface{}{
+		"user_id":     export.UserID,
+		"exported_at": export.ExportedAt,
+		"collections": names,
+	}
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := writeZipJSON(archive, name+".json", export.Collections[name]); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// DeleteAccount godoc
+// @Summary      Delete user account
+// @Description  Delete the authenticated user's account and all data stored about it: interview sessions, learning progress, quiz submissions, streak activity, devices and tokens. When a grace period is configured the deletion is only scheduled and every device is logged out; logging in again and calling /users/me/restore before the date cancels it.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.AccountDeletionResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	response, err := h.accountUsecase.DeleteAccount(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreAccount godoc
+// @Summary      Restore account
+// @Description  Cancel a scheduled account deletion during its grace period
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/restore [post]
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	if err := h.accountUsecase.RestoreAccount(userID); err != nil {
+		if err.Error() == "account is not scheduled for deletion" {
+			c.JSON(http.StatusConflict, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Account restored"})
+}
@@ -0,0 +1,49 @@
+// internal/handler/achievement_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/service"
+)
+
+type AchievementHandler struct {
+	achievementService *service.AchievementService
+}
+
+func NewAchievementHandler(achievementService *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+// GetAchievements godoc
+// @Summary      Get achievements
+// @Description  Get every achievement badge with whether the authenticated user earned it and their progress towards it. Titles and descriptions are in the user's language.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.AchievementsResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/achievements [get]
+func (h *AchievementHandler) GetAchievements(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	achievements, err := h.achievementService.GetAchievements(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get achievements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, achievements)
+}
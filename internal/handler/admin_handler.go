@@ -0,0 +1,695 @@
+// internal/handler/admin_handler.go
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/usecase"
+)
+
+type AdminHandler struct {
+	adminUsecase usecase.AdminUsecase
+}
+
+func NewAdminHandler(adminUsecase usecase.AdminUsecase) *AdminHandler {
+	return &AdminHandler{adminUsecase: adminUsecase}
+}
+
+// SearchUsers godoc
+// @Summary      Search users
+// @Description  Search users by name or email, optionally filtered by role and suspension state. Admin only.
+// @Tags         Admin
+// @Produce      json
+// @Param        q query string false "Name or email contains"
+// @Param        role query string false "Role" Enums(learner, content_editor, admin)
+// @Param        suspended query bool false "Suspension state"
+// @Param        page query int false "Page (default 1)"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Success      200 {object} domain.UserSearchResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/users [get]
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	filter := domain.UserSearchFilter{
+		Query: c.Query("q"),
+		Role:  c.Query("role"),
+	}
+	if suspendedStr := c.Query("suspended"); suspendedStr != "" {
+		suspended, err := strconv.ParseBool(suspendedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "invalid suspended filter"})
+			return
+		}
+		filter.Suspended = &suspended
+	}
+	page, limit := pagination(c)
+
+	response, err := h.adminUsecase.SearchUsers(actor, filter, page, limit)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user
+// @Description  Suspend an account and log it out of every device. Suspended users cannot log in or refresh tokens. Admin only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Param        request body domain.SuspendUserRequest true "Suspension reason"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.adminUsecase.SuspendUser(actor, c.Param("id"), &req); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "User suspended successfully"})
+}
+
+// UnsuspendUser godoc
+// @Summary      Lift a suspension
+// @Description  Allow a suspended user to log in again. Admin only.
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/unsuspend [post]
+func (h *AdminHandler) UnsuspendUser(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUsecase.UnsuspendUser(actor, c.Param("id")); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "User unsuspended successfully"})
+}
+
+// UpdateUserRoles godoc
+// @Summary      Set user roles
+// @Description  Replace the roles of a user. Takes effect when the user's access token is next refreshed. Admin only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Param        request body domain.UpdateRolesRequest true "Roles"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/roles [put]
+func (h *AdminHandler) UpdateUserRoles(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.adminUsecase.UpdateUserRoles(actor, c.Param("id"), &req); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Roles updated successfully"})
+}
+
+// CreateLearningPath godoc
+// @Summary      Create a learning path
+// @Description  Content editors and admins only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.LearningPathRequest true "Learning path"
+// @Success      201 {object} domain.LearningPath
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/paths [post]
+func (h *AdminHandler) CreateLearningPath(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.LearningPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	path, err := h.adminUsecase.CreateLearningPath(actor, &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, path)
+}
+
+// UpdateLearningPath godoc
+// @Summary      Update a learning path
+// @Description  Content editors and admins only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Learning path ID"
+// @Param        request body domain.LearningPathRequest true "Learning path"
+// @Success      200 {object} domain.LearningPath
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/paths/{id} [put]
+func (h *AdminHandler) UpdateLearningPath(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.LearningPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	path, err := h.adminUsecase.UpdateLearningPath(actor, c.Param("id"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, path)
+}
+
+// DeleteLearningPath godoc
+// @Summary      Delete a learning path
+// @Description  Delete an empty learning path; its lessons must be deleted first. Content editors and admins only.
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "Learning path ID"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/paths/{id} [delete]
+func (h *AdminHandler) DeleteLearningPath(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUsecase.DeleteLearningPath(actor, c.Param("id")); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Learning path deleted successfully"})
+}
+
+// CreateLesson godoc
+// @Summary      Create a lesson
+// @Description  Add a lesson to a learning path. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.LessonRequest true "Lesson"
+// @Success      201 {object} domain.Lesson
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/lessons [post]
+func (h *AdminHandler) CreateLesson(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.LessonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	lesson, err := h.adminUsecase.CreateLesson(actor, &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, lesson)
+}
+
+// UpdateLesson godoc
+// @Summary      Update a lesson
+// @Description  Changing path_id moves the lesson to another learning path. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Lesson ID"
+// @Param        request body domain.LessonRequest true "Lesson"
+// @Success      200 {object} domain.Lesson
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/lessons/{id} [put]
+func (h *AdminHandler) UpdateLesson(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.LessonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	lesson, err := h.adminUsecase.UpdateLesson(actor, c.Param("id"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// DeleteLesson godoc
+// @Summary      Delete a lesson
+// @Description  Delete a lesson together with its quiz. Content editors and admins only.
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "Lesson ID"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/lessons/{id} [delete]
+func (h *AdminHandler) DeleteLesson(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUsecase.DeleteLesson(actor, c.Param("id")); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Lesson deleted successfully"})
+}
+
+// CreateQuiz godoc
+// @Summary      Create a quiz
+// @Description  Attach a quiz to a lesson; a lesson has at most one quiz. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.QuizRequest true "Quiz"
+// @Success      201 {object} domain.Quiz
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/quizzes [post]
+func (h *AdminHandler) CreateQuiz(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	quiz, err := h.adminUsecase.CreateQuiz(actor, &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quiz)
+}
+
+// UpdateQuiz godoc
+// @Summary      Update a quiz
+// @Description  Replace a quiz's title and questions. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Quiz ID"
+// @Param        request body domain.QuizRequest true "Quiz"
+// @Success      200 {object} domain.Quiz
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/quizzes/{id} [put]
+func (h *AdminHandler) UpdateQuiz(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	quiz, err := h.adminUsecase.UpdateQuiz(actor, c.Param("id"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+// DeleteQuiz godoc
+// @Summary      Delete a quiz
+// @Description  Content editors and admins only
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "Quiz ID"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/learning/quizzes/{id} [delete]
+func (h *AdminHandler) DeleteQuiz(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminUsecase.DeleteQuiz(actor, c.Param("id")); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Quiz deleted successfully"})
+}
+
+// ListAuditLogs godoc
+// @Summary      List audit log entries
+// @Description  Most recent admin actions first. Admin only.
+// @Tags         Admin
+// @Produce      json
+// @Param        actor_id query string false "Filter by acting user ID"
+// @Param        action query string false "Filter by action, e.g. user.suspend"
+// @Param        target_id query string false "Filter by target ID"
+// @Param        page query int false "Page (default 1)"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Success      200 {object} domain.AuditLogListResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	filter := domain.AuditLogFilter{
+		Action:   c.Query("action"),
+		TargetID: c.Query("target_id"),
+	}
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		actorID, err := primitive.ObjectIDFromHex(actorIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "invalid actor_id"})
+			return
+		}
+		filter.ActorID = &actorID
+	}
+	page, limit := pagination(c)
+
+	response, err := h.adminUsecase.ListAuditLogs(actor, filter, page, limit)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListJobs godoc
+// @Summary      List scheduled jobs
+// @Description  Background jobs with their cron schedule (UTC), next and last run, the instance running them now, and their 10 most recent runs. Admin only.
+// @Tags         Admin
+// @Produce      json
+// @Success      200 {array}  domain.ScheduledJob
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/jobs [get]
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.adminUsecase.ListJobs(actor)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// RunJob godoc
+// @Summary      Run a job now
+// @Description  Start a scheduled job immediately, in the background, without changing its schedule. Follow the run in the job list. Admin only.
+// @Tags         Admin
+// @Produce      json
+// @Param        name path string true "Job name, e.g. streak-expiry"
+// @Success      202 {object} domain.JobRun
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/jobs/{name}/run [post]
+func (h *AdminHandler) RunJob(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.adminUsecase.RunJob(actor, c.Param("name"))
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListPrompts godoc
+// @Summary      List AI prompts
+// @Description  Every prompt the AI features use, with all its versions and locale variants and the rollout deciding which version users get. Content editors and admins only.
+// @Tags         Admin
+// @Produce      json
+// @Success      200 {array}  domain.Prompt
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/prompts [get]
+func (h *AdminHandler) ListPrompts(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	prompts, err := h.adminUsecase.ListPrompts(actor)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prompts)
+}
+
+// CreatePromptTemplate godoc
+// @Summary      Add a prompt template
+// @Description  Without a version, adds the next version of the prompt; it serves nobody until the rollout or an experiment names it. With a version, adds a locale variant of it, e.g. "am". Templates use Go template syntax over the prompt's variables. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        name    path string                       true "Prompt name, e.g. grammar"
+// @Param        request body domain.PromptTemplateRequest true "Template"
+// @Success      201 {object} domain.PromptTemplate
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/prompts/{name}/templates [post]
+func (h *AdminHandler) CreatePromptTemplate(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	template, err := h.adminUsecase.CreatePromptTemplate(actor, c.Param("name"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdatePromptRollout godoc
+// @Summary      Set a prompt's rollout
+// @Description  Serve a version of the prompt (0 for the highest version in the prompt files), optionally with an experiment splitting signed-in users between versions by weight. Leaving out the experiment ends it. Content editors and admins only.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        name    path string                      true "Prompt name, e.g. grammar"
+// @Param        request body domain.PromptRolloutRequest true "Rollout"
+// @Success      200 {object} domain.PromptRollout
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/prompts/{name}/rollout [put]
+func (h *AdminHandler) UpdatePromptRollout(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PromptRolloutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	rollout, err := h.adminUsecase.UpdatePromptRollout(actor, c.Param("name"), &req)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rollout)
+}
+
+// auditActor identifies the authenticated admin for the audit log.
+func auditActor(c *gin.Context) (domain.AuditActor, bool) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return domain.AuditActor{}, false
+	}
+	return domain.AuditActor{UserID: userID, IPAddress: c.ClientIP()}, true
+}
+
+// pagination reads the page and limit query parameters; the usecase applies defaults.
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return page, limit
+}
+
+func adminError(c *gin.Context, err error) {
+	var invalidPrompt *domain.PromptValidationError
+	if errors.As(err, &invalidPrompt) {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	switch err.Error() {
+	case "user not found", "learning path not found", "lesson not found", "quiz not found", "job not found",
+		"prompt not found", "prompt version not found":
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+	case "invalid role", "a quiz cannot be moved to another lesson":
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+	case "cannot suspend your own account", "cannot remove your own admin role":
+		c.JSON(http.StatusForbidden, domain.ErrorResponse{Error: err.Error()})
+	case "learning path still has lessons", "lesson already has a quiz", "job is already running",
+		"prompt version already exists":
+		c.JSON(http.StatusConflict, domain.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+	}
+}
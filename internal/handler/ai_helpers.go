@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/llm"
+	"lissanai.com/backend/internal/service"
+)
+
+// promptAudience identifies who AI prompts are rendered for: the signed-in
+// user, if any, and the language the client asks for.
+func promptAudience(c *gin.Context) service.PromptAudience {
+	audience := service.PromptAudience{Locale: acceptLanguage(c)}
+	if userID, exists := c.Get("user_id"); exists {
+		audience.UserID, _ = userID.(string)
+	}
+	return audience
+}
+
+// promptContext is the request context carrying the prompt audience.
+func promptContext(c *gin.Context) context.Context {
+	return service.WithPromptAudience(c.Request.Context(), promptAudience(c))
+}
+
+// aiCacheContext is promptContext that also records whether the AI reply
+// came from the cache, for setAICacheHeader.
+func aiCacheContext(c *gin.Context) (context.Context, *service.AICacheStatus) {
+	return service.WithAICacheStatus(promptContext(c))
+}
+
+// setAICacheHeader tells the client where the AI reply came from: X-Cache is
+// HIT, MISS, or SHARED for a reply made for an identical concurrent request.
+func setAICacheHeader(c *gin.Context, status *service.AICacheStatus) {
+	if *status != "" {
+		c.Header("X-Cache", string(*status))
+	}
+}
+
+// freshAIReply reports whether the AI reply was made for this request. Only
+// those count as activity: cached and shared replies are often retries, and
+// resubmitting the same text must not earn XP.
+func freshAIReply(status *service.AICacheStatus) bool {
+	return *status == service.AICacheMiss
+}
+
+// acceptLanguage returns the primary language of the first Accept-Language
+// entry, e.g. "am" for "am-ET,am;q=0.9".
+func acceptLanguage(c *gin.Context) string {
+	first, _, _ := strings.Cut(c.GetHeader("Accept-Language"), ",")
+	first, _, _ = strings.Cut(first, ";")
+	language, _, _ := strings.Cut(strings.TrimSpace(first), "-")
+	if language == "*" {
+		return ""
+	}
+	return strings.ToLower(language)
+}
+
+// respondInvalidAIOutput answers 502 when the model kept replying with JSON
+// that did not fit the expected shape, even after being asked again.
+func respondInvalidAIOutput(c *gin.Context, err error) bool {
+	if !errors.Is(err, llm.ErrInvalidOutput) {
+		return false
+	}
+	c.JSON(http.StatusBadGateway, gin.H{"error": "The AI returned an invalid answer, please try again"})
+	return true
+}
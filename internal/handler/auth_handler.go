@@ -2,11 +2,16 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"lissanai.com/backend/internal/domain"
 	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/service"
 	"lissanai.com/backend/internal/usecase"
 )
 
@@ -43,6 +48,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	response, err := h.authUsecase.Register(&req)
 	if err != nil {
@@ -59,14 +66,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user with email and password
+// @Description  Authenticate user with email and password. If the account has two-factor authentication enabled, the response only carries mfa_required and an mfa_token to exchange at /auth/mfa/verify.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        credentials body domain.LoginRequest true "User Login Credentials"
-// @Success      200 {object} domain.AuthResponse
+// @Success      200 {object} domain.LoginResponse
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
@@ -74,9 +83,18 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	response, err := h.authUsecase.Login(&req)
 	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		if err.Error() == "account suspended" {
+			c.JSON(http.StatusForbidden, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -86,14 +104,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // SocialAuth godoc
 // @Summary      Social authentication
-// @Description  Authenticate or register user using social providers (Google, Apple)
+// @Description  Authenticate or register user using social providers (Google, Apple, Facebook). The provider token is verified with the provider before any account is created or linked. Accounts with two-factor authentication get an mfa_token, as with password login.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        socialInfo body domain.SocialAuthRequest true "Social Authentication Information"
-// @Success      200 {object} domain.AuthResponse
+// @Success      200 {object} domain.LoginResponse
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
 // @Router       /auth/social [post]
 func (h *AuthHandler) SocialAuth(c *gin.Context) {
 	var req domain.SocialAuthRequest
@@ -101,9 +120,57 @@ func (h *AuthHandler) SocialAuth(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	response, err := h.authUsecase.SocialAuth(&req)
 	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedProvider) {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err.Error() == "account suspended" {
+			c.JSON(http.StatusForbidden, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyMFA godoc
+// @Summary      Complete two-factor login
+// @Description  Exchange the mfa_token from login and an authenticator or recovery code for access and refresh tokens
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFAVerifyRequest true "MFA token and code"
+// @Success      200 {object} domain.AuthResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
+// @Router       /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req domain.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	response, err := h.authUsecase.VerifyMFA(&req)
+	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		if err.Error() == "account suspended" {
+			c.JSON(http.StatusForbidden, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -143,7 +210,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary      Refresh access token
-// @Description  Use refresh token to get a new access token
+// @Description  Exchange a refresh token for a new access token and a new refresh token. The presented refresh token is rotated and must not be used again; replaying it revokes the whole session.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -151,6 +218,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 // @Success      200 {object} domain.TokenResponse
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
+// @Failure      403 {object} domain.ErrorResponse
 // @Router       /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req domain.RefreshTokenRequest
@@ -158,9 +226,15 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	response, err := h.authUsecase.RefreshToken(&req)
 	if err != nil {
+		if err.Error() == "account suspended" {
+			c.JSON(http.StatusForbidden, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -177,6 +251,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Param        email body domain.ForgotPasswordRequest true "User Email"
 // @Success      200 {object} domain.MessageResponse
 // @Failure      400 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
 // @Router       /auth/forgot-password [post]
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	var req domain.ForgotPasswordRequest
@@ -184,9 +259,13 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
 
 	err := h.authUsecase.ForgotPassword(&req)
 	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -204,6 +283,7 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 // @Success      200 {object} domain.MessageResponse
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
 // @Router       /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req domain.ResetPasswordRequest
@@ -211,9 +291,13 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.IPAddress = c.ClientIP()
 
 	err := h.authUsecase.ResetPassword(&req)
 	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -221,6 +305,67 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Password reset successfully"})
 }
 
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirm the user's email address using the token from the verification email
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        token body domain.VerifyEmailRequest true "Verification Token"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err := h.authUsecase.VerifyEmail(&req)
+	if err != nil {
+		if err.Error() == "invalid or expired verification token" || err.Error() == "user not found" {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "invalid or expired verification token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link to an unverified account's email address
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        email body domain.ResendVerificationRequest true "User Email"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
+// @Router       /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req domain.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.IPAddress = c.ClientIP()
+
+	err := h.authUsecase.ResendVerification(&req)
+	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "If the account exists and is unverified, a verification link has been sent"})
+}
+
 // GetProfile godoc
 // @Summary      Get user profile
 // @Description  Get the profile of the authenticated user
@@ -249,7 +394,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary      Update user profile
-// @Description  Update parts of the user's profile (name, settings)
+// @Description  Update parts of the user's profile (name, handle, settings). Only the settings fields present are changed; invalid values are rejected. The handle names the public profile at /u/{handle}, shown when settings.public_profile is on; it is 3-30 letters, digits or underscores and unique.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -258,6 +403,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
 // @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
 // @Security     BearerAuth
 // @Router       /users/me [patch]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
@@ -275,6 +421,23 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	user, err := h.userUsecase.UpdateProfile(userID, &req)
 	if err != nil {
+		var invalid *domain.SettingsValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err.Error() == "invalid handle: must be 3-30 letters, digits or underscores" {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err.Error() == "handle is already taken" {
+			c.JSON(http.StatusConflict, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err.Error() == "failed to update user" {
+			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -282,62 +445,95 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-// DeleteAccount godoc
-// @Summary      Delete user account
-// @Description  Allow a user to delete their account
+// AddPushToken godoc
+// @Summary      Register push token
+// @Description  Register a device token for push notifications. Provider says who issued it: "fcm" (default) or "apns" for iOS apps registering with APNs directly
 // @Tags         Users
+// @Accept       json
 // @Produce      json
+// @Param        tokenInfo body domain.PushTokenRequest true "Push Token Information"
 // @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
-// @Failure      404 {object} domain.ErrorResponse
 // @Security     BearerAuth
-// @Router       /users/me [delete]
-func (h *UserHandler) DeleteAccount(c *gin.Context) {
+// @Router       /users/me/push-token [post]
+func (h *UserHandler) AddPushToken(c *gin.Context) {
 	userID, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
 		return
 	}
 
-	err := h.userUsecase.DeleteAccount(userID)
+	var req domain.PushTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if sessionID, ok := middleware.GetSessionIDFromContext(c); ok {
+		req.SessionID = sessionID.Hex()
+	}
+
+	err := h.userUsecase.AddPushToken(userID, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Account deleted successfully"})
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Push token registered successfully"})
 }
 
-// AddPushToken godoc
-// @Summary      Register push token
-// @Description  Register a device token (FCM/APNs) for push notifications
+// RemovePushToken godoc
+// @Summary      Unregister push token
+// @Description  Stop sending push notifications to a device. Without a token in the body, the token registered by the current device is removed.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
-// @Param        tokenInfo body domain.PushTokenRequest true "Push Token Information"
+// @Param        tokenInfo body domain.RemovePushTokenRequest false "Push token to remove"
 // @Success      200 {object} domain.MessageResponse
 // @Failure      400 {object} domain.ErrorResponse
 // @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
 // @Security     BearerAuth
-// @Router       /users/me/push-token [post]
-func (h *UserHandler) AddPushToken(c *gin.Context) {
+// @Router       /users/me/push-token [delete]
+func (h *UserHandler) RemovePushToken(c *gin.Context) {
 	userID, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
 		return
 	}
 
-	var req domain.PushTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	// The body is optional
+	var req domain.RemovePushTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
 		return
 	}
+	if sessionID, ok := middleware.GetSessionIDFromContext(c); ok {
+		req.SessionID = sessionID.Hex()
+	}
 
-	err := h.userUsecase.AddPushToken(userID, &req)
+	err := h.userUsecase.RemovePushToken(userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		switch err.Error() {
+		case "user not found", "push token not found":
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Push token registered successfully"})
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Push token removed successfully"})
+}
+
+// respondThrottled answers 429 with a Retry-After header when err comes from
+// the brute-force limiter, and reports whether it did.
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttled *usecase.ThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, domain.ErrorResponse{Error: err.Error()})
+	return true
 }
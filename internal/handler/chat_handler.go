@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
 	"lissanai.com/backend/internal/domain/models"
 	"lissanai.com/backend/internal/middleware"
 	"lissanai.com/backend/internal/service"
@@ -15,12 +17,14 @@ import (
 type ChatHandler struct {
 	usecase       *usecase.ChatUsecase
 	streakService *service.StreakService
+	xpService     *service.XPService
 }
 
-func NewChatHandler(u *usecase.ChatUsecase, streakService *service.StreakService) *ChatHandler {
+func NewChatHandler(u *usecase.ChatUsecase, streakService *service.StreakService, xpService *service.XPService) *ChatHandler {
 	return &ChatHandler{
 		usecase:       u,
 		streakService: streakService,
+		xpService:     xpService,
 	}
 }
 
@@ -87,6 +91,7 @@ func (h *ChatHandler) GetNextQuestionHandler(c *gin.Context) {
 // @Success 200 {object} models.Feedback "Answer submitted successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 502 {object} models.ErrorResponse "The AI kept returning invalid output"
 // @Security BearerAuth
 // @Router /interview/answer [post]
 func (h *ChatHandler) SubmitAnswerHandler(c *gin.Context) {
@@ -97,7 +102,10 @@ func (h *ChatHandler) SubmitAnswerHandler(c *gin.Context) {
 		return
 	}
 
-	msg, err := h.usecase.SubmitAnswer(req.SessionID, req.Answer)
+	answer, err := h.usecase.SubmitAnswer(promptContext(c), req.SessionID, req.Answer)
+	if respondInvalidAIOutput(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -110,10 +118,15 @@ func (h *ChatHandler) SubmitAnswerHandler(c *gin.Context) {
 				log.Printf("Failed to record streak activity for user %s: %v", objectID.Hex(), err)
 				// Don't fail the request if streak recording fails
 			}
+			// Each question earns XP for its best answer, however often it is answered
+			reference := fmt.Sprintf("%s/%d", req.SessionID, answer.QuestionNumber)
+			if err := h.xpService.AwardScore(c.Request.Context(), objectID, domain.XPSourceInterviewScore, float64(answer.Feedback.ScorePercent), reference); err != nil {
+				log.Printf("Failed to award interview XP to user %s: %v", objectID.Hex(), err)
+			}
 		}
 	}
 
-	c.JSON(http.StatusOK, msg)
+	c.JSON(http.StatusOK, answer.Feedback)
 }
 
 // EndSessionHandler returns the final session summary
@@ -5,17 +5,37 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"lissanai.com/backend/internal/domain/entities"
 	"lissanai.com/backend/internal/domain/interfaces"
+	"lissanai.com/backend/internal/service"
 )
 
 // The struct and constructor remain the same.
 type EmailController struct {
-	emailUC interfaces.EmailUsecase
+	emailUC       interfaces.EmailUsecase
+	streakService *service.StreakService
 }
 
-func NewEmailController(emailUC interfaces.EmailUsecase) *EmailController {
-	return &EmailController{emailUC: emailUC}
+func NewEmailController(emailUC interfaces.EmailUsecase, streakService *service.StreakService) *EmailController {
+	return &EmailController{emailUC: emailUC, streakService: streakService}
+}
+
+// recordEmailDraft records the activity for signed-in users; anonymous
+// users may draft emails unless the verified-email policy gates them.
+func (ctrl *EmailController) recordEmailDraft(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		return
+	}
+	if err := ctrl.streakService.RecordActivity(c.Request.Context(), objectID, "email_draft"); err != nil {
+		log.Printf("Failed to record streak activity for user %s: %v", objectID.Hex(), err)
+		// Don't fail the request if streak recording fails
+	}
 }
 
 // --- HANDLER #1: GENERATE EMAIL ---
@@ -23,14 +43,18 @@ func NewEmailController(emailUC interfaces.EmailUsecase) *EmailController {
 
 // GenerateEmailHandler godoc
 // @Summary      Generate a new email
-// @Description  Generates a complete, professional email from a user's prompt (which can be in English or Amharic).
+// @Description  Generates a complete, professional email from a user's prompt (which can be in English or Amharic). Signing in is optional unless verified emails are required; signed-in drafts count as activity.
 // @Tags         Email
 // @Accept       json
 // @Produce      json
 // @Param        generateRequest  body      entities.GenerateEmailRequest  true  "The user's prompt and optional tone/template."
 // @Success      200              {object}  entities.EmailResponse
+// @Header       200              {string}  X-Cache "HIT, MISS, or SHARED with a concurrent identical request"
 // @Failure      400              {object}  object{error=string}
+// @Failure      401              {object}  object{error=string}  "Invalid token, or none when verified emails are required"
 // @Failure      500              {object}  object{error=string}
+// @Failure      502              {object}  object{error=string}
+// @Security     BearerAuth
 // @Router       /email/generate [post]
 func (ctrl *EmailController) GenerateEmailHandler(c *gin.Context) {
 	var req entities.GenerateEmailRequest
@@ -39,13 +63,21 @@ func (ctrl *EmailController) GenerateEmailHandler(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.emailUC.GenerateEmailFromPrompt(c.Request.Context(), &req)
+	ctx, cacheStatus := aiCacheContext(c)
+	response, err := ctrl.emailUC.GenerateEmailFromPrompt(ctx, &req)
+	if respondInvalidAIOutput(c, err) {
+		return
+	}
 	if err != nil {
 		log.Printf("!!! INTERNAL SERVER ERROR (GenerateEmail): %v !!!", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate email"})
 		return
 	}
+	if freshAIReply(cacheStatus) {
+		ctrl.recordEmailDraft(c)
+	}
 
+	setAICacheHeader(c, cacheStatus)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -54,14 +86,18 @@ func (ctrl *EmailController) GenerateEmailHandler(c *gin.Context) {
 
 // EditEmailHandler godoc
 // @Summary      Edit an existing email
-// @Description  Corrects and improves a user's drafted email to make it more professional.
+// @Description  Corrects and improves a user's drafted email to make it more professional. Signing in is optional unless verified emails are required; signed-in drafts count as activity.
 // @Tags         Email
 // @Accept       json
 // @Produce      json
 // @Param        editRequest  body      entities.EditEmailRequest  true  "The user's email draft and optional tone/template."
 // @Success      200          {object}  entities.EmailResponse
+// @Header       200          {string}  X-Cache "HIT, MISS, or SHARED with a concurrent identical request"
 // @Failure      400          {object}  object{error=string}
+// @Failure      401          {object}  object{error=string}  "Invalid token, or none when verified emails are required"
 // @Failure      500          {object}  object{error=string}
+// @Failure      502          {object}  object{error=string}
+// @Security     BearerAuth
 // @Router       /email/edit [post]
 func (ctrl *EmailController) EditEmailHandler(c *gin.Context) {
 	var req entities.EditEmailRequest
@@ -70,12 +106,20 @@ func (ctrl *EmailController) EditEmailHandler(c *gin.Context) {
 		return
 	}
 
-	response, err := ctrl.emailUC.EditEmailDraft(c.Request.Context(), &req)
+	ctx, cacheStatus := aiCacheContext(c)
+	response, err := ctrl.emailUC.EditEmailDraft(ctx, &req)
+	if respondInvalidAIOutput(c, err) {
+		return
+	}
 	if err != nil {
 		log.Printf("!!! INTERNAL SERVER ERROR (EditEmail): %v !!!", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to edit email"})
 		return
 	}
+	if freshAIReply(cacheStatus) {
+		ctrl.recordEmailDraft(c)
+	}
 
+	setAICacheHeader(c, cacheStatus)
 	c.JSON(http.StatusOK, response)
 }
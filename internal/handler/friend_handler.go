@@ -0,0 +1,163 @@
+// internal/handler/friend_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/service"
+)
+
+type FriendHandler struct {
+	friendService *service.FriendService
+}
+
+func NewFriendHandler(friendService *service.FriendService) *FriendHandler {
+	return &FriendHandler{friendService: friendService}
+}
+
+// ListFriends godoc
+// @Summary      List friends
+// @Description  Get the authenticated user's friends, the friend requests sent to them and the requests they sent.
+// @Tags         Leaderboards
+// @Produce      json
+// @Success      200 {object} domain.FriendsResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/friends [get]
+func (h *FriendHandler) ListFriends(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	friends, err := h.friendService.ListFriends(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get friends"})
+		return
+	}
+
+	c.JSON(http.StatusOK, friends)
+}
+
+// SendFriendRequest godoc
+// @Summary      Send friend request
+// @Description  Ask the user with this email to be friends. If they already asked the authenticated user, their request is accepted instead.
+// @Tags         Leaderboards
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.FriendRequest true "Email of the user to add"
+// @Success      201 {object} domain.Friendship
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/friends [post]
+func (h *FriendHandler) SendFriendRequest(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req domain.FriendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	friendship, err := h.friendService.SendRequest(c.Request.Context(), userID, req.Email)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+		case "cannot add yourself as a friend":
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		case "already friends", "friend request already sent":
+			c.JSON(http.StatusConflict, domain.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to send friend request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, friendship)
+}
+
+// AcceptFriendRequest godoc
+// @Summary      Accept friend request
+// @Description  Accept a friend request sent to the authenticated user.
+// @Tags         Leaderboards
+// @Produce      json
+// @Param        id path string true "Friendship ID"
+// @Success      200 {object} domain.Friendship
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/friends/{id}/accept [post]
+func (h *FriendHandler) AcceptFriendRequest(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	friendshipID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: "friend request not found"})
+		return
+	}
+
+	friendship, err := h.friendService.Accept(c.Request.Context(), userID, friendshipID)
+	if err != nil {
+		if err.Error() == "friend request not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to accept friend request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, friendship)
+}
+
+// RemoveFriend godoc
+// @Summary      Remove friend
+// @Description  Remove a friend, or decline or withdraw a friend request.
+// @Tags         Leaderboards
+// @Produce      json
+// @Param        id path string true "Friendship ID"
+// @Success      200 {object} domain.SuccessResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/friends/{id} [delete]
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	friendshipID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: "friendship not found"})
+		return
+	}
+
+	if err := h.friendService.Remove(c.Request.Context(), userID, friendshipID); err != nil {
+		if err.Error() == "friendship not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to remove friend"})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{Message: "Friend removed"})
+}
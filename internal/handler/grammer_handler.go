@@ -35,8 +35,10 @@ type GrammarRequest struct {
 // @Produce      json
 // @Param        text body GrammarRequest true "Text to be checked"
 // @Success      200 {object} models.GrammarResponse "Returns corrected text and explanation"
+// @Header       200 {string} X-Cache "HIT, MISS, or SHARED with a concurrent identical request"
 // @Failure      400 {object} object{error=string}
 // @Failure      500 {object} object{error=string}
+// @Failure      502 {object} object{error=string} "The AI kept returning invalid output"
 // @Security BearerAuth
 // @Router       /grammar/check [post]
 func (h *GrammarHandler) GrammarCheck(c *gin.Context) {
@@ -47,14 +49,18 @@ func (h *GrammarHandler) GrammarCheck(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grammarUsecase.CheckGrammar(request.Text)
+	ctx, cacheStatus := aiCacheContext(c)
+	resp, err := h.grammarUsecase.CheckGrammar(ctx, request.Text)
+	if respondInvalidAIOutput(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Record streak activity for grammar check
-	if userID, exists := c.Get("user_id"); exists {
+	// Record streak activity for grammar check, unless the reply was reused
+	if userID, exists := c.Get("user_id"); exists && freshAIReply(cacheStatus) {
 		if objectID, err := primitive.ObjectIDFromHex(userID.(string)); err == nil {
 			if err := h.streakService.RecordActivity(c.Request.Context(), objectID, "grammar_check"); err != nil {
 				log.Printf("Failed to record streak activity for user %s: %v", objectID.Hex(), err)
@@ -63,5 +69,6 @@ func (h *GrammarHandler) GrammarCheck(c *gin.Context) {
 		}
 	}
 
+	setAICacheHeader(c, cacheStatus)
 	c.JSON(http.StatusOK, resp)
 }
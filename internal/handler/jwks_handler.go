@@ -0,0 +1,28 @@
+// internal/handler/jwks_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/service"
+)
+
+type JWKSHandler struct {
+	jwtService service.JWTService
+}
+
+func NewJWKSHandler(jwtService service.JWTService) *JWKSHandler {
+	return &JWKSHandler{jwtService: jwtService}
+}
+
+// GetJWKS serves the public keys that verify LissanAI access tokens, so other
+// services can check tokens without sharing a secret. Tokens carry the kid of
+// the key that signed them; keys retired by a rotation stay listed until the
+// tokens they signed have expired. The same keys sign refresh and mfa tokens,
+// so verifiers must also require the "typ" claim to be "access".
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Short cache so a rotation is picked up quickly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.PublicKeys())
+}
@@ -15,12 +15,14 @@ import (
 type LearningHandler struct {
 	learningUsecase usecase.LearningUsecase
 	streakService   *service.StreakService
+	xpService       *service.XPService
 }
 
-func NewLearningHandler(learningUsecase usecase.LearningUsecase, streakService *service.StreakService) *LearningHandler {
+func NewLearningHandler(learningUsecase usecase.LearningUsecase, streakService *service.StreakService, xpService *service.XPService) *LearningHandler {
 	return &LearningHandler{
 		learningUsecase: learningUsecase,
 		streakService:   streakService,
+		xpService:       xpService,
 	}
 }
 
@@ -325,6 +327,11 @@ func (h *LearningHandler) SubmitQuiz(c *gin.Context) {
 		return
 	}
 
+	// A quiz earns XP for its best score, however often it is retaken
+	if err := h.xpService.AwardScore(c.Request.Context(), userOID, domain.XPSourceQuizScore, result.Percentage, result.QuizID); err != nil {
+		log.Printf("Failed to award quiz XP to user %s: %v", userOID.Hex(), err)
+	}
+
 	// Record streak activity if quiz was passed
 	if result.Passed {
 		if err := h.streakService.RecordActivity(c.Request.Context(), userOID, "quiz_passed"); err != nil {
@@ -0,0 +1,167 @@
+// internal/handler/mfa_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/usecase"
+)
+
+type MFAHandler struct {
+	mfaUsecase usecase.MFAUsecase
+}
+
+func NewMFAHandler(mfaUsecase usecase.MFAUsecase) *MFAHandler {
+	return &MFAHandler{mfaUsecase: mfaUsecase}
+}
+
+// Enroll godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and the otpauth:// provisioning URI to show as a QR code. Nothing changes until the enrollment is confirmed.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.MFAEnrollResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	response, err := h.mfaUsecase.Enroll(userID)
+	if err != nil {
+		mfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Confirm godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Turn on two-factor authentication with a code from the authenticator app. Returns the recovery codes; they are shown only once.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFACodeRequest true "Authenticator code"
+// @Success      200 {object} domain.MFARecoveryCodesResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      409 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/mfa/confirm [post]
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response, err := h.mfaUsecase.Confirm(userID, &req)
+	if err != nil {
+		mfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Disable godoc
+// @Summary      Turn off two-factor authentication
+// @Description  Requires a current authenticator code or a recovery code
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFACodeRequest true "Authenticator or recovery code"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.mfaUsecase.Disable(userID, &req); err != nil {
+		mfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes. Requires a current authenticator code or a recovery code.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFACodeRequest true "Authenticator or recovery code"
+// @Success      200 {object} domain.MFARecoveryCodesResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      429 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response, err := h.mfaUsecase.RegenerateRecoveryCodes(userID, &req)
+	if err != nil {
+		mfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func mfaError(c *gin.Context, err error) {
+	if respondThrottled(c, err) {
+		return
+	}
+	switch err.Error() {
+	case "invalid verification code", "two-factor enrollment not started", "two-factor authentication is not enabled":
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+	case "two-factor authentication is already enabled":
+		c.JSON(http.StatusConflict, domain.ErrorResponse{Error: err.Error()})
+	case "user not found":
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,83 @@
+// internal/handler/profile_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/service"
+)
+
+// Public profiles and their images can be cached this long (seconds)
+const publicProfileMaxAge = 3600
+
+type ProfileHandler struct {
+	profileService *service.ProfileService
+}
+
+func NewProfileHandler(profileService *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// GetPublicProfile serves the streaks and badges of a user who turned on the
+// public_profile setting, by their handle. Name and email are never shown.
+func (h *ProfileHandler) GetPublicProfile(c *gin.Context) {
+	profile, err := h.profileService.GetPublicProfile(c.Request.Context(), c.Param("handle"))
+	if err != nil {
+		if err.Error() == "profile not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get profile"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(publicProfileMaxAge))
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetPublicCalendarSVG serves a public profile's activity calendar for the
+// year in ?year= (default this year) as an SVG image to embed or share, with
+// the user's streaks and badges. Days are shaded by how many activities they
+// had; what the activities were is not shown.
+func (h *ProfileHandler) GetPublicCalendarSVG(c *gin.Context) {
+	h.getPublicCalendar(c, "svg")
+}
+
+// GetPublicCalendarPNG serves the same image as GetPublicCalendarSVG as a
+// PNG, for sites that do not show SVG.
+func (h *ProfileHandler) GetPublicCalendarPNG(c *gin.Context) {
+	h.getPublicCalendar(c, "png")
+}
+
+func (h *ProfileHandler) getPublicCalendar(c *gin.Context, format string) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	calendar, card, err := h.profileService.GetPublicCalendar(c.Request.Context(), c.Param("handle"), year)
+	if err != nil {
+		if err.Error() == "profile not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get activity calendar"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(publicProfileMaxAge))
+	writeCalendarImage(c, format, calendar, card)
+}
+
+// writeCalendarImage renders the calendar as an "svg" or "png" image.
+func writeCalendarImage(c *gin.Context, format string, calendar *domain.ActivityCalendarResponse, card service.CalendarCard) {
+	if format == "png" {
+		image, err := service.RenderCalendarPNG(calendar, card)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to render activity calendar"})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", image)
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", service.RenderCalendarSVG(calendar, card))
+}
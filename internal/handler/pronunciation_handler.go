@@ -25,14 +25,17 @@ func NewPronunciationHandler(uc interfaces.PronunciationUsecase) *PronunciationH
 // @Tags         Pronunciation
 // @Produce      json
 // @Success      200 {object} entities.PracticeSentence
+// @Header       200 {string} X-Cache "HIT, MISS, or SHARED with a concurrent identical request"
 // @Failure      500 {object} object{error=string} "Returns an error if the AI service fails to generate a sentence."
 // @Router       /pronunciation/sentence [get]
 func (h *PronunciationHandler) GetSentences(c *gin.Context) {
-	sentences, err := h.pronunciationUC.GetPracticeSentence(c.Request.Context())
+	ctx, cacheStatus := aiCacheContext(c)
+	sentences, err := h.pronunciationUC.GetPracticeSentence(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate practice sentence"})
 		return
 	}
+	setAICacheHeader(c, cacheStatus)
 	c.JSON(http.StatusOK, sentences)
 }
 
@@ -47,6 +50,7 @@ func (h *PronunciationHandler) GetSentences(c *gin.Context) {
 // @Success      200          {object}  entities.PronunciationFeedback
 // @Failure      400          {object}  object{error=string} "Returns an error if the form data is invalid or missing."
 // @Failure      500          {object}  object{error=string} "Returns an error if the AI service fails during assessment."
+// @Failure      502          {object}  object{error=string} "Returns an error if the AI kept returning invalid output."
 // @Router       /pronunciation/assess [post]
 func (h *PronunciationHandler) AssessPronunciation(c *gin.Context) {
 	targetText := c.PostForm("target_text")
@@ -83,7 +87,10 @@ func (h *PronunciationHandler) AssessPronunciation(c *gin.Context) {
 
 	log.Printf("Received request to assess pronunciation for text: '%s' with audio size %d bytes.", targetText, len(audioData))
 
-	feedback, err := h.pronunciationUC.AssessPronunciation(c.Request.Context(), targetText, audioData, audioMimeType)
+	feedback, err := h.pronunciationUC.AssessPronunciation(promptContext(c), targetText, audioData, audioMimeType)
+	if respondInvalidAIOutput(c, err) {
+		return
+	}
 	if err != nil {
 		log.Printf("Error from pronunciation usecase: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assess pronunciation"})
@@ -0,0 +1,112 @@
+// internal/handler/session_handler.go
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/repository"
+	"lissanai.com/backend/internal/usecase"
+)
+
+type SessionHandler struct {
+	sessionUsecase usecase.SessionUsecase
+}
+
+func NewSessionHandler(sessionUsecase usecase.SessionUsecase) *SessionHandler {
+	return &SessionHandler{sessionUsecase: sessionUsecase}
+}
+
+// ListSessions godoc
+// @Summary      List active sessions
+// @Description  List the devices the authenticated user is logged in on. The session of the calling token is marked as current.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {array} domain.SessionResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	currentSessionID, _ := middleware.GetSessionIDFromContext(c)
+
+	sessions, err := h.sessionUsecase.ListSessions(userID, currentSessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Log out a single device. Its refresh tokens stop working and its push token is removed.
+// @Tags         Users
+// @Produce      json
+// @Param        id path string true "Session ID"
+// @Success      200 {object} domain.MessageResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	err := h.sessionUsecase.RevokeSession(userID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Session revoked successfully"})
+}
+
+// RevokeOtherSessions godoc
+// @Summary      Log out everywhere else
+// @Description  Revoke every session of the authenticated user except the one making the request
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.MessageResponse
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      500 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/sessions [delete]
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	currentSessionID, _ := middleware.GetSessionIDFromContext(c)
+
+	err := h.sessionUsecase.RevokeOtherSessions(userID, currentSessionID)
+	if err != nil {
+		if err.Error() == "current session unknown, please log in again" {
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{Message: "Logged out of all other sessions"})
+}
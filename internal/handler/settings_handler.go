@@ -0,0 +1,93 @@
+// internal/handler/settings_handler.go
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/service"
+)
+
+type SettingsHandler struct {
+	settingsService service.SettingsService
+}
+
+func NewSettingsHandler(settingsService service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService}
+}
+
+// GetSettings godoc
+// @Summary      Get settings
+// @Description  Get the authenticated user's settings. Users who never changed them get the defaults.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} domain.UserSettings
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/settings [get]
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	settings, err := h.settingsService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings godoc
+// @Summary      Update settings
+// @Description  Change some of the authenticated user's settings. Only the fields present are changed. Language is en or am, daily goal 5-240 minutes, reminder time HH:MM in the user's time zone (empty turns reminders off), time zone an IANA name, CEFR level A1-C2, TTS voice female or male, reminder channel auto, push or email, quiet hours HH:MM start and end (both empty turns them off), and hide_from_leaderboards keeps the user out of leagues and friends' leaderboards. public_profile publishes streaks, badges and the activity calendar at /u/{handle} once the user has a handle.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        settings body domain.UpdateSettingsRequest true "Settings to change"
+// @Success      200 {object} domain.UserSettings
+// @Failure      400 {object} domain.ErrorResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/settings [patch]
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req domain.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		var invalid *domain.SettingsValidationError
+		switch {
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: err.Error()})
+		case err.Error() == "user not found":
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to update settings"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
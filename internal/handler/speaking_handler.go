@@ -86,7 +86,7 @@ func (h *ConversationHandler) HandleConversation(c *gin.Context) {
 	defer conn.Close()
 	log.Println("Client connected. Session will auto-terminate in 3 minutes.")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	ctx, cancel := context.WithTimeout(service.WithPromptAudience(context.Background(), promptAudience(c)), 3*time.Minute)
 	defer cancel()
 
 	msgChan := make(chan message)
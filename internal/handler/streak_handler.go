@@ -21,7 +21,7 @@ func NewStreakHandler(streakService *service.StreakService) *StreakHandler {
 }
 
 // @Summary Get user streak information
-// @Description Get the current user's streak information including current streak, longest streak, and freeze status
+// @Description Get the current user's streak information including current streak, longest streak, and freeze status. Days are counted in the time zone from the user's settings
 // @Tags Streak
 // @Produce json
 // @Success 200 {object} domain.StreakInfo
@@ -52,7 +52,7 @@ func (h *StreakHandler) GetStreakInfo(c *gin.Context) {
 }
 
 // @Summary Freeze streak
-// @Description Freeze the user's current streak to prevent it from being lost due to inactivity (limited uses per month)
+// @Description Use a streak freeze from the inventory for today, so the streak survives without practice today. Freezes are otherwise used automatically for missed days; they are earned at every 7-day milestone and granted monthly, up to the inventory size
 // @Tags Streak
 // @Accept json
 // @Produce json
@@ -91,14 +91,46 @@ func (h *StreakHandler) FreezeStreak(c *gin.Context) {
 	c.JSON(http.StatusOK, domain.SuccessResponse{Message: "Streak frozen successfully! 🧊"})
 }
 
+// @Summary Get streak freezes
+// @Description Get the user's streak freeze inventory and the history of freezes earned and used, newest first
+// @Tags Streak
+// @Produce json
+// @Success 200 {object} domain.StreakFreezesResponse
+// @Failure 401 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/streak/freezes [get]
+func (h *StreakHandler) GetStreakFreezes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	freezes, err := h.streakService.GetStreakFreezes(c.Request.Context(), objectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "Failed to get streak freezes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, freezes)
+}
+
 // @Summary Record activity (Internal)
-// @Description Record a user activity to maintain their streak - this is called internally by other services
+// @Description Record a user activity to maintain their streak, for manual testing by admins. Features record their own activities; manual ones earn no badges or XP
 // @Tags Streak
 // @Accept json
 // @Produce json
 // @Param activity_type query string true "Type of activity" Enums(lesson_completed, quiz_passed, daily_goal_met)
 // @Success 200 {object} domain.SuccessResponse
 // @Failure 401 {object} domain.ErrorResponse
+// @Failure 403 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/streak/activity [post]
@@ -136,7 +168,7 @@ func (h *StreakHandler) RecordActivity(c *gin.Context) {
 		return
 	}
 
-	err = h.streakService.RecordActivity(c.Request.Context(), objectID, activityType)
+	err = h.streakService.RecordManualActivity(c.Request.Context(), objectID, activityType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "Failed to record activity"})
 		return
@@ -146,11 +178,15 @@ func (h *StreakHandler) RecordActivity(c *gin.Context) {
 }
 
 // @Summary Get activity calendar
-// @Description Get GitHub-like activity calendar showing daily learning activities
+// @Description Get GitHub-like activity calendar showing daily learning activities, as JSON or as an SVG or PNG image shaded by activity count. Dates are days in the time zone from the user's settings; days without activity that a streak freeze covered are marked frozen
 // @Tags Streak
 // @Produce json
+// @Produce image/svg+xml
+// @Produce image/png
 // @Param year query int false "Year (default: current year)" example(2025)
+// @Param format query string false "json (default), or svg or png for an image of the calendar" Enums(json, svg, png)
 // @Success 200 {object} domain.ActivityCalendarResponse
+// @Failure 400 {object} domain.ErrorResponse
 // @Failure 401 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Security BearerAuth
@@ -176,11 +212,21 @@ func (h *StreakHandler) GetActivityCalendar(c *gin.Context) {
 		}
 	}
 
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "svg" && format != "png" {
+		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Error: "Invalid format: must be json, svg or png"})
+		return
+	}
+
 	calendar, err := h.streakService.GetActivityCalendar(c.Request.Context(), objectID, year)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "Failed to get activity calendar"})
 		return
 	}
 
+	if format != "json" {
+		writeCalendarImage(c, format, calendar, service.CalendarCard{})
+		return
+	}
 	c.JSON(http.StatusOK, calendar)
 }
\ No newline at end of file
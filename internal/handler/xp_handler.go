@@ -0,0 +1,104 @@
+// internal/handler/xp_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/middleware"
+	"lissanai.com/backend/internal/service"
+)
+
+type XPHandler struct {
+	xpService *service.XPService
+}
+
+func NewXPHandler(xpService *service.XPService) *XPHandler {
+	return &XPHandler{xpService: xpService}
+}
+
+// GetXP godoc
+// @Summary      Get XP
+// @Description  Get the authenticated user's total XP, the XP earned this week, their league and their 20 most recent XP awards. Weeks start on Monday at midnight UTC.
+// @Tags         Leaderboards
+// @Produce      json
+// @Success      200 {object} domain.XPSummaryResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/me/xp [get]
+func (h *XPHandler) GetXP(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	summary, err := h.xpService.GetXPSummary(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get XP"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetLeagueLeaderboard godoc
+// @Summary      Get league leaderboard
+// @Description  Get this week's leaderboard of the authenticated user's league group, which they join with their first XP of the week. When the week ends the ranks up to promotion_rank move up a league and those from demotion_rank move down. Users who hide themselves from leaderboards get no entries.
+// @Tags         Leaderboards
+// @Produce      json
+// @Success      200 {object} domain.LeagueLeaderboardResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Failure      404 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaderboards/league [get]
+func (h *XPHandler) GetLeagueLeaderboard(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	leaderboard, err := h.xpService.GetLeagueLeaderboard(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, domain.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
+
+// GetFriendsLeaderboard godoc
+// @Summary      Get friends leaderboard
+// @Description  Rank the authenticated user and their friends by the XP earned this week. Friends who hide themselves from leaderboards are left out.
+// @Tags         Leaderboards
+// @Produce      json
+// @Success      200 {object} domain.FriendsLeaderboardResponse
+// @Failure      401 {object} domain.ErrorResponse
+// @Security     BearerAuth
+// @Router       /leaderboards/friends [get]
+func (h *XPHandler) GetFriendsLeaderboard(c *gin.Context) {
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	leaderboard, err := h.xpService.GetFriendsLeaderboard(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Error: "failed to get leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
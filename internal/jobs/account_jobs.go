@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"lissanai.com/backend/internal/repository"
+	"lissanai.com/backend/internal/usecase"
+)
+
+type AccountJobs struct {
+	accountUsecase        usecase.AccountUsecase
+	refreshTokenRepo      repository.RefreshTokenRepository
+	passwordResetRepo     repository.PasswordResetRepository
+	emailVerificationRepo repository.EmailVerificationRepository
+}
+
+func NewAccountJobs(
+	accountUsecase usecase.AccountUsecase,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	emailVerificationRepo repository.EmailVerificationRepository,
+) *AccountJobs {
+	return &AccountJobs{
+		accountUsecase:        accountUsecase,
+		refreshTokenRepo:      refreshTokenRepo,
+		passwordResetRepo:     passwordResetRepo,
+		emailVerificationRepo: emailVerificationRepo,
+	}
+}
+
+// Register schedules the cleanup of expired tokens and, when deleted
+// accounts have a grace period, the purge of accounts whose period has ended
+func (j *AccountJobs) Register(scheduler *Scheduler, purgeDeletions bool) error {
+	cleanups := []struct {
+		name, spec, description string
+		run                     func() error
+	}{
+		{"expired-password-resets", "15 * * * *", "Delete expired password reset tokens", j.passwordResetRepo.DeleteExpiredResets},
+		{"expired-refresh-tokens", "30 3 * * *", "Delete expired refresh tokens", j.refreshTokenRepo.DeleteExpiredRefreshTokens},
+		{"expired-email-verifications", "45 3 * * *", "Delete expired email verification tokens", j.emailVerificationRepo.DeleteExpiredVerifications},
+	}
+	for _, cleanup := range cleanups {
+		run := cleanup.run
+		err := scheduler.Register(cleanup.name, cleanup.spec, cleanup.description, func(context.Context) error {
+			return run()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !purgeDeletions {
+		return nil
+	}
+	return scheduler.Register("account-deletion-purge", "20 * * * *",
+		"Delete accounts whose deletion grace period has ended",
+		j.purgeDueDeletions)
+}
+
+func (j *AccountJobs) purgeDueDeletions(context.Context) error {
+	purged, err := j.accountUsecase.PurgeDueDeletions()
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Purged %d deleted accounts", purged)
+	}
+	return nil
+}
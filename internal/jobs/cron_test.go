@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+// at parses a UTC time written as "2006-01-02 15:04".
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2026-01-01 is a Thursday
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want string
+	}{
+		{"every minute starts after the current one", "* * * * *", "2026-01-01 10:07", "2026-01-01 10:08"},
+		{"step", "*/15 * * * *", "2026-01-01 10:07", "2026-01-01 10:15"},
+		{"step into the next hour", "*/15 * * * *", "2026-01-01 10:45", "2026-01-01 11:00"},
+		{"step from a value", "5/20 * * * *", "2026-01-01 10:26", "2026-01-01 10:45"},
+		{"range", "0 3 * * 1-5", "2026-01-02 04:00", "2026-01-05 03:00"},
+		{"range with step", "10-30/10 * * * *", "2026-01-01 10:31", "2026-01-01 11:10"},
+		{"list", "0 9,17 * * *", "2026-01-01 09:30", "2026-01-01 17:00"},
+		{"list of ranges", "0 0 1-2,20-21 * *", "2026-01-03 00:00", "2026-01-20 00:00"},
+		{"0 is Sunday", "0 0 * * 0", "2026-01-01 00:00", "2026-01-04 00:00"},
+		{"7 is Sunday", "0 0 * * 7", "2026-01-01 00:00", "2026-01-04 00:00"},
+		{"day of month only", "0 0 13 * *", "2026-01-01 00:00", "2026-01-13 00:00"},
+		{"day of week only", "0 0 * * 5", "2026-01-03 00:00", "2026-01-09 00:00"},
+		{"both day fields: day of week first", "0 0 13 * 5", "2026-01-01 00:00", "2026-01-02 00:00"},
+		{"both day fields: day of month first", "0 0 13 * 5", "2026-01-10 00:00", "2026-01-13 00:00"},
+		{"@hourly", "@hourly", "2026-01-01 10:07", "2026-01-01 11:00"},
+		{"@daily", "@daily", "2026-01-01 10:07", "2026-01-02 00:00"},
+		{"@weekly", "@weekly", "2026-01-01 10:07", "2026-01-04 00:00"},
+		{"@monthly", "@monthly", "2026-01-15 10:07", "2026-02-01 00:00"},
+		{"@yearly", "@yearly", "2026-03-01 10:07", "2027-01-01 00:00"},
+		{"month rollover skips short months", "30 23 31 * *", "2026-01-31 23:30", "2026-03-31 23:30"},
+		{"year rollover", "0 0 1 1 *", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"last minute of the year", "59 23 31 12 *", "2026-12-31 23:59", "2027-12-31 23:59"},
+		{"leap day", "0 0 29 2 *", "2026-01-01 00:00", "2028-02-29 00:00"},
+		{"month", "0 0 1 6 *", "2026-07-01 00:00", "2027-06-01 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.spec, err)
+			}
+			if got, want := schedule.Next(at(t, tt.from)), at(t, tt.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format("2006-01-02 15:04 Mon"), want.Format("2006-01-02 15:04 Mon"))
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextIsUTC(t *testing.T) {
+	schedule, err := parseCron("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addisAbaba := time.FixedZone("EAT", 3*60*60)
+	from := time.Date(2026, 1, 1, 2, 30, 0, 0, addisAbaba) // 2025-12-31 23:30 UTC
+	if got, want := schedule.Next(from), at(t, "2026-01-01 00:00"); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", from, got, want)
+	}
+}
+
+func TestParseCronRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"unknown descriptor", "@reboot"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "* 24 * * *"},
+		{"day of month 0", "0 0 0 * *"},
+		{"month out of range", "* * * 13 *"},
+		{"day of week out of range", "* * * * 8"},
+		{"zero step", "*/0 * * * *"},
+		{"negative step", "*/-5 * * * *"},
+		{"reversed range", "30-10 * * * *"},
+		{"not a number", "a * * * *"},
+		{"empty list entry", "1,,2 * * * *"},
+		{"never runs: 30 February", "0 0 30 2 *"},
+		{"never runs: 31 April", "0 0 31 4 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if schedule, err := parseCron(tt.spec); err == nil {
+				t.Errorf("parseCron(%q) accepted, next run %s", tt.spec, schedule.Next(time.Now()))
+			}
+		})
+	}
+}
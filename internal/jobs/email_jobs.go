@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lissanai.com/backend/internal/service"
+)
+
+// The outbox is polled this often even without a wake-up, which picks up
+// retries and messages queued by other instances.
+const emailPollInterval = 15 * time.Second
+
+type EmailJobs struct {
+	dispatcher *service.EmailDispatcher
+}
+
+func NewEmailJobs(dispatcher *service.EmailDispatcher) *EmailJobs {
+	return &EmailJobs{
+		dispatcher: dispatcher,
+	}
+}
+
+// StartOutboxWorker delivers queued emails as they arrive
+func (j *EmailJobs) StartOutboxWorker(ctx context.Context) {
+	go j.runOutboxWorker(ctx)
+
+	log.Println("📧 Email outbox worker started")
+}
+
+func (j *EmailJobs) runOutboxWorker(ctx context.Context) {
+	ticker := time.NewTicker(emailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping email outbox worker")
+			return
+		case <-ticker.C:
+		case <-j.dispatcher.Woken():
+		}
+
+		if _, err := j.dispatcher.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error delivering queued emails: %v", err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"lissanai.com/backend/internal/service"
+)
+
+type LeagueJobs struct {
+	xpService *service.XPService
+}
+
+func NewLeagueJobs(xpService *service.XPService) *LeagueJobs {
+	return &LeagueJobs{
+		xpService: xpService,
+	}
+}
+
+// Register schedules the weekly league rollover, just after weeks end on
+// Monday at midnight UTC
+func (j *LeagueJobs) Register(scheduler *Scheduler) error {
+	return scheduler.Register("league-rollover", "1 0 * * 1",
+		"Rank last week's league groups and promote and demote their members",
+		j.xpService.RolloverLeagues)
+}
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lissanai.com/backend/internal/service"
+)
+
+// Prompt templates and rollouts stored by the content team reach every
+// instance within this long.
+const promptReloadInterval = time.Minute
+
+type PromptJobs struct {
+	registry *service.PromptRegistry
+}
+
+func NewPromptJobs(registry *service.PromptRegistry) *PromptJobs {
+	return &PromptJobs{
+		registry: registry,
+	}
+}
+
+// StartReloader keeps this instance's prompts in sync with the database.
+// Each instance reloads its own, so this is not a scheduled job.
+func (j *PromptJobs) StartReloader(ctx context.Context) {
+	go j.runReloader(ctx)
+
+	log.Println("📝 Prompt reloader started")
+}
+
+func (j *PromptJobs) runReloader(ctx context.Context) {
+	ticker := time.NewTicker(promptReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping prompt reloader")
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.registry.Reload(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error reloading prompts: %v", err)
+		}
+	}
+}
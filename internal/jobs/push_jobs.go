@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lissanai.com/backend/internal/service"
+)
+
+// The outbox is polled this often even without a wake-up, which picks up
+// retries and notifications queued by other instances.
+const pushPollInterval = 15 * time.Second
+
+type PushJobs struct {
+	dispatcher *service.PushDispatcher
+}
+
+func NewPushJobs(dispatcher *service.PushDispatcher) *PushJobs {
+	return &PushJobs{
+		dispatcher: dispatcher,
+	}
+}
+
+// StartPushWorker delivers queued push notifications as they arrive
+func (j *PushJobs) StartPushWorker(ctx context.Context) {
+	go j.runPushWorker(ctx)
+
+	log.Println("🔔 Push notification worker started")
+}
+
+func (j *PushJobs) runPushWorker(ctx context.Context) {
+	ticker := time.NewTicker(pushPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping push notification worker")
+			return
+		case <-ticker.C:
+		case <-j.dispatcher.Woken():
+		}
+
+		if _, err := j.dispatcher.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error delivering push notifications: %v", err)
+		}
+	}
+}
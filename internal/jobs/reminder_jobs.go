@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lissanai.com/backend/internal/service"
+)
+
+// Reminders go out within this long after each user's reminder time
+const reminderCheckInterval = 5 * time.Minute
+
+type ReminderJobs struct {
+	reminderService *service.ReminderService
+}
+
+func NewReminderJobs(reminderService *service.ReminderService) *ReminderJobs {
+	return &ReminderJobs{
+		reminderService: reminderService,
+	}
+}
+
+// StartReminderScheduler sends daily practice reminders at each user's local reminder time
+func (j *ReminderJobs) StartReminderScheduler(ctx context.Context) {
+	go j.runReminderScheduler(ctx)
+
+	log.Println("⏰ Practice reminder scheduler started")
+}
+
+func (j *ReminderJobs) runReminderScheduler(ctx context.Context) {
+	ticker := time.NewTicker(reminderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping practice reminder scheduler")
+			return
+		case <-ticker.C:
+			sent, err := j.reminderService.SendDueReminders(ctx)
+			if err != nil {
+				log.Printf("Error sending practice reminders: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("Sent %d practice reminders", sent)
+			}
+		}
+	}
+}
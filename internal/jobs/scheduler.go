@@ -0,0 +1,296 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+const (
+	// Due jobs are started within this long of their scheduled minute
+	schedulerPollInterval = 20 * time.Second
+	// A job's lease is renewed while it runs; if its instance dies, another
+	// one takes over once the lease has expired
+	jobLeaseDuration = 5 * time.Minute
+	// Runs listed with each job
+	recentJobRuns = 10
+)
+
+// Scheduler runs registered jobs on cron schedules. The next run of each
+// job is stored in MongoDB, so schedules survive restarts and a run missed
+// while no instance was up happens once on startup. Every instance polls,
+// but a job only runs on the instance that takes its lease.
+type Scheduler struct {
+	repo     repository.JobRepository
+	instance string
+
+	mu      sync.Mutex
+	ctx     context.Context
+	jobs    map[string]*job
+	order   []string
+	running map[string]bool
+}
+
+type job struct {
+	name     string
+	schedule *cronSchedule
+	run      func(ctx context.Context) error
+}
+
+func NewScheduler(repo repository.JobRepository) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		instance: instanceID(),
+		ctx:      context.Background(),
+		jobs:     make(map[string]*job),
+		running:  make(map[string]bool),
+	}
+}
+
+// instanceID names this process in leases and run history.
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Register adds a job run on the cron expression spec (UTC). Registering
+// under an existing name replaces the job's schedule.
+func (s *Scheduler) Register(name, spec, description string, run func(ctx context.Context) error) error {
+	schedule, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.EnsureJob(name, spec, description, schedule.Next(time.Now())); err != nil {
+		return fmt.Errorf("failed to register job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; !ok {
+		s.order = append(s.order, name)
+	}
+	s.jobs[name] = &job{name: name, schedule: schedule, run: run}
+	return nil
+}
+
+// Start runs due jobs until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	go s.runScheduler(ctx)
+
+	log.Printf("🗓️ Job scheduler started with %d jobs on instance %s", len(s.order), s.instance)
+}
+
+func (s *Scheduler) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.startDueJobs()
+
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping job scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) startDueJobs() {
+	s.mu.Lock()
+	names := append([]string(nil), s.order...)
+	s.mu.Unlock()
+
+	for _, name := range names {
+		if _, err := s.start(name, domain.JobTriggerSchedule, ""); err != nil && !errors.Is(err, errJobBusy) {
+			log.Printf("Error starting job %s: %v", name, err)
+		}
+	}
+}
+
+var errJobBusy = errors.New("job is already running")
+
+// start takes the job's lease and runs it in the background. Scheduled
+// starts only happen when the job is due; they return nil, nil otherwise.
+func (s *Scheduler) start(name, trigger, triggeredBy string) (*domain.JobRun, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, errors.New("job not found")
+	}
+	if s.running[name] {
+		s.mu.Unlock()
+		return nil, errJobBusy
+	}
+	s.running[name] = true
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		delete(s.running, name)
+		s.mu.Unlock()
+	}
+
+	now := time.Now()
+	scheduled := trigger == domain.JobTriggerSchedule
+	acquired, err := s.repo.AcquireLease(name, s.instance, now, now.Add(jobLeaseDuration), scheduled)
+	if err != nil || !acquired {
+		release()
+		if err != nil {
+			return nil, err
+		}
+		if scheduled {
+			// Not due, or running elsewhere
+			return nil, nil
+		}
+		return nil, errJobBusy
+	}
+
+	run := &domain.JobRun{
+		Job:         name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Instance:    s.instance,
+		Status:      domain.JobRunRunning,
+		StartedAt:   now,
+	}
+	if err := s.repo.CreateRun(run); err != nil {
+		log.Printf("Failed to record run of job %s: %v", name, err)
+	}
+
+	go func() {
+		defer release()
+		s.execute(ctx, j, run)
+	}()
+	return run, nil
+}
+
+// execute runs a job while renewing its lease and records the outcome.
+func (s *Scheduler) execute(ctx context.Context, j *job, run *domain.JobRun) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go s.renewLease(runCtx, cancel, j.name)
+
+	log.Printf("🗓️ Running job %s (%s)", j.name, run.Trigger)
+	err := runJob(runCtx, j)
+	if cause := context.Cause(runCtx); errors.Is(cause, repository.ErrLeaseLost) {
+		err = cause
+	}
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMS = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = domain.JobRunSucceeded
+	if err != nil {
+		run.Status = domain.JobRunFailed
+		run.Error = err.Error()
+		log.Printf("Error running job %s: %v", j.name, err)
+	}
+
+	// A manual run leaves the schedule alone
+	var nextRunAt *time.Time
+	if run.Trigger == domain.JobTriggerSchedule {
+		next := j.schedule.Next(finished)
+		nextRunAt = &next
+	}
+	if err := s.repo.FinishJob(j.name, s.instance, run, nextRunAt); err != nil {
+		log.Printf("Failed to record outcome of job %s: %v", j.name, err)
+	}
+	if !run.ID.IsZero() {
+		if err := s.repo.FinishRun(run); err != nil {
+			log.Printf("Failed to record run of job %s: %v", j.name, err)
+		}
+	}
+}
+
+// runJob runs a job, turning a panic into an error so it cannot take the
+// scheduler down.
+func runJob(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run(ctx)
+}
+
+// renewLease keeps the job's lease while it runs. If another instance has
+// taken the lease over, which it may once the lease expires, the run is
+// cancelled so the job never runs twice at once.
+func (s *Scheduler) renewLease(ctx context.Context, cancel context.CancelCauseFunc, name string) {
+	ticker := time.NewTicker(jobLeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.repo.RenewLease(name, s.instance, time.Now().Add(jobLeaseDuration))
+			if errors.Is(err, repository.ErrLeaseLost) {
+				log.Printf("Lost the lease of job %s, stopping it", name)
+				cancel(err)
+				return
+			}
+			if err != nil {
+				log.Printf("Failed to renew lease of job %s: %v", name, err)
+			}
+		}
+	}
+}
+
+// Jobs returns the registered jobs with their state and recent runs.
+func (s *Scheduler) Jobs() ([]*domain.ScheduledJob, error) {
+	states, err := s.repo.GetJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	registered := make(map[string]bool, len(s.jobs))
+	for name := range s.jobs {
+		registered[name] = true
+	}
+	s.mu.Unlock()
+
+	jobs := make([]*domain.ScheduledJob, 0, len(registered))
+	for _, state := range states {
+		// Jobs no longer registered keep their state but are not listed
+		if !registered[state.Name] {
+			continue
+		}
+		runs, err := s.repo.GetRecentRuns(state.Name, recentJobRuns)
+		if err != nil {
+			return nil, err
+		}
+		state.RecentRuns = runs
+		jobs = append(jobs, state)
+	}
+	return jobs, nil
+}
+
+// Trigger runs a job now, in the background, unless it is already running
+// on any instance. triggeredBy names who asked for it.
+func (s *Scheduler) Trigger(name, triggeredBy string) (*domain.JobRun, error) {
+	return s.start(name, domain.JobTriggerManual, triggeredBy)
+}
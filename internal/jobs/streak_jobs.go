@@ -1,10 +1,6 @@
 package jobs
 
 import (
-	"context"
-	"log"
-	"time"
-
 	"lissanai.com/backend/internal/service"
 )
 
@@ -18,59 +14,19 @@ func NewStreakJobs(streakService *service.StreakService) *StreakJobs {
 	}
 }
 
-// StartStreakMaintenance starts background jobs for streak maintenance
-func (j *StreakJobs) StartStreakMaintenance(ctx context.Context) {
-	// Check for expired streaks every hour
-	go j.runExpiredStreakChecker(ctx)
-	
-	// Reset monthly freezes on the 1st of each month
-	go j.runMonthlyFreezeReset(ctx)
-	
-	log.Println("🔥 Streak maintenance jobs started")
-}
-
-func (j *StreakJobs) runExpiredStreakChecker(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Stopping expired streak checker")
-			return
-		case <-ticker.C:
-			if err := j.streakService.CheckAndUpdateExpiredStreaks(ctx); err != nil {
-				log.Printf("Error checking expired streaks: %v", err)
-			}
-		}
+// Register schedules streak maintenance: ending streaks that were not kept
+// up, and granting each user a streak freeze every month
+func (j *StreakJobs) Register(scheduler *Scheduler) error {
+	err := scheduler.Register("streak-expiry", "0 * * * *",
+		"Use freezes for missed days and reset streaks that ran out of them",
+		j.streakService.CheckAndUpdateExpiredStreaks)
+	if err != nil {
+		return err
 	}
-}
-
-func (j *StreakJobs) runMonthlyFreezeReset(ctx context.Context) {
-	// Calculate next first of month
-	now := time.Now()
-	nextMonth := now.AddDate(0, 1, 0)
-	firstOfNextMonth := time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
-	
-	// Wait until first of next month
-	time.Sleep(time.Until(firstOfNextMonth))
-	
-	// Then run monthly
-	ticker := time.NewTicker(24 * time.Hour * 30) // Approximately monthly
-	defer ticker.Stop()
 
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Stopping monthly freeze reset")
-			return
-		case <-ticker.C:
-			// Only reset on the 1st of the month
-			if time.Now().Day() == 1 {
-				if err := j.streakService.ResetMonthlyFreezes(ctx); err != nil {
-					log.Printf("Error resetting monthly freezes: %v", err)
-				}
-			}
-		}
-	}
-}
\ No newline at end of file
+	// Each month's freeze is granted once, so running daily also covers
+	// users who sign up during the month
+	return scheduler.Register("streak-freeze-grant", "5 0 * * *",
+		"Grant every active user this month's streak freeze",
+		j.streakService.GrantMonthlyFreezes)
+}
@@ -0,0 +1,160 @@
+// internal/llm/fake.go
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FakeReply is the fake provider's reply to text requests.
+const FakeReply = "This is a reply from the fake language model."
+
+// Models of the fake provider that fail, to try out failover
+const (
+	FakeModelRateLimited = "rate-limited"
+	FakeModelError       = "error"
+)
+
+// Fake is a deterministic provider for development and tests: it needs no
+// API key and gives the same reply to the same request. JSON requests get
+// an object built from the schema, with every field filled in.
+type Fake struct{}
+
+func NewFake() *Fake { return &Fake{} }
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) Generate(ctx context.Context, model string, req *Request) (*Response, error) {
+	switch model {
+	case FakeModelRateLimited:
+		return nil, &APIError{Provider: f.Name(), StatusCode: http.StatusTooManyRequests, Body: "fake rate limit"}
+	case FakeModelError:
+		return nil, &APIError{Provider: f.Name(), StatusCode: http.StatusInternalServerError, Body: "fake error"}
+	}
+
+	text := FakeReply
+	if req.JSON || req.Schema != nil {
+		value := interface{}(map[string]interface{}{})
+		if req.Schema != nil {
+			var schema interface{}
+			if err := json.Unmarshal(req.Schema, &schema); err != nil {
+				return nil, fmt.Errorf("invalid JSON schema: %w", err)
+			}
+			value = fakeValue(schema, "")
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	return &Response{Text: text, Provider: f.Name(), Model: model}, nil
+}
+
+// fakeValue returns a value that matches schema. name is the property the
+// value is for, used as the text of strings.
+func fakeValue(schema interface{}, name string) interface{} {
+	node, _ := schema.(map[string]interface{})
+	if enum, ok := node["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	schemaType := node["type"]
+	if types, ok := schemaType.([]interface{}); ok {
+		// The first type that is not null
+		schemaType = nil
+		for _, t := range types {
+			if t != "null" {
+				schemaType = t
+				break
+			}
+		}
+	}
+
+	switch schemaType {
+	case "object":
+		object := map[string]interface{}{}
+		properties, _ := node["properties"].(map[string]interface{})
+		for property, propertySchema := range properties {
+			object[property] = fakeValue(propertySchema, property)
+		}
+		return object
+	case "array":
+		count := 1
+		if minItems, ok := node["minItems"].(float64); ok && int(minItems) > count {
+			count = int(minItems)
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			items[i] = fakeValue(node["items"], name)
+		}
+		return items
+	case "integer", "number":
+		if minimum, ok := node["minimum"].(float64); ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	default:
+		if name == "" {
+			return "fake"
+		}
+		return "fake " + strings.ReplaceAll(name, "_", " ")
+	}
+}
+
+// NewFakeServer serves a provider through the OpenAI chat completions API
+// at /v1/chat/completions, so the OpenAI-compatible adapter can be run
+// against it locally. Failed requests are answered with the status of the
+// provider's APIError.
+func NewFakeServer(provider Provider) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var body openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		req := &Request{Temperature: body.Temperature, MaxTokens: body.MaxTokens}
+		req.JSON = body.ResponseFormat != nil && body.ResponseFormat.Type != "text"
+		for _, message := range body.Messages {
+			if message.Role == "system" {
+				req.System = message.Content
+			} else {
+				req.Prompt = message.Content
+			}
+		}
+		if req.JSON && body.ResponseFormat.JSONSchema != nil {
+			req.Schema = body.ResponseFormat.JSONSchema.Schema
+		} else if _, schema, ok := strings.Cut(req.System, jsonSchemaInstruction); ok {
+			req.Schema = json.RawMessage(schema)
+		}
+
+		resp, err := provider.Generate(r.Context(), body.Model, req)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				status = apiErr.StatusCode
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		reply := openAIResponse{Choices: []openAIChoice{{
+			Message:      openAIMessage{Role: "assistant", Content: resp.Text},
+			FinishReason: "stop",
+		}}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	})
+	return mux
+}
@@ -0,0 +1,174 @@
+// internal/llm/gemini.go
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// Gemini calls Google's Gemini API. It handles text, JSON output with a
+// response schema, and audio sent inline.
+type Gemini struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGemini(apiKey string) *Gemini {
+	return &Gemini{apiKey: apiKey, baseURL: geminiBaseURL, client: newHTTPClient()}
+}
+
+func (g *Gemini) Name() string { return "gemini" }
+
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+}
+
+type geminiInlineData struct {
+	MIMEType string `json:"mimeType"`
+	Data     []byte `json:"data"` // base64 in JSON
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature      *float64        `json:"temperature,omitempty"`
+	MaxOutputTokens  int             `json:"maxOutputTokens,omitempty"`
+	ResponseMIMEType string          `json:"responseMimeType,omitempty"`
+	ResponseSchema   json.RawMessage `json:"responseSchema,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+}
+
+func (g *Gemini) Generate(ctx context.Context, model string, req *Request) (*Response, error) {
+	parts := []geminiPart{{Text: req.Prompt}}
+	if req.Audio != nil {
+		parts = append(parts, geminiPart{InlineData: &geminiInlineData{MIMEType: req.Audio.MIMEType, Data: req.Audio.Data}})
+	}
+	body := geminiRequest{
+		Contents: []geminiContent{{Role: "user", Parts: parts}},
+		GenerationConfig: geminiGenerationConfig{
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.MaxTokens,
+		},
+	}
+	if req.System != "" {
+		body.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: req.System}}}
+	}
+	if req.JSON || req.Schema != nil {
+		body.GenerationConfig.ResponseMIMEType = "application/json"
+	}
+	if req.Schema != nil {
+		schema, err := geminiSchema(req.Schema)
+		if err != nil {
+			return nil, err
+		}
+		body.GenerationConfig.ResponseSchema = schema
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", g.baseURL, url.PathEscape(model))
+	var resp geminiResponse
+	err := postJSON(ctx, g.client, g.Name(), endpoint, map[string]string{"x-goog-api-key": g.apiKey}, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("gemini blocked the prompt: %s", resp.PromptFeedback.BlockReason)
+	}
+	if len(resp.Candidates) == 0 {
+		return nil, errors.New("gemini returned no candidates")
+	}
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("gemini returned no text (finish reason %s)", resp.Candidates[0].FinishReason)
+	}
+	return &Response{Text: text.String(), Provider: g.Name(), Model: model}, nil
+}
+
+// Gemini's response schema is an OpenAPI subset, so JSON Schema keywords it
+// does not know are dropped and types are spelled in capitals.
+var geminiSchemaKeys = map[string]bool{
+	"type": true, "format": true, "description": true, "nullable": true, "enum": true,
+	"properties": true, "required": true, "items": true, "minItems": true, "maxItems": true,
+	"minimum": true, "maximum": true, "propertyOrdering": true,
+}
+
+func geminiSchema(schema json.RawMessage) (json.RawMessage, error) {
+	var node interface{}
+	if err := json.Unmarshal(schema, &node); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return json.Marshal(toGeminiSchema(node))
+}
+
+func toGeminiSchema(node interface{}) interface{} {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+
+	converted := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if !geminiSchemaKeys[key] {
+			continue
+		}
+		switch key {
+		case "type":
+			// ["string", "null"] is a nullable string
+			if types, ok := value.([]interface{}); ok {
+				for _, t := range types {
+					if t == "null" {
+						converted["nullable"] = true
+					} else if name, ok := t.(string); ok {
+						converted["type"] = strings.ToUpper(name)
+					}
+				}
+				continue
+			}
+			if name, ok := value.(string); ok {
+				value = strings.ToUpper(name)
+			}
+		case "properties":
+			if properties, ok := value.(map[string]interface{}); ok {
+				convertedProperties := make(map[string]interface{}, len(properties))
+				for name, property := range properties {
+					convertedProperties[name] = toGeminiSchema(property)
+				}
+				value = convertedProperties
+			}
+		case "items":
+			value = toGeminiSchema(value)
+		}
+		converted[key] = value
+	}
+	return converted
+}
@@ -0,0 +1,105 @@
+// internal/llm/llm.go
+
+// Package llm talks to large language models through one interface. Each
+// provider (Gemini, OpenAI-compatible APIs such as Groq, and a local fake)
+// is an adapter; the Router picks the models for each feature from config
+// and fails over to the next one when a provider errors or rate-limits.
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Features that call a model. Each has its own list of models to try.
+const (
+	FeatureGrammar           = "grammar"
+	FeatureInterviewFeedback = "interview_feedback"
+	FeatureInterviewSummary  = "interview_summary"
+	FeatureEmail             = "email"
+	FeaturePracticeSentence  = "practice_sentence"
+	FeaturePronunciation     = "pronunciation"
+	FeatureConversation      = "conversation"
+)
+
+// Features lists every feature; the config must route each of them.
+var Features = []string{
+	FeatureGrammar,
+	FeatureInterviewFeedback,
+	FeatureInterviewSummary,
+	FeatureEmail,
+	FeaturePracticeSentence,
+	FeaturePronunciation,
+	FeatureConversation,
+}
+
+// Request is a single-turn generation request.
+type Request struct {
+	System string // instructions, sent separately where the API allows it
+	Prompt string
+	Audio  *Audio // optional recording to send with the prompt
+
+	// JSON asks for a single JSON object as the reply. Schema, when set, is
+	// the JSON Schema the object must follow.
+	JSON   bool
+	Schema json.RawMessage
+
+	Temperature *float64 // provider default when nil
+	MaxTokens   int      // provider default when 0
+}
+
+// Audio is a recording sent inline with a request.
+type Audio struct {
+	MIMEType string
+	Data     []byte
+}
+
+type Response struct {
+	Text     string
+	Provider string
+	Model    string
+}
+
+// Provider is one LLM API. Generate runs a request on one of the
+// provider's models.
+type Provider interface {
+	Name() string
+	Generate(ctx context.Context, model string, req *Request) (*Response, error)
+}
+
+// Client generates replies for a feature. It is what the rest of the app
+// depends on; the Router implements it.
+type Client interface {
+	Generate(ctx context.Context, feature string, req *Request) (*Response, error)
+}
+
+// ModelLister is implemented by clients that can say which models answer a
+// feature, as "provider:model" in the order they are tried.
+type ModelLister interface {
+	Models(feature string) []string
+}
+
+// ErrUnsupported is returned by a provider that cannot handle a request,
+// e.g. one with audio; the Router moves on to the next model.
+var ErrUnsupported = errors.New("request not supported by provider")
+
+// APIError is an error response from a provider's API.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // from the Retry-After header of a 429, if any
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API returned status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// RateLimited reports whether the provider refused the request for
+// exceeding a rate limit or quota.
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == 429
+}
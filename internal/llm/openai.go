@@ -0,0 +1,130 @@
+// internal/llm/openai.go
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Base URLs of OpenAI-compatible APIs
+const (
+	OpenAIBaseURL = "https://api.openai.com/v1"
+	GroqBaseURL   = "https://api.groq.com/openai/v1"
+)
+
+// OpenAICompatible calls a chat completions API in OpenAI's format, such as
+// OpenAI's own, Groq's or the fake server. It handles text and JSON output;
+// requests with audio are not supported.
+type OpenAICompatible struct {
+	name       string
+	apiKey     string
+	baseURL    string
+	client     *http.Client
+	jsonSchema bool
+}
+
+// NewOpenAICompatible creates an adapter called name for the API at baseURL.
+// apiKey may be empty for servers that do not check one.
+func NewOpenAICompatible(name, baseURL, apiKey string) *OpenAICompatible {
+	return &OpenAICompatible{
+		name:    name,
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  newHTTPClient(),
+	}
+}
+
+// WithJSONSchema sends schemas as a json_schema response format, for APIs
+// with structured outputs; otherwise they go in the instructions.
+func (o *OpenAICompatible) WithJSONSchema() *OpenAICompatible {
+	o.jsonSchema = true
+	return o
+}
+
+func (o *OpenAICompatible) Name() string { return o.name }
+
+// Instruction added to the system message of JSON requests; the fake server
+// reads the schema back from it
+const jsonSchemaInstruction = "Reply with a single JSON object that follows this JSON Schema:\n"
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    *float64              `json:"temperature,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIChoice struct {
+	Message      openAIMessage `json:"message"`
+	FinishReason string        `json:"finish_reason"`
+}
+
+type openAIResponse struct {
+	Choices []openAIChoice `json:"choices"`
+}
+
+func (o *OpenAICompatible) Generate(ctx context.Context, model string, req *Request) (*Response, error) {
+	if req.Audio != nil {
+		return nil, ErrUnsupported
+	}
+
+	body := openAIRequest{
+		Model:       model,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+	system := req.System
+	switch {
+	case req.Schema != nil && o.jsonSchema:
+		body.ResponseFormat = &openAIResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &openAIJSONSchema{Name: "reply", Schema: req.Schema},
+		}
+	case req.JSON || req.Schema != nil:
+		// JSON mode is the most widely supported; the schema goes in the
+		// instructions, which JSON mode requires to mention JSON anyway
+		body.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+		instruction := "Reply with a single JSON object."
+		if req.Schema != nil {
+			instruction = jsonSchemaInstruction + string(req.Schema)
+		}
+		system = strings.TrimSpace(system + "\n\n" + instruction)
+	}
+	if system != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: system})
+	}
+	body.Messages = append(body.Messages, openAIMessage{Role: "user", Content: req.Prompt})
+
+	headers := map[string]string{}
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+	var resp openAIResponse
+	if err := postJSON(ctx, o.client, o.name, o.baseURL+"/chat/completions", headers, body, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return nil, errors.New(o.name + " response contained no content")
+	}
+	return &Response{Text: resp.Choices[0].Message.Content, Provider: o.name, Model: model}, nil
+}
@@ -0,0 +1,203 @@
+// internal/llm/router.go
+package llm
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed models.json
+var defaultModels []byte
+
+// A provider that rate-limited without saying for how long is tried last
+// for this long
+const defaultCooldown = 30 * time.Second
+
+// Config lists the models each feature tries, in order, as
+// "provider:model".
+type Config struct {
+	Features map[string][]string `json:"features"`
+}
+
+// LoadConfig reads the model config from the file in LLM_CONFIG_FILE, or
+// the built-in internal/llm/models.json if it is not set.
+func LoadConfig() (*Config, error) {
+	data := defaultModels
+	if path := os.Getenv("LLM_CONFIG_FILE"); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read LLM config: %w", err)
+		}
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM config: %w", err)
+	}
+	for _, feature := range Features {
+		if len(config.Features[feature]) == 0 {
+			return nil, fmt.Errorf("invalid LLM config: no models for %s", feature)
+		}
+	}
+	return &config, nil
+}
+
+type target struct {
+	provider Provider
+	model    string
+}
+
+func (t target) String() string {
+	return t.provider.Name() + ":" + t.model
+}
+
+// Router sends each request to the first model configured for its feature
+// and fails over to the next one on any error. A provider that rate-limits
+// is tried after the others until its Retry-After has passed.
+type Router struct {
+	routes map[string][]target
+
+	mu        sync.Mutex
+	coolUntil map[string]time.Time // by provider
+}
+
+// NewRouter routes features to models of providers. Models of providers
+// that are not given are left out, so a missing API key only removes that
+// provider.
+func NewRouter(config *Config, providers ...Provider) (*Router, error) {
+	byName := make(map[string]Provider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	r := &Router{routes: make(map[string][]target), coolUntil: make(map[string]time.Time)}
+	for feature, models := range config.Features {
+		for _, spec := range models {
+			name, model, ok := strings.Cut(spec, ":")
+			if !ok || name == "" || model == "" {
+				return nil, fmt.Errorf("invalid LLM model %q for %s: must be provider:model", spec, feature)
+			}
+			provider, ok := byName[name]
+			if !ok {
+				log.Printf("LLM provider %s is not configured; %s will not use %s", name, feature, model)
+				continue
+			}
+			r.routes[feature] = append(r.routes[feature], target{provider: provider, model: model})
+		}
+		if len(r.routes[feature]) == 0 {
+			log.Printf("⚠️ No configured LLM provider for %s; its requests will fail", feature)
+		}
+	}
+	return r, nil
+}
+
+// NewRouterFromEnv creates the providers whose API keys are set and routes
+// features as LoadConfig says. The fake provider is always available; with
+// LLM_FAKE=true every feature uses only it.
+//
+//	GEMINI_API_KEY                    gemini
+//	GROQ_API_KEY                      groq
+//	OPENAI_API_KEY / OPENAI_BASE_URL  openai, or any OpenAI-compatible server
+func NewRouterFromEnv() (*Router, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []Provider{NewFake()}
+	if os.Getenv("LLM_FAKE") == "true" {
+		for feature := range config.Features {
+			config.Features[feature] = []string{"fake:default"}
+		}
+		log.Println("⚠️ LLM_FAKE is set: every feature uses the fake language model")
+		return NewRouter(config, providers...)
+	}
+
+	if key := os.Getenv("GEMINI_API_KEY"); key != "" {
+		providers = append(providers, NewGemini(key))
+	}
+	if key := os.Getenv("GROQ_API_KEY"); key != "" {
+		providers = append(providers, NewOpenAICompatible("groq", GroqBaseURL, key))
+	}
+	openAIKey, openAIBaseURL := os.Getenv("OPENAI_API_KEY"), os.Getenv("OPENAI_BASE_URL")
+	if openAIKey != "" || openAIBaseURL != "" {
+		if openAIBaseURL == "" {
+			openAIBaseURL = OpenAIBaseURL
+		}
+		providers = append(providers, NewOpenAICompatible("openai", openAIBaseURL, openAIKey).WithJSONSchema())
+	}
+	return NewRouter(config, providers...)
+}
+
+func (r *Router) Generate(ctx context.Context, feature string, req *Request) (*Response, error) {
+	targets := r.ordered(feature)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no LLM provider configured for %s", feature)
+	}
+
+	var errs []error
+	for _, t := range targets {
+		resp, err := t.provider.Generate(ctx, t.model, req)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RateLimited() {
+			r.coolDown(t.provider.Name(), apiErr.RetryAfter)
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			log.Printf("LLM %s: %s failed: %v", feature, t, err)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t, err))
+	}
+	return nil, fmt.Errorf("all models failed for %s: %w", feature, errors.Join(errs...))
+}
+
+// Models returns the models the feature is routed to, in the configured
+// order.
+func (r *Router) Models(feature string) []string {
+	models := make([]string, 0, len(r.routes[feature]))
+	for _, t := range r.routes[feature] {
+		models = append(models, t.String())
+	}
+	return models
+}
+
+// ordered returns the feature's models with those of rate-limited
+// providers moved to the end.
+func (r *Router) ordered(feature string) []target {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var ready, cooling []target
+	for _, t := range r.routes[feature] {
+		if now.Before(r.coolUntil[t.provider.Name()]) {
+			cooling = append(cooling, t)
+		} else {
+			ready = append(ready, t)
+		}
+	}
+	return append(ready, cooling...)
+}
+
+func (r *Router) coolDown(provider string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultCooldown
+	}
+	r.mu.Lock()
+	r.coolUntil[provider] = time.Now().Add(retryAfter)
+	r.mu.Unlock()
+}
@@ -0,0 +1,236 @@
+// internal/llm/schema.go
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema is the subset of JSON Schema that replies are checked against.
+type Schema struct {
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Enum       []string           `json:"enum,omitempty"`
+	Minimum    *float64           `json:"minimum,omitempty"`
+	Maximum    *float64           `json:"maximum,omitempty"`
+	// False for structs: a reply may not contain fields they do not have
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+var schemaCache sync.Map // reflect.Type -> *Schema
+
+// SchemaOf returns the JSON Schema of the JSON encoding of v's type. Fields
+// are required unless they are pointers or omitempty, no other fields are
+// allowed, fields tagged llm:"-" are filled in by the app and left out, and
+// the tags swag reads for the API docs add constraints:
+//
+//	minimum:"0" maximum:"100"          numbers
+//	enums:"grammar,pronunciation"      strings
+func SchemaOf(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if cached, ok := schemaCache.Load(t); ok {
+		return cached.(*Schema)
+	}
+	schema := schemaFor(t)
+	schemaCache.Store(t, schema)
+	return schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		closed := false
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: &closed}
+		addFields(schema, t)
+		sort.Strings(schema.Required)
+		return schema
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{} // anything
+	}
+}
+
+func addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || field.Tag.Get("llm") == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(schema, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaFor(field.Type)
+		if enums := field.Tag.Get("enums"); enums != "" {
+			property.Enum = strings.Split(enums, ",")
+		}
+		if minimum, err := strconv.ParseFloat(field.Tag.Get("minimum"), 64); err == nil {
+			property.Minimum = &minimum
+		}
+		if maximum, err := strconv.ParseFloat(field.Tag.Get("maximum"), 64); err == nil {
+			property.Maximum = &maximum
+		}
+		schema.Properties[name] = property
+
+		if field.Type.Kind() != reflect.Ptr && !strings.Contains(","+options+",", ",omitempty,") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// JSON returns the schema as sent to providers.
+func (s *Schema) JSON() json.RawMessage {
+	data, _ := json.Marshal(s) // a Schema always marshals
+	return data
+}
+
+// Validate checks a decoded JSON value (decoded with UseNumber) against the
+// schema and describes every problem it finds.
+func (s *Schema) Validate(value interface{}) []string {
+	var problems []string
+	s.validate(value, "", &problems)
+	sort.Strings(problems)
+	return problems
+}
+
+func (s *Schema) validate(value interface{}, path string, problems *[]string) {
+	at := path
+	if at == "" {
+		at = "the reply"
+	}
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if object[name] == nil {
+				*problems = append(*problems, joinPath(path, name)+": is required")
+			}
+		}
+		if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			for name := range object {
+				if s.Properties[name] == nil {
+					*problems = append(*problems, joinPath(path, name)+": is not an expected field")
+				}
+			}
+		}
+		for name, property := range s.Properties {
+			if fieldValue, ok := object[name]; ok && fieldValue != nil {
+				property.validate(fieldValue, joinPath(path, name), problems)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, text) {
+			fail("%q is not one of %s", text, strings.Join(s.Enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be true or false")
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		n, err := number.Float64()
+		if err != nil {
+			fail("must be a number")
+			return
+		}
+		if _, err := number.Int64(); s.Type == "integer" && err != nil {
+			fail("must be a whole number")
+			return
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("%s is below the minimum %g", number, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("%s is above the maximum %g", number, *s.Maximum)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// internal/llm/structured.go
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// How many times GenerateJSON asks again after an invalid reply
+const maxRepairAttempts = 2
+
+// ErrInvalidOutput is returned by GenerateJSON when no reply matched the
+// schema.
+var ErrInvalidOutput = errors.New("the model did not return valid JSON for the request")
+
+// Checker is implemented by replies with rules their schema cannot express,
+// e.g. that a score is the sum of its parts. Check describes what is wrong.
+type Checker interface {
+	Check() []string
+}
+
+// GenerateJSON asks for a reply in the shape of out, which must be a
+// pointer, and decodes it into out. The schema of out's type is sent with
+// the request (natively where the provider supports it) and the reply is
+// checked against it, scores and unexpected fields included, and by out's
+// Check if it is a Checker. An invalid reply is sent back with what was
+// wrong a few times before ErrInvalidOutput is returned.
+func GenerateJSON(ctx context.Context, client Client, feature string, req Request, out interface{}) (*Response, error) {
+	schema := SchemaOf(out)
+	req.JSON = true
+	req.Schema = schema.JSON()
+	prompt := req.Prompt
+
+	var problem string
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		if attempt > 0 {
+			req.Prompt = prompt + "\n\n" + problem
+		}
+
+		resp, err := client.Generate(ctx, feature, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := ParseJSON(resp.Text, schema)
+		if err == nil {
+			if err := json.Unmarshal(raw, out); err != nil {
+				return nil, fmt.Errorf("failed to decode %s reply: %w", feature, err)
+			}
+			if err = check(out); err == nil {
+				return resp, nil
+			}
+		}
+
+		log.Printf("LLM %s: invalid reply from %s:%s (attempt %d): %v", feature, resp.Provider, resp.Model, attempt+1, err)
+		problem = fmt.Sprintf("Your previous reply was:\n%s\n\nIt was not valid: %v.\nReply again with only the corrected JSON object.", resp.Text, err)
+	}
+	return nil, fmt.Errorf("%w (%s)", ErrInvalidOutput, feature)
+}
+
+func check(out interface{}) error {
+	checker, ok := out.(Checker)
+	if !ok {
+		return nil
+	}
+	if problems := checker.Check(); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ParseJSON finds the JSON object in a model's reply, which may be wrapped
+// in a code fence or text, and checks it against schema.
+func ParseJSON(text string, schema *Schema) (json.RawMessage, error) {
+	raw, err := extractJSON(text)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if problems := schema.Validate(value); len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return raw, nil
+}
+
+// extractJSON returns the first JSON object in text.
+func extractJSON(text string) (json.RawMessage, error) {
+	start := strings.IndexByte(text, '{')
+	if start < 0 {
+		return nil, errors.New("no JSON object in the reply")
+	}
+
+	// The decoder stops at the end of the object, leaving any closing
+	// fence or trailing text
+	var raw json.RawMessage
+	if err := json.NewDecoder(strings.NewReader(text[start:])).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return raw, nil
+}
@@ -2,15 +2,118 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
 	"lissanai.com/backend/internal/service"
 )
 
-func AuthMiddleware(jwtService service.JWTService) gin.HandlerFunc {
+// SessionChecker reports whether a device session still exists. Revoking a
+// session deletes it, so this is what ends the session's access tokens.
+type SessionChecker interface {
+	IsSessionActive(userID, sessionID primitive.ObjectID) (bool, error)
+}
+
+// sessionCheckTTL is how long a session is trusted after it was last found
+// active, and so how long a revoked session's access tokens keep working.
+const sessionCheckTTL = 30 * time.Second
+
+// activeSessions remembers when each session was last found active, so that
+// not every request has to look it up.
+type activeSessions struct {
+	checker SessionChecker
+	mu      sync.Mutex
+	seen    map[primitive.ObjectID]time.Time
+}
+
+func (a *activeSessions) isActive(userID, sessionID primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	a.mu.Lock()
+	seenAt, ok := a.seen[sessionID]
+	a.mu.Unlock()
+	if ok && now.Sub(seenAt) < sessionCheckTTL {
+		return true, nil
+	}
+
+	active, err := a.checker.IsSessionActive(userID, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !active {
+		delete(a.seen, sessionID)
+		return false, nil
+	}
+	if len(a.seen) > 100000 {
+		// Drop everything rather than track ages; it only costs lookups
+		a.seen = make(map[primitive.ObjectID]time.Time)
+	}
+	a.seen[sessionID] = now
+	return true, nil
+}
+
+// UserLoader loads the user an access token belongs to. Suspension and roles
+// are read from the user rather than the token, so they take effect before
+// the token expires.
+type UserLoader interface {
+	GetUserByID(id primitive.ObjectID) (*domain.User, error)
+}
+
+// accountCheckTTL is how long a user's suspension and roles are reused
+// before they are loaded again.
+const accountCheckTTL = 30 * time.Second
+
+type account struct {
+	suspended bool
+	roles     []string
+	loadedAt  time.Time
+}
+
+// accounts remembers the suspension and roles of recently seen users.
+type accounts struct {
+	loader UserLoader
+	mu     sync.Mutex
+	byID   map[primitive.ObjectID]account
+}
+
+func (a *accounts) get(userID primitive.ObjectID) (account, error) {
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.byID[userID]
+	a.mu.Unlock()
+	if ok && now.Sub(cached.loadedAt) < accountCheckTTL {
+		return cached, nil
+	}
+
+	user, err := a.loader.GetUserByID(userID)
+	if err != nil {
+		return account{}, err
+	}
+	loaded := account{suspended: user.Suspended, roles: user.EffectiveRoles(), loadedAt: now}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.byID) > 100000 {
+		a.byID = make(map[primitive.ObjectID]account)
+	}
+	a.byID[userID] = loaded
+	return loaded, nil
+}
+
+// AuthMiddleware accepts requests carrying a valid access token whose device
+// session has not been revoked and whose user is not suspended.
+func AuthMiddleware(jwtService service.JWTService, sessions SessionChecker, users UserLoader) gin.HandlerFunc {
+	active := &activeSessions{checker: sessions, seen: make(map[primitive.ObjectID]time.Time)}
+	known := &accounts{loader: users, byID: make(map[primitive.ObjectID]account)}
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -45,12 +148,63 @@ func AuthMiddleware(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 
-		// Set user ID in context
+		// Reject tokens of revoked device sessions. Tokens issued before
+		// sessions existed carry none and expire on their own.
+		if sessionID, err := jwtService.ExtractSessionID(token); err == nil && sessionID != "" {
+			id, err := primitive.ObjectIDFromHex(sessionID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+				c.Abort()
+				return
+			}
+			ok, err := active.isActive(userID, id)
+			if err != nil {
+				log.Printf("Failed to check session %s: %v", sessionID, err)
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to check session"})
+				c.Abort()
+				return
+			}
+			if !ok {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "session revoked, please log in again"})
+				c.Abort()
+				return
+			}
+			c.Set("session_id", sessionID)
+		}
+
+		// Suspension and roles may have changed since the token was issued
+		current, err := known.get(userID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			c.Abort()
+			return
+		}
+		if current.suspended {
+			c.JSON(http.StatusForbidden, gin.H{"error": "account suspended"})
+			c.Abort()
+			return
+		}
+
+		// Set user ID and current roles in context
 		c.Set("user_id", userID.Hex())
+		c.Set("roles", current.roles)
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware lets anonymous requests through without a user and
+// runs auth, an AuthMiddleware, on requests that carry a token, so a bad
+// token is still rejected.
+func OptionalAuthMiddleware(auth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
 func GetUserIDFromContext(c *gin.Context) (primitive.ObjectID, bool) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -69,3 +223,24 @@ func GetUserIDFromContext(c *gin.Context) (primitive.ObjectID, bool) {
 
 	return id, true
 }
+
+// GetSessionIDFromContext returns the device session of the access token, if any.
+// Tokens issued before sessions existed carry no session.
+func GetSessionIDFromContext(c *gin.Context) (primitive.ObjectID, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+
+	sessionIDStr, ok := sessionID.(string)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(sessionIDStr)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,46 @@
+// internal/middleware/role_middleware.go
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lissanai.com/backend/internal/domain"
+)
+
+// RequireRole only lets through users holding at least one of the given roles.
+// AuthMiddleware reads the roles from the user, so a role change takes effect
+// within accountCheckTTL rather than when the token expires. Must run after
+// AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := GetUserIDFromContext(c); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			c.Abort()
+			return
+		}
+
+		for _, held := range GetRolesFromContext(c) {
+			for _, role := range roles {
+				if held == role {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		c.Abort()
+	}
+}
+
+// GetRolesFromContext returns the current roles of the authenticated user.
+// Users without any are learners.
+func GetRolesFromContext(c *gin.Context) []string {
+	if roles, exists := c.Get("roles"); exists {
+		if list, ok := roles.([]string); ok && len(list) > 0 {
+			return list
+		}
+	}
+	return []string{domain.RoleLearner}
+}
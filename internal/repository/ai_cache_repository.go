@@ -0,0 +1,123 @@
+// internal/repository/ai_cache_repository.go
+package repository
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// AICacheRepository stores AI replies until they expire.
+type AICacheRepository interface {
+	// GetEntry returns the entry for key, or nil if there is none or it has
+	// expired.
+	GetEntry(key string) (*domain.AICacheEntry, error)
+	SaveEntry(entry *domain.AICacheEntry) error
+}
+
+// --- MongoDB ---
+
+type aiCacheRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAICacheRepository stores entries in the "ai_cache" collection, shared
+// by all server instances. A TTL index drops expired entries.
+func NewAICacheRepository(db *mongo.Database) AICacheRepository {
+	collection := db.Collection("ai_cache")
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("Failed to create ai_cache TTL index: %v", err)
+	}
+
+	return &aiCacheRepository{collection: collection}
+}
+
+func (r *aiCacheRepository) GetEntry(key string) (*domain.AICacheEntry, error) {
+	// The TTL monitor runs once a minute, so expired entries can linger
+	var entry domain.AICacheEntry
+	err := r.collection.FindOne(context.Background(), bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *aiCacheRepository) SaveEntry(entry *domain.AICacheEntry) error {
+	_, err := r.collection.ReplaceOne(context.Background(),
+		bson.M{"_id": entry.Key}, entry, options.Replace().SetUpsert(true))
+	return err
+}
+
+// --- In memory ---
+
+type inMemoryAICacheRepository struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+}
+
+// NewInMemoryAICacheRepository keeps up to size entries in process memory,
+// dropping the least recently used one when full.
+func NewInMemoryAICacheRepository(size int) AICacheRepository {
+	return &inMemoryAICacheRepository{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (r *inMemoryAICacheRepository) GetEntry(key string) (*domain.AICacheEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := element.Value.(*domain.AICacheEntry)
+	if !entry.ExpiresAt.After(time.Now()) {
+		r.order.Remove(element)
+		delete(r.entries, key)
+		return nil, nil
+	}
+	r.order.MoveToFront(element)
+	copied := *entry
+	return &copied, nil
+}
+
+func (r *inMemoryAICacheRepository) SaveEntry(entry *domain.AICacheEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copied := *entry
+	if element, ok := r.entries[entry.Key]; ok {
+		element.Value = &copied
+		r.order.MoveToFront(element)
+		return nil
+	}
+	r.entries[entry.Key] = r.order.PushFront(&copied)
+	for r.order.Len() > r.size {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(*domain.AICacheEntry).Key)
+	}
+	return nil
+}
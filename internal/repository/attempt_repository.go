@@ -0,0 +1,229 @@
+// internal/repository/attempt_repository.go
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// AttemptRepository stores failed-attempt counters used for brute-force
+// protection. Keys are opaque strings chosen by the caller.
+type AttemptRepository interface {
+	// GetAttempts returns the counter for key, or nil if there is none.
+	GetAttempts(key string) (*domain.AuthAttempt, error)
+	// IncrementAttempts records a failure. Counters whose last failure is older
+	// than window (and that are not locked) start again from zero.
+	IncrementAttempts(key string, window time.Duration) (*domain.AuthAttempt, error)
+	LockAttempts(key string, until time.Time) error
+	ResetAttempts(key string) error
+}
+
+// --- MongoDB ---
+
+type attemptRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewAttemptRepository stores counters in the "auth_attempts" collection, so
+// limits hold across server instances. A TTL index drops stale counters.
+func NewAttemptRepository(db *mongo.Database) AttemptRepository {
+	collection := db.Collection("auth_attempts")
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("Failed to create auth_attempts TTL index: %v", err)
+	}
+
+	return &attemptRepository{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *attemptRepository) GetAttempts(key string) (*domain.AuthAttempt, error) {
+	var attempt domain.AuthAttempt
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": key}).Decode(&attempt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) IncrementAttempts(key string, window time.Duration) (*domain.AuthAttempt, error) {
+	now := time.Now()
+
+	// Forget a stale, unlocked counter before counting this failure
+	_, err := r.collection.DeleteOne(context.Background(), bson.M{
+		"_id":             key,
+		"last_failure_at": bson.M{"$lt": now.Add(-window)},
+		"$or": []bson.M{
+			{"locked_until": bson.M{"$exists": false}},
+			{"locked_until": bson.M{"$lt": now}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	update := bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"last_failure_at": now},
+		"$setOnInsert": bson.M{"first_failure_at": now},
+		"$max":         bson.M{"expires_at": now.Add(window)},
+	}
+	var attempt domain.AuthAttempt
+	for tries := 1; ; tries++ {
+		err = r.collection.FindOneAndUpdate(context.Background(), bson.M{"_id": key}, update, opts).Decode(&attempt)
+		// Concurrent failures for a new key race to insert it; the losers
+		// retry and increment the counter the winner inserted.
+		if mongo.IsDuplicateKeyError(err) && tries < 3 {
+			continue
+		}
+		break
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *attemptRepository) LockAttempts(key string, until time.Time) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": key},
+		bson.M{
+			"$set": bson.M{"locked_until": until},
+			"$max": bson.M{"expires_at": until},
+		},
+	)
+	return err
+}
+
+func (r *attemptRepository) ResetAttempts(key string) error {
+	_, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": key})
+	return err
+}
+
+// --- In-memory ---
+
+// inMemoryAttemptSweepSize bounds how many counters accumulate before expired
+// ones are swept, so a flood of distinct keys cannot grow memory forever.
+const inMemoryAttemptSweepSize = 10000
+
+type inMemoryAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*domain.AuthAttempt
+	now      func() time.Time
+}
+
+// NewInMemoryAttemptRepository keeps counters in process memory. Suitable for
+// a single instance and for exercising the throttling logic without MongoDB.
+func NewInMemoryAttemptRepository() AttemptRepository {
+	return NewInMemoryAttemptRepositoryWithClock(time.Now)
+}
+
+// NewInMemoryAttemptRepositoryWithClock is NewInMemoryAttemptRepository with a
+// controllable clock.
+func NewInMemoryAttemptRepositoryWithClock(now func() time.Time) AttemptRepository {
+	return &inMemoryAttemptRepository{
+		attempts: make(map[string]*domain.AuthAttempt),
+		now:      now,
+	}
+}
+
+func (r *inMemoryAttemptRepository) GetAttempts(key string) (*domain.AuthAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt := r.live(key)
+	if attempt == nil {
+		return nil, nil
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+func (r *inMemoryAttemptRepository) IncrementAttempts(key string, window time.Duration) (*domain.AuthAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	attempt := r.live(key)
+	if attempt != nil && attempt.LastFailureAt.Before(now.Add(-window)) &&
+		(attempt.LockedUntil == nil || attempt.LockedUntil.Before(now)) {
+		attempt = nil
+	}
+	if attempt == nil {
+		if len(r.attempts) >= inMemoryAttemptSweepSize {
+			r.sweep(now)
+		}
+		attempt = &domain.AuthAttempt{Key: key, FirstFailureAt: now}
+		r.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	if expiresAt := now.Add(window); expiresAt.After(attempt.ExpiresAt) {
+		attempt.ExpiresAt = expiresAt
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+func (r *inMemoryAttemptRepository) LockAttempts(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt := r.live(key); attempt != nil {
+		attempt.LockedUntil = &until
+		if until.After(attempt.ExpiresAt) {
+			attempt.ExpiresAt = until
+		}
+	}
+	return nil
+}
+
+func (r *inMemoryAttemptRepository) ResetAttempts(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// live returns the counter for key, dropping it if it has expired. Callers hold mu.
+func (r *inMemoryAttemptRepository) live(key string) *domain.AuthAttempt {
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil
+	}
+	if !attempt.ExpiresAt.After(r.now()) {
+		delete(r.attempts, key)
+		return nil
+	}
+	return attempt
+}
+
+// sweep drops every expired counter. Callers hold mu.
+func (r *inMemoryAttemptRepository) sweep(now time.Time) {
+	for key, attempt := range r.attempts {
+		if !attempt.ExpiresAt.After(now) {
+			delete(r.attempts, key)
+		}
+	}
+}
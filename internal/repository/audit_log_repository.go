@@ -0,0 +1,76 @@
+// internal/repository/audit_log_repository.go
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// AuditLogRepository stores the append-only record of admin actions. An
+// entry is only ever changed to mark its action failed.
+type AuditLogRepository interface {
+	CreateAuditLog(entry *domain.AuditLog) error
+	MarkAuditLogFailed(id primitive.ObjectID) error
+	GetAuditLogs(filter domain.AuditLogFilter, skip, limit int64) ([]*domain.AuditLog, int64, error)
+}
+
+type auditLogRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository(db *mongo.Database) AuditLogRepository {
+	return &auditLogRepository{
+		db:         db,
+		collection: db.Collection("audit_logs"),
+	}
+}
+
+func (r *auditLogRepository) CreateAuditLog(entry *domain.AuditLog) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	_, err := r.collection.InsertOne(context.Background(), entry)
+	return err
+}
+
+func (r *auditLogRepository) MarkAuditLogFailed(id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"failed": true}})
+	return err
+}
+
+func (r *auditLogRepository) GetAuditLogs(filter domain.AuditLogFilter, skip, limit int64) ([]*domain.AuditLog, int64, error) {
+	query := bson.M{}
+	if filter.ActorID != nil {
+		query["actor_id"] = *filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+
+	total, err := r.collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	var entries []*domain.AuditLog
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,134 @@
+// internal/repository/email_outbox_repository.go
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// Sent and failed messages are kept this long for troubleshooting.
+const outboxRetention = 30 * 24 * time.Hour
+
+// EmailOutboxRepository persists emails until a worker has delivered them.
+type EmailOutboxRepository interface {
+	Enqueue(email *domain.OutboxEmail) error
+	// ClaimNext marks the oldest due message as sending until now+lease and
+	// returns it, or nil if nothing is due. Messages whose claim has lapsed
+	// (the worker died mid-send) are due again.
+	ClaimNext(now time.Time, lease time.Duration) (*domain.OutboxEmail, error)
+	MarkSent(id primitive.ObjectID) error
+	// MarkRetry records a failed attempt and schedules the next one.
+	MarkRetry(id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error
+	// MarkFailed records a failed attempt and gives up on the message.
+	MarkFailed(id primitive.ObjectID, lastError string) error
+}
+
+type emailOutboxRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewEmailOutboxRepository stores messages in the "email_outbox" collection.
+// Finished messages are dropped by a TTL index once their retention ends.
+func NewEmailOutboxRepository(db *mongo.Database) EmailOutboxRepository {
+	collection := db.Collection("email_outbox")
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create email_outbox indexes: %v", err)
+	}
+
+	return &emailOutboxRepository{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *emailOutboxRepository) Enqueue(email *domain.OutboxEmail) error {
+	now := time.Now()
+	email.ID = primitive.NewObjectID()
+	email.Status = domain.OutboxPending
+	email.CreatedAt = now
+	if email.NextAttemptAt.IsZero() {
+		email.NextAttemptAt = now
+	}
+
+	_, err := r.collection.InsertOne(context.Background(), email)
+	return err
+}
+
+func (r *emailOutboxRepository) ClaimNext(now time.Time, lease time.Duration) (*domain.OutboxEmail, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": domain.OutboxPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": domain.OutboxSending, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": domain.OutboxSending, "locked_until": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var email domain.OutboxEmail
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&email)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &email, nil
+}
+
+func (r *emailOutboxRepository) MarkSent(id primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxSent, "sent_at": now, "expires_at": now.Add(outboxRetention)},
+			"$unset": bson.M{"locked_until": "", "last_error": ""},
+		},
+	)
+	return err
+}
+
+func (r *emailOutboxRepository) MarkRetry(id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxPending, "last_error": lastError, "next_attempt_at": nextAttemptAt},
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	return err
+}
+
+func (r *emailOutboxRepository) MarkFailed(id primitive.ObjectID, lastError string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxFailed, "last_error": lastError, "expires_at": time.Now().Add(outboxRetention)},
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	return err
+}
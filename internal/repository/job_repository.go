@@ -0,0 +1,205 @@
+// internal/repository/job_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// Run history is kept this long
+const jobRunRetention = 30 * 24 * time.Hour
+
+// ErrLeaseLost is returned when renewing a lease another instance has taken
+// over, after it expired.
+var ErrLeaseLost = errors.New("lease lost")
+
+// JobRepository persists the state of scheduled jobs, shared by every
+// instance, and their run history. A job's lease makes sure only one
+// instance runs it at a time.
+type JobRepository interface {
+	// EnsureJob records a job's schedule and description. nextRunAt is only
+	// stored for a new job or a changed schedule, so restarts keep the
+	// persisted next run.
+	EnsureJob(name, schedule, description string, nextRunAt time.Time) error
+	GetJobs() ([]*domain.ScheduledJob, error)
+	// AcquireLease takes the job's lease for owner until until, if no other
+	// instance holds it. With dueOnly it is only taken when the job's next
+	// run is at or before now. It reports whether the lease was taken.
+	AcquireLease(name, owner string, now, until time.Time, dueOnly bool) (bool, error)
+	// RenewLease extends a lease owner still holds, or returns ErrLeaseLost.
+	RenewLease(name, owner string, until time.Time) error
+	// FinishJob records the outcome of a run and releases owner's lease.
+	// A nil nextRunAt keeps the scheduled next run.
+	FinishJob(name, owner string, run *domain.JobRun, nextRunAt *time.Time) error
+
+	CreateRun(run *domain.JobRun) error
+	FinishRun(run *domain.JobRun) error
+	// GetRecentRuns returns the latest runs of a job, newest first.
+	GetRecentRuns(name string, limit int64) ([]*domain.JobRun, error)
+}
+
+type jobRepository struct {
+	db            *mongo.Database
+	jobCollection *mongo.Collection
+	runCollection *mongo.Collection
+}
+
+// NewJobRepository stores job state in "scheduled_jobs" and run history in
+// "job_runs", where a TTL index drops runs after their retention.
+func NewJobRepository(db *mongo.Database) JobRepository {
+	runCollection := db.Collection("job_runs")
+	_, err := runCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "started_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(jobRunRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create job_runs indexes: %v", err)
+	}
+
+	return &jobRepository{
+		db:            db,
+		jobCollection: db.Collection("scheduled_jobs"),
+		runCollection: runCollection,
+	}
+}
+
+func (r *jobRepository) EnsureJob(name, schedule, description string, nextRunAt time.Time) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	result, err := r.jobCollection.UpdateOne(ctx,
+		bson.M{"_id": name, "schedule": schedule},
+		bson.M{"$set": bson.M{"description": description, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	_, err = r.jobCollection.UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{"$set": bson.M{
+			"schedule":    schedule,
+			"description": description,
+			"next_run_at": nextRunAt,
+			"updated_at":  now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// Created by another instance starting at the same time
+		return nil
+	}
+	return err
+}
+
+func (r *jobRepository) GetJobs() ([]*domain.ScheduledJob, error) {
+	ctx := context.Background()
+	cursor, err := r.jobCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*domain.ScheduledJob
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) AcquireLease(name, owner string, now, until time.Time, dueOnly bool) (bool, error) {
+	filter := bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"lease_until": bson.M{"$exists": false}},
+			{"lease_until": bson.M{"$lte": now}},
+		},
+	}
+	if dueOnly {
+		filter["next_run_at"] = bson.M{"$lte": now}
+	}
+
+	result, err := r.jobCollection.UpdateOne(context.Background(), filter, bson.M{
+		"$set": bson.M{"lease_owner": owner, "lease_until": until},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *jobRepository) RenewLease(name, owner string, until time.Time) error {
+	result, err := r.jobCollection.UpdateOne(context.Background(),
+		bson.M{"_id": name, "lease_owner": owner},
+		bson.M{"$set": bson.M{"lease_until": until}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *jobRepository) FinishJob(name, owner string, run *domain.JobRun, nextRunAt *time.Time) error {
+	set := bson.M{
+		"last_run_at": run.StartedAt,
+		"last_status": run.Status,
+		"last_error":  run.Error,
+		"updated_at":  time.Now(),
+	}
+	if nextRunAt != nil {
+		set["next_run_at"] = *nextRunAt
+	}
+
+	_, err := r.jobCollection.UpdateOne(context.Background(),
+		bson.M{"_id": name, "lease_owner": owner},
+		bson.M{"$set": set, "$unset": bson.M{"lease_owner": "", "lease_until": ""}},
+	)
+	return err
+}
+
+func (r *jobRepository) CreateRun(run *domain.JobRun) error {
+	run.ID = primitive.NewObjectID()
+	_, err := r.runCollection.InsertOne(context.Background(), run)
+	return err
+}
+
+func (r *jobRepository) FinishRun(run *domain.JobRun) error {
+	_, err := r.runCollection.UpdateOne(context.Background(), bson.M{"_id": run.ID}, bson.M{
+		"$set": bson.M{
+			"status":      run.Status,
+			"error":       run.Error,
+			"finished_at": run.FinishedAt,
+			"duration_ms": run.DurationMS,
+		},
+	})
+	return err
+}
+
+func (r *jobRepository) GetRecentRuns(name string, limit int64) ([]*domain.JobRun, error) {
+	ctx := context.Background()
+	cursor, err := r.runCollection.Find(ctx, bson.M{"job": name},
+		options.Find().SetSort(bson.M{"started_at": -1}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	var runs []*domain.JobRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -17,14 +17,23 @@ type LearningRepository interface {
 	// Learning Paths
 	GetAllLearningPaths() ([]*domain.LearningPath, error)
 	GetLearningPathByID(id primitive.ObjectID) (*domain.LearningPath, error)
+	CreateLearningPath(path *domain.LearningPath) error
+	UpdateLearningPath(path *domain.LearningPath) error
+	DeleteLearningPath(id primitive.ObjectID) error
 	
 	// Lessons
 	GetLessonByID(id primitive.ObjectID) (*domain.Lesson, error)
 	GetLessonsByPathID(pathID primitive.ObjectID) ([]*domain.Lesson, error)
+	CreateLesson(lesson *domain.Lesson) error
+	UpdateLesson(lesson *domain.Lesson) error
+	DeleteLesson(id primitive.ObjectID) error
 	
 	// Quizzes
 	GetQuizByID(id primitive.ObjectID) (*domain.Quiz, error)
 	GetQuizByLessonID(lessonID primitive.ObjectID) (*domain.Quiz, error)
+	CreateQuiz(quiz *domain.Quiz) error
+	UpdateQuiz(quiz *domain.Quiz) error
+	DeleteQuiz(id primitive.ObjectID) error
 	
 	// User Progress
 	GetUserProgress(userID, pathID primitive.ObjectID) (*domain.UserProgress, error)
@@ -82,6 +91,56 @@ func (r *learningRepository) GetLearningPathByID(id primitive.ObjectID) (*domain
 	return &path, nil
 }
 
+func (r *learningRepository) CreateLearningPath(path *domain.LearningPath) error {
+	collection := r.db.Collection("learning_paths")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if path.ID.IsZero() {
+		path.ID = primitive.NewObjectID()
+	}
+	path.CreatedAt = time.Now()
+	path.UpdatedAt = path.CreatedAt
+	if path.LessonIDs == nil {
+		path.LessonIDs = []primitive.ObjectID{}
+	}
+
+	_, err := collection.InsertOne(ctx, path)
+	return err
+}
+
+func (r *learningRepository) UpdateLearningPath(path *domain.LearningPath) error {
+	collection := r.db.Collection("learning_paths")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	path.UpdatedAt = time.Now()
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": path.ID}, bson.M{"$set": path})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("learning path not found")
+	}
+	return nil
+}
+
+func (r *learningRepository) DeleteLearningPath(id primitive.ObjectID) error {
+	collection := r.db.Collection("learning_paths")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("learning path not found")
+	}
+	return nil
+}
+
 // Lessons
 func (r *learningRepository) GetLessonByID(id primitive.ObjectID) (*domain.Lesson, error) {
 	collection := r.db.Collection("lessons")
@@ -105,7 +164,7 @@ func (r *learningRepository) GetLessonsByPathID(pathID primitive.ObjectID) ([]*d
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	opts := options.Find().SetSort(bson.D{{"order", 1}})
+	opts := options.Find().SetSort(bson.D{{Key: "order", Value: 1}})
 	cursor, err := collection.Find(ctx, bson.M{"path_id": pathID}, opts)
 	if err != nil {
 		return nil, err
@@ -120,6 +179,80 @@ func (r *learningRepository) GetLessonsByPathID(pathID primitive.ObjectID) ([]*d
 	return lessons, nil
 }
 
+// CreateLesson inserts the lesson and appends it to its learning path.
+func (r *learningRepository) CreateLesson(lesson *domain.Lesson) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if lesson.ID.IsZero() {
+		lesson.ID = primitive.NewObjectID()
+	}
+	lesson.CreatedAt = time.Now()
+	lesson.UpdatedAt = lesson.CreatedAt
+
+	if _, err := r.db.Collection("lessons").InsertOne(ctx, lesson); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection("learning_paths").UpdateOne(
+		ctx,
+		bson.M{"_id": lesson.PathID},
+		bson.M{"$addToSet": bson.M{"lesson_ids": lesson.ID}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	return err
+}
+
+// UpdateLesson replaces the lesson and, if it moved to another learning path,
+// moves its ID between the paths' lesson lists.
+func (r *learningRepository) UpdateLesson(lesson *domain.Lesson) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lessons := r.db.Collection("lessons")
+	var previous domain.Lesson
+	if err := lessons.FindOne(ctx, bson.M{"_id": lesson.ID}).Decode(&previous); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errors.New("lesson not found")
+		}
+		return err
+	}
+
+	lesson.UpdatedAt = time.Now()
+	if _, err := lessons.UpdateOne(ctx, bson.M{"_id": lesson.ID}, bson.M{"$set": lesson}); err != nil {
+		return err
+	}
+
+	if previous.PathID == lesson.PathID {
+		return nil
+	}
+	paths := r.db.Collection("learning_paths")
+	if _, err := paths.UpdateOne(ctx, bson.M{"_id": previous.PathID}, bson.M{"$pull": bson.M{"lesson_ids": lesson.ID}}); err != nil {
+		return err
+	}
+	_, err := paths.UpdateOne(ctx, bson.M{"_id": lesson.PathID}, bson.M{"$addToSet": bson.M{"lesson_ids": lesson.ID}})
+	return err
+}
+
+// DeleteLesson removes the lesson, its quiz and its entry in the learning path.
+func (r *learningRepository) DeleteLesson(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.db.Collection("lessons").DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("lesson not found")
+	}
+
+	if _, err := r.db.Collection("quizzes").DeleteMany(ctx, bson.M{"lesson_id": id}); err != nil {
+		return err
+	}
+	_, err = r.db.Collection("learning_paths").UpdateMany(ctx, bson.M{"lesson_ids": id}, bson.M{"$pull": bson.M{"lesson_ids": id}})
+	return err
+}
+
 // Quizzes
 func (r *learningRepository) GetQuizByID(id primitive.ObjectID) (*domain.Quiz, error) {
 	collection := r.db.Collection("quizzes")
@@ -155,6 +288,63 @@ func (r *learningRepository) GetQuizByLessonID(lessonID primitive.ObjectID) (*do
 	return &quiz, nil
 }
 
+// CreateQuiz inserts the quiz and attaches it to its lesson.
+func (r *learningRepository) CreateQuiz(quiz *domain.Quiz) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if quiz.ID.IsZero() {
+		quiz.ID = primitive.NewObjectID()
+	}
+	quiz.CreatedAt = time.Now()
+	quiz.UpdatedAt = quiz.CreatedAt
+
+	if _, err := r.db.Collection("quizzes").InsertOne(ctx, quiz); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection("lessons").UpdateOne(
+		ctx,
+		bson.M{"_id": quiz.LessonID},
+		bson.M{"$set": bson.M{"quiz_id": quiz.ID, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *learningRepository) UpdateQuiz(quiz *domain.Quiz) error {
+	collection := r.db.Collection("quizzes")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	quiz.UpdatedAt = time.Now()
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": quiz.ID}, bson.M{"$set": quiz})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("quiz not found")
+	}
+	return nil
+}
+
+// DeleteQuiz removes the quiz and detaches it from its lesson.
+func (r *learningRepository) DeleteQuiz(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.db.Collection("quizzes").DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("quiz not found")
+	}
+
+	_, err = r.db.Collection("lessons").UpdateMany(ctx, bson.M{"quiz_id": id}, bson.M{"$unset": bson.M{"quiz_id": ""}})
+	return err
+}
+
 // User Progress
 func (r *learningRepository) GetUserProgress(userID, pathID primitive.ObjectID) (*domain.UserProgress, error) {
 	collection := r.db.Collection("user_progress")
@@ -0,0 +1,109 @@
+// internal/repository/prompt_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// ErrPromptTemplateExists is returned when a prompt already has a template
+// for the version and locale.
+var ErrPromptTemplateExists = errors.New("prompt version already exists")
+
+// PromptRepository stores the prompt templates and rollouts edited through
+// the admin API. They are added to the ones shipped in files.
+type PromptRepository interface {
+	GetTemplates() ([]*domain.PromptTemplate, error)
+	CreateTemplate(template *domain.PromptTemplate) error
+	GetRollouts() ([]*domain.PromptRollout, error)
+	SaveRollout(rollout *domain.PromptRollout) error
+}
+
+type promptRepository struct {
+	templateCollection *mongo.Collection
+	rolloutCollection  *mongo.Collection
+}
+
+// NewPromptRepository stores templates in "prompt_templates", unique by
+// name, version and locale, and rollouts in "prompt_rollouts" by name.
+func NewPromptRepository(db *mongo.Database) PromptRepository {
+	templateCollection := db.Collection("prompt_templates")
+	_, err := templateCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "version", Value: 1}, {Key: "locale", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Failed to create prompt_templates index: %v", err)
+	}
+
+	return &promptRepository{
+		templateCollection: templateCollection,
+		rolloutCollection:  db.Collection("prompt_rollouts"),
+	}
+}
+
+func (r *promptRepository) GetTemplates() ([]*domain.PromptTemplate, error) {
+	ctx := context.Background()
+
+	cursor, err := r.templateCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var templates []*domain.PromptTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		template.Source = domain.PromptSourceMongo
+	}
+	return templates, nil
+}
+
+func (r *promptRepository) CreateTemplate(template *domain.PromptTemplate) error {
+	template.CreatedAt = time.Now()
+
+	result, err := r.templateCollection.InsertOne(context.Background(), template)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrPromptTemplateExists
+	}
+	if err != nil {
+		return err
+	}
+	template.ID = result.InsertedID.(primitive.ObjectID)
+	template.Source = domain.PromptSourceMongo
+	return nil
+}
+
+func (r *promptRepository) GetRollouts() ([]*domain.PromptRollout, error) {
+	ctx := context.Background()
+
+	cursor, err := r.rolloutCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rollouts []*domain.PromptRollout
+	if err := cursor.All(ctx, &rollouts); err != nil {
+		return nil, err
+	}
+	return rollouts, nil
+}
+
+func (r *promptRepository) SaveRollout(rollout *domain.PromptRollout) error {
+	rollout.UpdatedAt = time.Now()
+
+	_, err := r.rolloutCollection.ReplaceOne(context.Background(),
+		bson.M{"_id": rollout.Name}, rollout, options.Replace().SetUpsert(true))
+	return err
+}
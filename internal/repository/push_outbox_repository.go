@@ -0,0 +1,129 @@
+// internal/repository/push_outbox_repository.go
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// PushOutboxRepository persists push notifications until a worker has
+// delivered them. It works like EmailOutboxRepository.
+type PushOutboxRepository interface {
+	Enqueue(notification *domain.PushNotification) error
+	// ClaimNext marks the oldest due notification as sending until now+lease
+	// and returns it, or nil if nothing is due.
+	ClaimNext(now time.Time, lease time.Duration) (*domain.PushNotification, error)
+	MarkSent(id primitive.ObjectID) error
+	MarkRetry(id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(id primitive.ObjectID, lastError string) error
+}
+
+type pushOutboxRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// NewPushOutboxRepository stores notifications in the "push_outbox" collection.
+// Finished notifications are dropped by a TTL index once their retention ends.
+func NewPushOutboxRepository(db *mongo.Database) PushOutboxRepository {
+	collection := db.Collection("push_outbox")
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create push_outbox indexes: %v", err)
+	}
+
+	return &pushOutboxRepository{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *pushOutboxRepository) Enqueue(notification *domain.PushNotification) error {
+	now := time.Now()
+	notification.ID = primitive.NewObjectID()
+	notification.Status = domain.OutboxPending
+	notification.CreatedAt = now
+	if notification.NextAttemptAt.IsZero() {
+		notification.NextAttemptAt = now
+	}
+
+	_, err := r.collection.InsertOne(context.Background(), notification)
+	return err
+}
+
+func (r *pushOutboxRepository) ClaimNext(now time.Time, lease time.Duration) (*domain.PushNotification, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": domain.OutboxPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": domain.OutboxSending, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": domain.OutboxSending, "locked_until": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var notification domain.PushNotification
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *pushOutboxRepository) MarkSent(id primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxSent, "sent_at": now, "expires_at": now.Add(outboxRetention)},
+			"$unset": bson.M{"locked_until": "", "last_error": ""},
+		},
+	)
+	return err
+}
+
+func (r *pushOutboxRepository) MarkRetry(id primitive.ObjectID, lastError string, nextAttemptAt time.Time) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxPending, "last_error": lastError, "next_attempt_at": nextAttemptAt},
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	return err
+}
+
+func (r *pushOutboxRepository) MarkFailed(id primitive.ObjectID, lastError string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": domain.OutboxFailed, "last_error": lastError, "expires_at": time.Now().Add(outboxRetention)},
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	return err
+}
@@ -0,0 +1,122 @@
+// internal/repository/session_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// ErrSessionNotFound is returned for sessions that do not exist, because
+// they were revoked, logged out or expired.
+var ErrSessionNotFound = errors.New("session not found")
+
+// UserSessionRepository stores the devices a user is logged in on.
+type UserSessionRepository interface {
+	CreateSession(session *domain.UserSession) error
+	GetSessionByID(id primitive.ObjectID) (*domain.UserSession, error)
+	GetUserSessions(userID primitive.ObjectID) ([]*domain.UserSession, error)
+	TouchSession(id primitive.ObjectID, ipAddress, userAgent string, expiresAt time.Time) error
+	SetSessionPushToken(id primitive.ObjectID, token string) error
+	DeleteSession(id primitive.ObjectID) error
+	DeleteUserSessions(userID primitive.ObjectID) error
+}
+
+type userSessionRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewUserSessionRepository(db *mongo.Database) UserSessionRepository {
+	collection := db.Collection("user_sessions")
+	// A session ends with its refresh token family
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("Failed to create user_sessions TTL index: %v", err)
+	}
+
+	return &userSessionRepository{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *userSessionRepository) CreateSession(session *domain.UserSession) error {
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+	_, err := r.collection.InsertOne(context.Background(), session)
+	return err
+}
+
+func (r *userSessionRepository) GetSessionByID(id primitive.ObjectID) (*domain.UserSession, error) {
+	var session domain.UserSession
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *userSessionRepository) GetUserSessions(userID primitive.ObjectID) ([]*domain.UserSession, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var sessions []*domain.UserSession
+	if err := cursor.All(context.Background(), &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchSession records activity on the session and moves its expiry to
+// expiresAt, that of the family's newest refresh token.
+func (r *userSessionRepository) TouchSession(id primitive.ObjectID, ipAddress, userAgent string, expiresAt time.Time) error {
+	set := bson.M{"last_used_at": time.Now(), "expires_at": expiresAt}
+	if ipAddress != "" {
+		set["ip_address"] = ipAddress
+	}
+	if userAgent != "" {
+		set["user_agent"] = userAgent
+	}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+func (r *userSessionRepository) SetSessionPushToken(id primitive.ObjectID, token string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"push_token": token}},
+	)
+	return err
+}
+
+func (r *userSessionRepository) DeleteSession(id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+func (r *userSessionRepository) DeleteUserSessions(userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,188 @@
+// internal/repository/user_data_repository.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UserDataRepository finds and erases everything stored about one user
+// across all collections, for data export and account deletion.
+type UserDataRepository interface {
+	// ExportUserData returns the user's documents keyed by collection, with
+	// secrets such as password hashes and tokens removed.
+	ExportUserData(userID primitive.ObjectID) (map[string][]bson.M, error)
+	// DeleteUserData removes the user's documents from every collection and
+	// returns how many were deleted from each. The user document goes last,
+	// so a deletion interrupted halfway can simply be run again.
+	DeleteUserData(userID primitive.ObjectID, email string) (map[string]int64, error)
+}
+
+// userDataCollection describes where a collection keeps its reference to the user.
+type userDataCollection struct {
+	name string
+	// filter selects the user's documents; sessionIDs are the user's interview sessions
+	filter func(userID primitive.ObjectID, sessionIDs []string) bson.M
+	// omit lists fields left out of exports (credentials and one-time tokens)
+	omit []string
+	// exportable is false for security bookkeeping that is deleted but not exported
+	exportable bool
+}
+
+func byUserID(userID primitive.ObjectID, _ []string) bson.M {
+	return bson.M{"user_id": userID}
+}
+
+// userDataCollections lists every collection holding per-user data. Any new
+// collection storing user data must be registered here so it is exported and
+// deleted with the account. Audit logs are deliberately absent: they only
+// hold IDs and are retained as the record of admin actions. So is ai_cache:
+// its entries are keyed by a hash of the input, not by user, and expire after
+// AI_CACHE_TTL.
+var userDataCollections = []userDataCollection{
+	{
+		// Found through the user's interview sessions, so listed before them:
+		// a rerun of an interrupted deletion can still find them
+		name: "messages",
+		filter: func(_ primitive.ObjectID, sessionIDs []string) bson.M {
+			return bson.M{"session_id": bson.M{"$in": sessionIDs}}
+		},
+		exportable: true,
+	},
+	{
+		name: "sessions", // interview sessions store the user ID as hex
+		filter: func(userID primitive.ObjectID, _ []string) bson.M {
+			return bson.M{"user_id": userID.Hex()}
+		},
+		exportable: true,
+	},
+	{name: "streak_activities", filter: byUserID, exportable: true},
+	{name: "daily_activity_summaries", filter: byUserID, exportable: true},
+	{name: "streak_freezes", filter: byUserID, exportable: true},
+	{name: "user_achievements", filter: byUserID, exportable: true},
+	{name: "xp_events", filter: byUserID, exportable: true},
+	{name: "league_memberships", filter: byUserID, exportable: true},
+	{
+		name: "friendships", // either side of the friendship
+		filter: func(userID primitive.ObjectID, _ []string) bson.M {
+			return bson.M{"$or": []bson.M{{"requester_id": userID}, {"addressee_id": userID}}}
+		},
+		exportable: true,
+	},
+	{name: "user_progress", filter: byUserID, exportable: true},
+	{name: "quiz_submissions", filter: byUserID, exportable: true},
+	{name: "user_sessions", filter: byUserID, omit: []string{"push_token"}, exportable: true},
+	{name: "refresh_tokens", filter: byUserID},
+	{name: "password_resets", filter: byUserID},
+	{name: "email_verifications", filter: byUserID},
+	{name: "email_outbox", filter: byUserID},
+	{name: "push_outbox", filter: byUserID},
+}
+
+// Fields of the user document that are never exported.
+var userSecretFields = []string{"password_hash", "mfa_secret", "mfa_pending_secret", "mfa_recovery_codes", "mfa_last_used_step"}
+
+type userDataRepository struct {
+	db *mongo.Database
+}
+
+func NewUserDataRepository(db *mongo.Database) UserDataRepository {
+	return &userDataRepository{db: db}
+}
+
+func (r *userDataRepository) ExportUserData(userID primitive.ObjectID) (map[string][]bson.M, error) {
+	ctx := context.Background()
+	data := make(map[string][]bson.M)
+
+	var user bson.M
+	if err := r.db.Collection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to export users: %w", err)
+	}
+	for _, field := range userSecretFields {
+		delete(user, field)
+	}
+	data["users"] = []bson.M{user}
+
+	sessionIDs, err := r.interviewSessionIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range userDataCollections {
+		if !c.exportable {
+			continue
+		}
+		cursor, err := r.db.Collection(c.name).Find(ctx, c.filter(userID, sessionIDs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", c.name, err)
+		}
+		docs := []bson.M{}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", c.name, err)
+		}
+		for _, doc := range docs {
+			for _, field := range c.omit {
+				delete(doc, field)
+			}
+		}
+		data[c.name] = docs
+	}
+	return data, nil
+}
+
+func (r *userDataRepository) DeleteUserData(userID primitive.ObjectID, email string) (map[string]int64, error) {
+	ctx := context.Background()
+	deleted := make(map[string]int64)
+
+	sessionIDs, err := r.interviewSessionIDs(ctx, userID)
+	if err != nil {
+		return deleted, err
+	}
+
+	for _, c := range userDataCollections {
+		result, err := r.db.Collection(c.name).DeleteMany(ctx, c.filter(userID, sessionIDs))
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %w", c.name, err)
+		}
+		deleted[c.name] = result.DeletedCount
+	}
+
+	// Brute-force counters are keyed by email or user ID rather than referencing the user
+	keys := []string{":user:" + regexp.QuoteMeta(userID.Hex()) + "$"}
+	if email != "" {
+		keys = append(keys, ":email:"+regexp.QuoteMeta(email)+"$")
+	}
+	for _, key := range keys {
+		result, err := r.db.Collection("auth_attempts").DeleteMany(ctx, bson.M{"_id": bson.M{"$regex": key, "$options": "i"}})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete auth_attempts: %w", err)
+		}
+		deleted["auth_attempts"] += result.DeletedCount
+	}
+
+	result, err := r.db.Collection("users").DeleteOne(ctx, bson.M{"_id": userID})
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete users: %w", err)
+	}
+	deleted["users"] = result.DeletedCount
+	return deleted, nil
+}
+
+func (r *userDataRepository) interviewSessionIDs(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	values, err := r.db.Collection("sessions").Distinct(ctx, "_id", bson.M{"user_id": userID.Hex()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interview sessions: %w", err)
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -4,11 +4,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"log"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"lissanai.com/backend/internal/domain"
 )
 
@@ -17,17 +20,36 @@ type UserRepository interface {
 	GetUserByEmail(email string) (*domain.User, error)
 	GetUserByID(id primitive.ObjectID) (*domain.User, error)
 	GetUserByProviderID(provider, providerID string) (*domain.User, error)
-	UpdateUser(user *domain.User) error
+	UpdateProfileFields(userID primitive.ObjectID, name, handle *string) error
+	SetPasswordHash(userID primitive.ObjectID, passwordHash string) error
+	LinkProvider(userID primitive.ObjectID, provider, providerID string) error
 	DeleteUser(id primitive.ObjectID) error
+	MarkEmailVerified(userID primitive.ObjectID) error
+	ClearPassword(userID primitive.ObjectID) error
 	AddPushToken(userID primitive.ObjectID, pushToken domain.PushToken) error
 	RemovePushToken(userID primitive.ObjectID, token string) error
+	SearchUsers(filter domain.UserSearchFilter, skip, limit int64) ([]*domain.User, int64, error)
+	SetUserSuspended(userID primitive.ObjectID, suspended bool, reason string) error
+	UpdateUserRoles(userID primitive.ObjectID, roles []string) error
+	SetPendingMFASecret(userID primitive.ObjectID, secret string) error
+	EnableMFA(userID primitive.ObjectID, secret string, recoveryCodeHashes []string) error
+	DisableMFA(userID primitive.ObjectID) error
+	SetMFARecoveryCodes(userID primitive.ObjectID, recoveryCodeHashes []string) error
+	UseMFARecoveryCode(userID primitive.ObjectID, recoveryCodeHash string) (bool, error)
+	UseMFAStep(userID primitive.ObjectID, step int64) (bool, error)
+	ScheduleDeletion(userID primitive.ObjectID, scheduledFor time.Time) error
+	CancelDeletion(userID primitive.ObjectID) (bool, error)
+	GetUsersDueForDeletion(before time.Time, limit int64) ([]*domain.User, error)
 }
 
 type RefreshTokenRepository interface {
 	CreateRefreshToken(token *domain.RefreshToken) error
 	GetRefreshToken(token string) (*domain.RefreshToken, error)
+	RotateRefreshToken(token, replacedBy string) (bool, error)
+	RevokeRefreshTokenFamily(familyID string) error
 	DeleteRefreshToken(token string) error
 	DeleteUserRefreshTokens(userID primitive.ObjectID) error
+	DeleteExpiredRefreshTokens() error
 }
 
 type PasswordResetRepository interface {
@@ -37,6 +59,14 @@ type PasswordResetRepository interface {
 	DeleteExpiredResets() error
 }
 
+type EmailVerificationRepository interface {
+	CreateEmailVerification(verification *domain.EmailVerification) error
+	GetEmailVerification(token string) (*domain.EmailVerification, error)
+	MarkEmailVerificationUsed(token string) error
+	DeleteUserEmailVerifications(userID primitive.ObjectID) error
+	DeleteExpiredVerifications() error
+}
+
 type userRepository struct {
 	db         *mongo.Database
 	collection *mongo.Collection
@@ -52,10 +82,28 @@ type passwordResetRepository struct {
 	collection *mongo.Collection
 }
 
+type emailVerificationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+// ErrHandleTaken is returned when setting a handle another user has.
+var ErrHandleTaken = errors.New("handle is already taken")
+
 func NewUserRepository(db *mongo.Database) UserRepository {
+	collection := db.Collection("users")
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "handle", Value: 1}},
+		// Most users have no handle
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"handle": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		log.Printf("Failed to create users handle index: %v", err)
+	}
+
 	return &userRepository{
 		db:         db,
-		collection: db.Collection("users"),
+		collection: collection,
 	}
 }
 
@@ -73,6 +121,13 @@ func NewPasswordResetRepository(db *mongo.Database) PasswordResetRepository {
 	}
 }
 
+func NewEmailVerificationRepository(db *mongo.Database) EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db:         db,
+		collection: db.Collection("email_verifications"),
+	}
+}
+
 // User Repository Implementation
 func (r *userRepository) CreateUser(user *domain.User) (*domain.User, error) {
 	user.ID = primitive.NewObjectID()
@@ -126,12 +181,38 @@ func (r *userRepository) GetUserByProviderID(provider, providerID string) (*doma
 	return &user, nil
 }
 
-func (r *userRepository) UpdateUser(user *domain.User) error {
-	user.UpdatedAt = time.Now()
+// UpdateProfileFields sets the name and handle, leaving nil ones unchanged.
+// Users are only ever updated field by field, so concurrent writes to other
+// fields, such as streaks, XP or a suspension, are never reverted.
+func (r *userRepository) UpdateProfileFields(userID primitive.ObjectID, name, handle *string) error {
+	set := bson.M{"updated_at": time.Now()}
+	if name != nil {
+		set["name"] = *name
+	}
+	if handle != nil {
+		set["handle"] = *handle
+	}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": userID}, bson.M{"$set": set})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrHandleTaken
+	}
+	return err
+}
+
+func (r *userRepository) SetPasswordHash(userID primitive.ObjectID, passwordHash string) error {
 	_, err := r.collection.UpdateOne(
 		context.Background(),
-		bson.M{"_id": user.ID},
-		bson.M{"$set": user},
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"password_hash": passwordHash, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *userRepository) LinkProvider(userID primitive.ObjectID, provider, providerID string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"provider": provider, "provider_id": providerID, "updated_at": time.Now()}},
 	)
 	return err
 }
@@ -141,6 +222,32 @@ func (r *userRepository) DeleteUser(id primitive.ObjectID) error {
 	return err
 }
 
+func (r *userRepository) MarkEmailVerified(userID primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"email_verified":    true,
+			"email_verified_at": now,
+			"updated_at":        now,
+		}},
+	)
+	return err
+}
+
+func (r *userRepository) ClearPassword(userID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"password_hash": ""},
+		},
+	)
+	return err
+}
+
 func (r *userRepository) AddPushToken(userID primitive.ObjectID, pushToken domain.PushToken) error {
 	pushToken.CreatedAt = time.Now()
 	_, err := r.collection.UpdateOne(
@@ -175,6 +282,228 @@ func (r *userRepository) RemovePushToken(userID primitive.ObjectID, token string
 	return err
 }
 
+func (r *userRepository) SearchUsers(filter domain.UserSearchFilter, skip, limit int64) ([]*domain.User, int64, error) {
+	query := bson.M{}
+	var conditions []bson.M
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"name": pattern},
+			{"email": pattern},
+		}})
+	}
+	if filter.Role != "" {
+		roleCondition := bson.M{"roles": filter.Role}
+		if filter.Role == domain.RoleLearner {
+			// Users without stored roles are learners
+			roleCondition = bson.M{"$or": []bson.M{
+				{"roles": filter.Role},
+				{"roles": bson.M{"$exists": false}},
+			}}
+		}
+		conditions = append(conditions, roleCondition)
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			conditions = append(conditions, bson.M{"suspended": true})
+		} else {
+			conditions = append(conditions, bson.M{"suspended": bson.M{"$ne": true}})
+		}
+	}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	total, err := r.collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"password_hash": 0, "push_tokens": 0})
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	var users []*domain.User
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) SetUserSuspended(userID primitive.ObjectID, suspended bool, reason string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"suspended": true, "suspended_at": now, "suspension_reason": reason, "updated_at": now},
+	}
+	if !suspended {
+		update = bson.M{
+			"$set":   bson.M{"suspended": false, "updated_at": now},
+			"$unset": bson.M{"suspended_at": "", "suspension_reason": ""},
+		}
+	}
+
+	result, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateUserRoles(userID primitive.ObjectID, roles []string) error {
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"roles": roles, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *userRepository) SetPendingMFASecret(userID primitive.ObjectID, secret string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"mfa_pending_secret": secret, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *userRepository) EnableMFA(userID primitive.ObjectID, secret string, recoveryCodeHashes []string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{
+			"$set": bson.M{
+				"mfa_enabled":        true,
+				"mfa_secret":         secret,
+				"mfa_recovery_codes": recoveryCodeHashes,
+				"updated_at":         time.Now(),
+			},
+			"$unset": bson.M{"mfa_pending_secret": "", "mfa_last_used_step": ""},
+		},
+	)
+	return err
+}
+
+func (r *userRepository) DisableMFA(userID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{
+			"$set":   bson.M{"mfa_enabled": false, "updated_at": time.Now()},
+			"$unset": bson.M{"mfa_secret": "", "mfa_pending_secret": "", "mfa_recovery_codes": "", "mfa_last_used_step": ""},
+		},
+	)
+	return err
+}
+
+func (r *userRepository) SetMFARecoveryCodes(userID primitive.ObjectID, recoveryCodeHashes []string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"mfa_recovery_codes": recoveryCodeHashes, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// UseMFARecoveryCode removes a recovery code hash. It reports false if the code
+// was already used, so two concurrent logins cannot both spend it.
+func (r *userRepository) UseMFARecoveryCode(userID primitive.ObjectID, recoveryCodeHash string) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID, "mfa_recovery_codes": recoveryCodeHash},
+		bson.M{"$pull": bson.M{"mfa_recovery_codes": recoveryCodeHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UseMFAStep records the TOTP time step of an accepted code. It reports false
+// if that step (or a later one) was already used, which blocks code replay.
+func (r *userRepository) UseMFAStep(userID primitive.ObjectID, step int64) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{
+			"_id": userID,
+			"$or": []bson.M{
+				{"mfa_last_used_step": bson.M{"$exists": false}},
+				{"mfa_last_used_step": bson.M{"$lt": step}},
+			},
+		},
+		bson.M{"$set": bson.M{"mfa_last_used_step": step}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) ScheduleDeletion(userID primitive.ObjectID, scheduledFor time.Time) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"deletion_requested_at":  now,
+			"deletion_scheduled_for": scheduledFor,
+			"updated_at":             now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// CancelDeletion reports whether a scheduled deletion was cancelled.
+func (r *userRepository) CancelDeletion(userID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID, "deletion_scheduled_for": bson.M{"$exists": true}},
+		bson.M{
+			"$unset": bson.M{"deletion_requested_at": "", "deletion_scheduled_for": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) GetUsersDueForDeletion(before time.Time, limit int64) ([]*domain.User, error) {
+	opts := options.Find().SetSort(bson.M{"deletion_scheduled_for": 1}).SetLimit(limit)
+	cursor, err := r.collection.Find(context.Background(), bson.M{"deletion_scheduled_for": bson.M{"$lte": before}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var users []*domain.User
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // Refresh Token Repository Implementation
 func (r *refreshTokenRepository) CreateRefreshToken(token *domain.RefreshToken) error {
 	token.ID = primitive.NewObjectID()
@@ -195,6 +524,35 @@ func (r *refreshTokenRepository) GetRefreshToken(token string) (*domain.RefreshT
 	return &refreshToken, nil
 }
 
+// RotateRefreshToken marks a token as rotated. It only succeeds for a token that
+// has not been rotated or revoked yet, so two concurrent refreshes with the same
+// token cannot both win; the loser is treated as reuse.
+func (r *refreshTokenRepository) RotateRefreshToken(token, replacedBy string) (bool, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{
+			"token":      token,
+			"revoked":    bson.M{"$ne": true},
+			"rotated_at": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"rotated_at": now, "replaced_by": replacedBy}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeRefreshTokenFamily(familyID string) error {
+	_, err := r.collection.UpdateMany(
+		context.Background(),
+		bson.M{"family_id": familyID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	return err
+}
+
 func (r *refreshTokenRepository) DeleteRefreshToken(token string) error {
 	_, err := r.collection.DeleteOne(context.Background(), bson.M{"token": token})
 	return err
@@ -205,6 +563,15 @@ func (r *refreshTokenRepository) DeleteUserRefreshTokens(userID primitive.Object
 	return err
 }
 
+// DeleteExpiredRefreshTokens removes expired tokens. Rotated tokens are kept
+// until they expire so that reuse can still be detected.
+func (r *refreshTokenRepository) DeleteExpiredRefreshTokens() error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{
+		"expires_at": bson.M{"$lt": time.Now()},
+	})
+	return err
+}
+
 // Password Reset Repository Implementation
 func (r *passwordResetRepository) CreatePasswordReset(reset *domain.PasswordReset) error {
 	reset.ID = primitive.NewObjectID()
@@ -244,3 +611,48 @@ func (r *passwordResetRepository) DeleteExpiredResets() error {
 	})
 	return err
 }
+
+// Email Verification Repository Implementation
+func (r *emailVerificationRepository) CreateEmailVerification(verification *domain.EmailVerification) error {
+	verification.ID = primitive.NewObjectID()
+	verification.CreatedAt = time.Now()
+	_, err := r.collection.InsertOne(context.Background(), verification)
+	return err
+}
+
+func (r *emailVerificationRepository) GetEmailVerification(token string) (*domain.EmailVerification, error) {
+	var verification domain.EmailVerification
+	err := r.collection.FindOne(context.Background(), bson.M{
+		"token":      token,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&verification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("email verification token not found or expired")
+		}
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *emailVerificationRepository) MarkEmailVerificationUsed(token string) error {
+	_, err := r.collection.UpdateOne(
+		context.Background(),
+		bson.M{"token": token},
+		bson.M{"$set": bson.M{"used": true}},
+	)
+	return err
+}
+
+func (r *emailVerificationRepository) DeleteUserEmailVerifications(userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
+
+func (r *emailVerificationRepository) DeleteExpiredVerifications() error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{
+		"expires_at": bson.M{"$lt": time.Now()},
+	})
+	return err
+}
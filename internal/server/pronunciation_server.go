@@ -2,23 +2,17 @@
 package server
 
 import (
-	"log"
-	"os"
-
 	"github.com/gin-gonic/gin"
 	"lissanai.com/backend/internal/handler"
+	"lissanai.com/backend/internal/llm"
+	"lissanai.com/backend/internal/service"
 	"lissanai.com/backend/internal/usecase"
 )
 
-func SetupPronunciationRoutes(router *gin.RouterGroup) {
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("FATAL: GEMINI_API_KEY is not set.")
-	}
-
-	// We no longer create a genai.Client here.
-	// We pass the API key string directly to the usecase.
-	pronunciationUC := usecase.NewPronunciationUsecase(geminiAPIKey)
+func SetupPronunciationRoutes(router *gin.RouterGroup, llmClient llm.Client, prompts *service.PromptRegistry, aiCache *service.AICache) {
+	// Sentences and assessments go through the LLM client, which picks the
+	// models configured for them, with prompts from the registry.
+	pronunciationUC := usecase.NewPronunciationUsecase(llmClient, prompts, aiCache)
 
 	// The rest of the setup is the same.
 	pronunciationHandler := handler.NewPronunciationHandler(pronunciationUC)
@@ -2,8 +2,11 @@
 package server
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 	"net/http"
 	
@@ -11,7 +14,10 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
 	"lissanai.com/backend/internal/handler"
+	"lissanai.com/backend/internal/jobs"
+	"lissanai.com/backend/internal/llm"
 	"lissanai.com/backend/internal/middleware"
 	"lissanai.com/backend/internal/repository"
 	"lissanai.com/backend/internal/service"
@@ -25,12 +31,24 @@ import (
 func New() *gin.Engine {
 	router := gin.Default()
 
+	// Only take the client IP from X-Forwarded-For when our own proxy set it;
+	// otherwise clients could pick the IP that login throttling counts against
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+
 	// --- CORS Middleware ---
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Replace "*" with your frontend URL in production
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "X-Cache"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -42,56 +60,159 @@ func New() *gin.Engine {
 	}
 
 	// --- Services ---
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-this-in-production" // Default for development
-		log.Println("Warning: Using default JWT secret. Set JWT_SECRET environment variable in production.")
+	// Outside development a real signing key is mandatory
+	devMode := os.Getenv("APP_ENV") == "development"
+	jwtKeys, err := service.LoadKeySetFromEnv(devMode)
+	if err != nil {
+		log.Fatal("Failed to load JWT keys: ", err)
 	}
 
-	jwtService := service.NewJWTService(jwtSecret)
+	jwtService := service.NewJWTService(jwtKeys)
 	passwordService := service.NewPasswordService()
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	emailService := service.NewEmailService()
+	socialAuthService := service.NewSocialAuthServiceFromEnv()
+	totpService := service.NewTOTPService("LissanAI")
+	settingsService := service.NewSettingsService(db)
 
-	// Create the AI service.
-	aiService, err := service.NewAiService()
+	// Create the AI services. Every model call goes through the LLM router,
+	// which picks each feature's models from its config and fails over.
+	llmClient, err := llm.NewRouterFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure LLM providers: ", err)
+	}
+	// Their prompts come from the prompt registry: files, plus versions and
+	// experiments the content team stores in MongoDB.
+	promptTemplates, promptRollouts, err := service.LoadPromptFiles()
+	if err != nil {
+		log.Fatal("Failed to load prompts: ", err)
+	}
+	promptRegistry, err := service.NewPromptRegistry(promptTemplates, promptRollouts, repository.NewPromptRepository(db), settingsService)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("Failed to load prompts: ", err)
 	}
-	chatAiService, _ := service.NewChatAiService(apiKey)
+	// Replies to repeated grammar checks, emails and practice sentences are
+	// cached, and identical concurrent requests share one call
+	aiCache, err := service.NewAICacheFromEnv(db, llmClient)
+	if err != nil {
+		log.Fatal("Failed to configure AI cache: ", err)
+	}
+	aiService := service.NewAiService(llmClient, promptRegistry, aiCache)
+	chatAiService := service.NewChatAiService(llmClient, promptRegistry)
 
 	// --- Repositories ---
 	userRepo := repository.NewUserRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	userSessionRepo := repository.NewUserSessionRepository(db)
 	chatSessionRepo := repository.NewMongoSessionRepo(db)
 	chatMessageRepo := repository.NewMongoMessageRepo(db)
 	learningRepo := repository.NewLearningRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	userDataRepo := repository.NewUserDataRepository(db)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(db)
+	pushOutboxRepo := repository.NewPushOutboxRepository(db)
+
+	// Brute-force counters are shared through MongoDB unless a single instance opts into memory
+	var attemptRepo repository.AttemptRepository
+	if os.Getenv("AUTH_ATTEMPT_STORE") == "memory" {
+		attemptRepo = repository.NewInMemoryAttemptRepository()
+	} else {
+		attemptRepo = repository.NewAttemptRepository(db)
+	}
+
+	// Emails are queued in the outbox and delivered by the outbox worker
+	emailTemplates, err := service.LoadEmailTemplates()
+	if err != nil {
+		log.Fatal("Failed to load email templates: ", err)
+	}
+	mailTransport, err := service.NewMailTransportFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure mail transport: ", err)
+	}
+	emailDispatcher := service.NewEmailDispatcher(emailOutboxRepo, mailTransport)
+	emailService := service.NewEmailService(emailTemplates, emailOutboxRepo, emailDispatcher.Wake)
+
+	// Push notifications are queued the same way and sent through FCM or APNs
+	pushProviders, err := service.NewPushProvidersFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure push providers: ", err)
+	}
+	pushDispatcher := service.NewPushDispatcher(pushOutboxRepo, userRepo, userSessionRepo, pushProviders)
+	pushService := service.NewPushService(pushOutboxRepo, pushDispatcher.Wake)
 
 	// --- Use Cases ---
-	authUsecase := usecase.NewAuthUsecase(userRepo, refreshTokenRepo, passwordResetRepo, jwtService, passwordService, emailService)
-	userUsecase := usecase.NewUserUsecase(userRepo, refreshTokenRepo)
+	authThrottle := usecase.NewAuthThrottle(attemptRepo)
+	authUsecase := usecase.NewAuthUsecase(userRepo, refreshTokenRepo, passwordResetRepo, emailVerificationRepo, userSessionRepo, jwtService, passwordService, emailService, socialAuthService, totpService, authThrottle)
+	mfaUsecase := usecase.NewMFAUsecase(userRepo, totpService, passwordService, authThrottle)
+	userUsecase := usecase.NewUserUsecase(userRepo, refreshTokenRepo, userSessionRepo, settingsService)
+	sessionUsecase := usecase.NewSessionUsecase(userRepo, refreshTokenRepo, userSessionRepo)
 	grammer_usecase := usecase.NewGrammarUsecase(aiService)
 	chat_usecase := usecase.NewChatUsecase(chatSessionRepo, chatMessageRepo, chatAiService)
 	learningUsecase := usecase.NewLearningUsecase(learningRepo)
+	// Periodic jobs run through the scheduler, once across all instances
+	scheduler := jobs.NewScheduler(repository.NewJobRepository(db))
+	adminUsecase := usecase.NewAdminUsecase(userRepo, refreshTokenRepo, userSessionRepo, learningRepo, auditLogRepo, scheduler, promptRegistry)
+
+	// Deleted accounts can be restored for this many days before their data is purged (0 deletes immediately)
+	deletionGraceDays, _ := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"))
+	accountUsecase := usecase.NewAccountUsecase(userRepo, userDataRepo, refreshTokenRepo, userSessionRepo, time.Duration(deletionGraceDays)*24*time.Hour)
 
 	// --- Services ---
-	streakService := service.NewStreakService(db)
+	achievementRules, err := service.LoadAchievementRules()
+	if err != nil {
+		log.Fatal("Failed to load achievement rules: ", err)
+	}
+	achievementService := service.NewAchievementService(db, achievementRules, pushService)
+	xpWeights, err := service.LoadXPWeights()
+	if err != nil {
+		log.Fatal("Failed to load XP weights: ", err)
+	}
+	friendService := service.NewFriendService(db)
+	xpService := service.NewXPService(db, xpWeights, friendService)
+	streakService := service.NewStreakService(db, pushService, achievementService, xpService)
+	reminderService := service.NewReminderService(db, emailService, pushService)
+	profileService := service.NewProfileService(db, streakService, achievementService)
 	
 	// --- Background Jobs ---
 	// Note: In production, you might want to start these jobs in a separate process
 	// For now, we'll start them here for simplicity
+	jobs.NewEmailJobs(emailDispatcher).StartOutboxWorker(context.Background())
+	jobs.NewPushJobs(pushDispatcher).StartPushWorker(context.Background())
+	jobs.NewReminderJobs(reminderService).StartReminderScheduler(context.Background())
+	jobs.NewPromptJobs(promptRegistry).StartReloader(context.Background())
+	if err := jobs.NewStreakJobs(streakService).Register(scheduler); err != nil {
+		log.Fatal("Failed to schedule streak jobs: ", err)
+	}
+	if err := jobs.NewLeagueJobs(xpService).Register(scheduler); err != nil {
+		log.Fatal("Failed to schedule league jobs: ", err)
+	}
+	accountJobs := jobs.NewAccountJobs(accountUsecase, refreshTokenRepo, passwordResetRepo, emailVerificationRepo)
+	if err := accountJobs.Register(scheduler, deletionGraceDays > 0); err != nil {
+		log.Fatal("Failed to schedule account jobs: ", err)
+	}
+	scheduler.Start(context.Background())
 
 	// --- Handlers ---
 	authHandler := handler.NewAuthHandler(authUsecase)
 	userHandler := handler.NewUserHandler(userUsecase)
+	sessionHandler := handler.NewSessionHandler(sessionUsecase)
+	mfaHandler := handler.NewMFAHandler(mfaUsecase)
 	grammer_handler := handler.NewGrammarHandler(grammer_usecase, streakService)
-	chat_handler := handler.NewChatHandler(chat_usecase, streakService)
+	chat_handler := handler.NewChatHandler(chat_usecase, streakService, xpService)
 	pronunciationHandler := handler.NewPronunciationActivityHandler(streakService)
-	learningHandler := handler.NewLearningHandler(learningUsecase, streakService)
+	learningHandler := handler.NewLearningHandler(learningUsecase, streakService, xpService)
+	adminHandler := handler.NewAdminHandler(adminUsecase)
+	jwksHandler := handler.NewJWKSHandler(jwtService)
+	accountHandler := handler.NewAccountHandler(accountUsecase)
+	settingsHandler := handler.NewSettingsHandler(settingsService)
+	achievementHandler := handler.NewAchievementHandler(achievementService)
+	xpHandler := handler.NewXPHandler(xpService)
+	friendHandler := handler.NewFriendHandler(friendService)
+	profileHandler := handler.NewProfileHandler(profileService)
 
 	// --- Middleware ---
-	authMiddleware := middleware.AuthMiddleware(jwtService)
+	authMiddleware := middleware.AuthMiddleware(jwtService, sessionUsecase, userRepo)
+	verifiedEmailPolicy := middleware.VerifiedEmailPolicyFromEnv()
 // This is a public endpoint used by services like UptimeRobot to keep the service alive.
 	healthHandler := func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -101,6 +222,14 @@ func New() *gin.Engine {
 
 	router.GET("/health", healthHandler)
 	router.HEAD("/health", healthHandler) 
+
+	// Public keys for verifying our tokens in other services
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// Public profiles users opt into, with images to embed elsewhere
+	router.GET("/u/:handle", profileHandler.GetPublicProfile)
+	router.GET("/u/:handle/calendar.svg", profileHandler.GetPublicCalendarSVG)
+	router.GET("/u/:handle/calendar.png", profileHandler.GetPublicCalendarPNG)
 	
 	// --- Routes ---
 	apiV1 := router.Group("/api/v1")
@@ -111,9 +240,12 @@ func New() *gin.Engine {
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/social", authHandler.SocialAuth)
+			auth.POST("/mfa/verify", authHandler.VerifyMFA)
 			auth.POST("/refresh", authHandler.RefreshToken)
 			auth.POST("/forgot-password", authHandler.ForgotPassword)
 			auth.POST("/reset-password", authHandler.ResetPassword)
+			auth.POST("/verify-email", authHandler.VerifyEmail)
+			auth.POST("/resend-verification", authHandler.ResendVerification)
 
 			// Protected auth routes
 			auth.POST("/logout", authMiddleware, authHandler.Logout)
@@ -126,11 +258,12 @@ func New() *gin.Engine {
 
 		// --- Chat/Interview routes ---
 		chatAPI := apiV1.Group("/interview")
+		chatAPI.Use(authMiddleware, verifiedEmailPolicy.For("interview", userUsecase))
 		{
-			chatAPI.POST("/start", authMiddleware, chat_handler.StartSessionHandler)
-			chatAPI.GET("/question", authMiddleware, chat_handler.GetNextQuestionHandler)
-			chatAPI.POST("/answer", authMiddleware, chat_handler.SubmitAnswerHandler)
-			chatAPI.POST("/:session_id/end", authMiddleware, chat_handler.EndSessionHandler)
+			chatAPI.POST("/start", chat_handler.StartSessionHandler)
+			chatAPI.GET("/question", chat_handler.GetNextQuestionHandler)
+			chatAPI.POST("/answer", chat_handler.SubmitAnswerHandler)
+			chatAPI.POST("/:session_id/end", chat_handler.EndSessionHandler)
 
 		}
 
@@ -140,13 +273,49 @@ func New() *gin.Engine {
 		{
 			users.GET("/me", userHandler.GetProfile)
 			users.PATCH("/me", userHandler.UpdateProfile)
-			users.DELETE("/me", userHandler.DeleteAccount)
+			users.DELETE("/me", accountHandler.DeleteAccount)
+			users.POST("/me/restore", accountHandler.RestoreAccount)
+			users.GET("/me/export", accountHandler.ExportData)
 			users.POST("/me/push-token", userHandler.AddPushToken)
+			users.DELETE("/me/push-token", userHandler.RemovePushToken)
+			users.GET("/me/settings", settingsHandler.GetSettings)
+			users.PATCH("/me/settings", settingsHandler.UpdateSettings)
+			users.GET("/me/achievements", achievementHandler.GetAchievements)
+			users.GET("/me/xp", xpHandler.GetXP)
+
+			// Friends, who make up the friends leaderboard
+			users.GET("/me/friends", friendHandler.ListFriends)
+			users.POST("/me/friends", friendHandler.SendFriendRequest)
+			users.POST("/me/friends/:id/accept", friendHandler.AcceptFriendRequest)
+			users.DELETE("/me/friends/:id", friendHandler.RemoveFriend)
+
+			// Device sessions
+			users.GET("/me/sessions", sessionHandler.ListSessions)
+			users.DELETE("/me/sessions", sessionHandler.RevokeOtherSessions)
+			users.DELETE("/me/sessions/:id", sessionHandler.RevokeSession)
+
+			// Two-factor authentication
+			users.POST("/me/mfa/enroll", mfaHandler.Enroll)
+			users.POST("/me/mfa/confirm", mfaHandler.Confirm)
+			users.POST("/me/mfa/disable", mfaHandler.Disable)
+			users.POST("/me/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
 		}
 
-		// Email routes (protected)
+		// Email routes; open to anonymous users unless the verified-email policy gates them
 		emailGroup := apiV1.Group("/")
-		SetupEmailRoutes(emailGroup)
+		if verifiedEmailPolicy.Requires("email") {
+			SetupEmailRoutes(emailGroup, llmClient, promptRegistry, aiCache, streakService, authMiddleware, verifiedEmailPolicy.For("email", userUsecase))
+		} else {
+			SetupEmailRoutes(emailGroup, llmClient, promptRegistry, aiCache, streakService, middleware.OptionalAuthMiddleware(authMiddleware))
+		}
+
+		// Leaderboard routes (protected)
+		leaderboards := apiV1.Group("/leaderboards")
+		leaderboards.Use(authMiddleware)
+		{
+			leaderboards.GET("/league", xpHandler.GetLeagueLeaderboard)
+			leaderboards.GET("/friends", xpHandler.GetFriendsLeaderboard)
+		}
 
 		// Learning routes (protected)
 		learningRoutes := apiV1.Group("/learning")
@@ -166,8 +335,8 @@ func New() *gin.Engine {
 		}
 
 		// Free Speaking route
-		SetupSpeakingRoutes(apiV1)
-		SetupPronunciationRoutes(apiV1)
+		SetupSpeakingRoutes(apiV1, llmClient, promptRegistry)
+		SetupPronunciationRoutes(apiV1, llmClient, promptRegistry, aiCache)
 
 		// Pronunciation routes (protected)
 		pronunciationRoutes := apiV1.Group("/pronunciation")
@@ -177,7 +346,48 @@ func New() *gin.Engine {
 		}
 
 		// Streak routes (protected)
-		SetupStreakRoutes(apiV1, authMiddleware, db)
+		SetupStreakRoutes(apiV1, authMiddleware, streakService)
+
+		// Admin routes (protected, role-restricted, audited)
+		admin := apiV1.Group("/admin")
+		admin.Use(authMiddleware)
+		{
+			adminUsers := admin.Group("/users", middleware.RequireRole(domain.RoleAdmin))
+			{
+				adminUsers.GET("", adminHandler.SearchUsers)
+				adminUsers.POST("/:id/suspend", adminHandler.SuspendUser)
+				adminUsers.POST("/:id/unsuspend", adminHandler.UnsuspendUser)
+				adminUsers.PUT("/:id/roles", adminHandler.UpdateUserRoles)
+			}
+
+			content := admin.Group("/learning", middleware.RequireRole(domain.RoleContentEditor, domain.RoleAdmin))
+			{
+				content.POST("/paths", adminHandler.CreateLearningPath)
+				content.PUT("/paths/:id", adminHandler.UpdateLearningPath)
+				content.DELETE("/paths/:id", adminHandler.DeleteLearningPath)
+				content.POST("/lessons", adminHandler.CreateLesson)
+				content.PUT("/lessons/:id", adminHandler.UpdateLesson)
+				content.DELETE("/lessons/:id", adminHandler.DeleteLesson)
+				content.POST("/quizzes", adminHandler.CreateQuiz)
+				content.PUT("/quizzes/:id", adminHandler.UpdateQuiz)
+				content.DELETE("/quizzes/:id", adminHandler.DeleteQuiz)
+			}
+
+			admin.GET("/audit-logs", middleware.RequireRole(domain.RoleAdmin), adminHandler.ListAuditLogs)
+
+			prompts := admin.Group("/prompts", middleware.RequireRole(domain.RoleContentEditor, domain.RoleAdmin))
+			{
+				prompts.GET("", adminHandler.ListPrompts)
+				prompts.POST("/:name/templates", adminHandler.CreatePromptTemplate)
+				prompts.PUT("/:name/rollout", adminHandler.UpdatePromptRollout)
+			}
+
+			adminJobs := admin.Group("/jobs", middleware.RequireRole(domain.RoleAdmin))
+			{
+				adminJobs.GET("", adminHandler.ListJobs)
+				adminJobs.POST("/:name/run", adminHandler.RunJob)
+			}
+		}
 	}
 
 	// --- Swagger ---
@@ -2,15 +2,13 @@ package server
 
 import (
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
+	"lissanai.com/backend/internal/domain"
 	"lissanai.com/backend/internal/handler"
+	"lissanai.com/backend/internal/middleware"
 	"lissanai.com/backend/internal/service"
 )
 
-func SetupStreakRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, db *mongo.Database) {
-	// Initialize streak service
-	streakService := service.NewStreakService(db)
-
+func SetupStreakRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, streakService *service.StreakService) {
 	// Initialize streak handler
 	streakHandler := handler.NewStreakHandler(streakService)
 
@@ -20,7 +18,8 @@ func SetupStreakRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc,
 	{
 		streakRoutes.GET("/info", streakHandler.GetStreakInfo)
 		streakRoutes.POST("/freeze", streakHandler.FreezeStreak)
-		streakRoutes.POST("/activity", streakHandler.RecordActivity) // For manual testing
+		streakRoutes.GET("/freezes", streakHandler.GetStreakFreezes)
+		streakRoutes.POST("/activity", middleware.RequireRole(domain.RoleAdmin), streakHandler.RecordActivity) // For manual testing
 		streakRoutes.GET("/calendar", streakHandler.GetActivityCalendar) // GitHub-like activity calendar
 	}
 }
\ No newline at end of file
@@ -0,0 +1,356 @@
+// internal/service/achievement_service.go
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+//go:embed achievements/rules.json
+var defaultAchievementRules []byte
+
+// Metrics an achievement rule can require a target for
+const (
+	// Number of recorded activities of the rule's activity type
+	metricActivityCount = "activity_count"
+	// Longest streak in days
+	metricStreak = "streak"
+	// Best quiz score in percent
+	metricQuizPercentage = "quiz_percentage"
+)
+
+// AchievementRule awards a badge once a metric of the user reaches Target.
+// Title and Description are keyed by UI language and must include English.
+type AchievementRule struct {
+	ID          string            `json:"id"`
+	Icon        string            `json:"icon"`
+	Metric      string            `json:"metric"`
+	Activity    string            `json:"activity,omitempty"` // for activity_count
+	Target      int               `json:"target"`
+	Title       map[string]string `json:"title"`
+	Description map[string]string `json:"description"`
+}
+
+// LoadAchievementRules reads the rules from the file in
+// ACHIEVEMENT_RULES_FILE, or the built-in rules if it is not set.
+func LoadAchievementRules() ([]AchievementRule, error) {
+	data := defaultAchievementRules
+	if path := os.Getenv("ACHIEVEMENT_RULES_FILE"); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read achievement rules: %w", err)
+		}
+	}
+
+	var rules []AchievementRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse achievement rules: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		switch {
+		case rule.ID == "" || seen[rule.ID]:
+			return nil, fmt.Errorf("achievement rule %q: missing or duplicate id", rule.ID)
+		case rule.Metric != metricActivityCount && rule.Metric != metricStreak && rule.Metric != metricQuizPercentage:
+			return nil, fmt.Errorf("achievement rule %q: unknown metric %q", rule.ID, rule.Metric)
+		case rule.Metric == metricActivityCount && rule.Activity == "":
+			return nil, fmt.Errorf("achievement rule %q: activity_count needs an activity", rule.ID)
+		case rule.Target <= 0:
+			return nil, fmt.Errorf("achievement rule %q: target must be positive", rule.ID)
+		case rule.Title["en"] == "" || rule.Description["en"] == "":
+			return nil, fmt.Errorf("achievement rule %q: English title and description are required", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	return rules, nil
+}
+
+// Badge notifications by UI language
+var achievementMessages = map[string][2]string{
+	"en": {"🏅 Badge earned: %s", "%s. Keep it up!"},
+	"am": {"🏅 አዲስ ባጅ፦ %s", "%s። በዚሁ ይቀጥሉ!"},
+}
+
+// AchievementService awards badges when the user's activities meet the
+// declared rules. Progress is always computed from the recorded activities,
+// quiz submissions and streaks, so rules added later also count past work.
+type AchievementService struct {
+	rules                 []AchievementRule
+	userCollection        *mongo.Collection
+	activityCollection    *mongo.Collection
+	quizCollection        *mongo.Collection
+	achievementCollection *mongo.Collection
+	pushService           PushService
+}
+
+func NewAchievementService(db *mongo.Database, rules []AchievementRule, pushService PushService) *AchievementService {
+	achievementCollection := db.Collection("user_achievements")
+	_, err := achievementCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "achievement_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Failed to create user_achievements index: %v", err)
+	}
+
+	return &AchievementService{
+		rules:                 rules,
+		userCollection:        db.Collection("users"),
+		activityCollection:    db.Collection("streak_activities"),
+		quizCollection:        db.Collection("quiz_submissions"),
+		achievementCollection: achievementCollection,
+		pushService:           pushService,
+	}
+}
+
+// EvaluateActivity awards the badges an activity may have earned: rules
+// counting its type, streak rules, and quiz score rules after a passed quiz.
+// It returns the newly awarded rules.
+func (s *AchievementService) EvaluateActivity(ctx context.Context, userID primitive.ObjectID, activityType string) ([]AchievementRule, error) {
+	awarded, err := s.awarded(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]int)
+	var earned []AchievementRule
+	for _, rule := range s.rules {
+		if _, ok := awarded[rule.ID]; ok || !ruleAffectedBy(rule, activityType) {
+			continue
+		}
+		value, err := s.metric(ctx, userID, rule, metrics)
+		if err != nil {
+			return earned, err
+		}
+		if value < rule.Target {
+			continue
+		}
+
+		_, err = s.achievementCollection.InsertOne(ctx, domain.UserAchievement{
+			UserID:        userID,
+			AchievementID: rule.ID,
+			AwardedAt:     time.Now(),
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			// Awarded by a concurrent activity
+			continue
+		}
+		if err != nil {
+			return earned, fmt.Errorf("failed to award achievement: %w", err)
+		}
+		log.Printf("🏅 User %s earned the %s achievement", userID.Hex(), rule.ID)
+		earned = append(earned, rule)
+	}
+
+	if len(earned) > 0 {
+		s.notify(ctx, userID, earned)
+	}
+	return earned, nil
+}
+
+func ruleAffectedBy(rule AchievementRule, activityType string) bool {
+	switch rule.Metric {
+	case metricActivityCount:
+		return rule.Activity == activityType
+	case metricQuizPercentage:
+		return activityType == "quiz_passed"
+	default:
+		// Any first activity of the day can extend the streak
+		return true
+	}
+}
+
+// GetAchievements returns every achievement with the user's progress, in
+// the user's language.
+func (s *AchievementService) GetAchievements(ctx context.Context, userID primitive.ObjectID) (*domain.AchievementsResponse, error) {
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	language := user.CurrentSettings().Language
+
+	awarded, err := s.awarded(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.AchievementsResponse{
+		Total:        len(s.rules),
+		Achievements: make([]domain.AchievementStatus, 0, len(s.rules)),
+	}
+	metrics := make(map[string]int)
+	for _, rule := range s.rules {
+		status := domain.AchievementStatus{
+			ID:          rule.ID,
+			Title:       localized(rule.Title, language),
+			Description: localized(rule.Description, language),
+			Icon:        rule.Icon,
+			Progress:    rule.Target,
+			Target:      rule.Target,
+		}
+		if awardedAt, ok := awarded[rule.ID]; ok {
+			status.Earned = true
+			status.AwardedAt = &awardedAt
+			response.Earned++
+		} else {
+			value, err := s.metric(ctx, userID, rule, metrics)
+			if err != nil {
+				return nil, err
+			}
+			status.Progress = min(value, rule.Target)
+		}
+		response.Achievements = append(response.Achievements, status)
+	}
+	return response, nil
+}
+
+// GetBadges returns the badges the user has earned, oldest first, with
+// titles in language.
+func (s *AchievementService) GetBadges(ctx context.Context, userID primitive.ObjectID, language string) ([]domain.PublicBadge, error) {
+	awarded, err := s.awarded(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	badges := make([]domain.PublicBadge, 0, len(awarded))
+	for _, rule := range s.rules {
+		if awardedAt, ok := awarded[rule.ID]; ok {
+			badges = append(badges, domain.PublicBadge{
+				ID:        rule.ID,
+				Title:     localized(rule.Title, language),
+				Icon:      rule.Icon,
+				AwardedAt: awardedAt,
+			})
+		}
+	}
+	sort.SliceStable(badges, func(i, j int) bool { return badges[i].AwardedAt.Before(badges[j].AwardedAt) })
+	return badges, nil
+}
+
+// awarded returns when each of the user's badges was awarded.
+func (s *AchievementService) awarded(ctx context.Context, userID primitive.ObjectID) (map[string]time.Time, error) {
+	cursor, err := s.achievementCollection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find achievements: %w", err)
+	}
+	var achievements []domain.UserAchievement
+	if err := cursor.All(ctx, &achievements); err != nil {
+		return nil, fmt.Errorf("failed to find achievements: %w", err)
+	}
+
+	awarded := make(map[string]time.Time, len(achievements))
+	for _, achievement := range achievements {
+		awarded[achievement.AchievementID] = achievement.AwardedAt
+	}
+	return awarded, nil
+}
+
+// metric computes the value a rule is measured by, reusing values already
+// computed for other rules in cache.
+func (s *AchievementService) metric(ctx context.Context, userID primitive.ObjectID, rule AchievementRule, cache map[string]int) (int, error) {
+	key := rule.Metric + ":" + rule.Activity
+	if value, ok := cache[key]; ok {
+		return value, nil
+	}
+
+	var value int
+	switch rule.Metric {
+	case metricActivityCount:
+		// Activities reported through the manual endpoint do not count
+		count, err := s.activityCollection.CountDocuments(ctx, bson.M{
+			"user_id":       userID,
+			"activity_type": rule.Activity,
+			"manual":        bson.M{"$ne": true},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to count activities: %w", err)
+		}
+		value = int(count)
+
+	case metricStreak:
+		var user domain.User
+		opts := options.FindOne().SetProjection(bson.M{"current_streak": 1, "longest_streak": 1})
+		if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+			return 0, fmt.Errorf("failed to find user: %w", err)
+		}
+		value = max(user.CurrentStreak, user.LongestStreak)
+
+	case metricQuizPercentage:
+		cursor, err := s.quizCollection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"user_id": userID, "max_score": bson.M{"$gt": 0}}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":  nil,
+				"best": bson.M{"$max": bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{"$score", 100}}, "$max_score"}}},
+			}}},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to find quiz scores: %w", err)
+		}
+		var results []struct {
+			Best float64 `bson:"best"`
+		}
+		if err := cursor.All(ctx, &results); err != nil {
+			return 0, fmt.Errorf("failed to find quiz scores: %w", err)
+		}
+		if len(results) > 0 {
+			value = int(results[0].Best)
+		}
+	}
+
+	cache[key] = value
+	return value, nil
+}
+
+func (s *AchievementService) notify(ctx context.Context, userID primitive.ObjectID, earned []AchievementRule) {
+	if s.pushService == nil {
+		return
+	}
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		log.Printf("Failed to find user %s for achievement notification: %v", userID.Hex(), err)
+		return
+	}
+	language := user.CurrentSettings().Language
+	message, ok := achievementMessages[language]
+	if !ok {
+		message = achievementMessages["en"]
+	}
+
+	for _, rule := range earned {
+		err := s.pushService.Notify(userID, &PushMessage{
+			Category: domain.PushCategoryAchievement,
+			Title:    fmt.Sprintf(message[0], localized(rule.Title, language)),
+			Body:     fmt.Sprintf(message[1], localized(rule.Description, language)),
+			Data:     map[string]string{"achievement_id": rule.ID},
+		})
+		if err != nil {
+			log.Printf("Failed to queue achievement notification for user %s: %v", userID.Hex(), err)
+		}
+	}
+}
+
+// localized picks the text for language, falling back to English.
+func localized(texts map[string]string, language string) string {
+	if text, ok := texts[language]; ok && text != "" {
+		return text
+	}
+	return texts["en"]
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"lissanai.com/backend/internal/domain"
 )
 
@@ -17,66 +19,51 @@ type ActivityCalendarService struct {
 }
 
 func NewActivityCalendarService(db *mongo.Database) *ActivityCalendarService {
+	summaryCollection := db.Collection("daily_activity_summaries")
+	_, err := summaryCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		// Summaries written before the index may have duplicates; scripts/rebucket_streaks merges them
+		log.Printf("Failed to create daily_activity_summaries index: %v", err)
+	}
+
 	return &ActivityCalendarService{
 		db:                   db,
-		summaryCollection:    db.Collection("daily_activity_summaries"),
+		summaryCollection:    summaryCollection,
 		activityCollection:   db.Collection("streak_activities"),
 	}
 }
 
-// RecordDailyActivity updates or creates a daily activity summary
+// RecordDailyActivity updates or creates a daily activity summary. The
+// summary's date is the calendar day of activityTime in its own location, so
+// pass the time in the user's time zone.
+//
+// A single upsert adds the activity, so concurrent activities are all
+// counted, and the unique (user_id, date) index keeps the first ones of a day
+// from creating two summaries.
 func (s *ActivityCalendarService) RecordDailyActivity(ctx context.Context, userID primitive.ObjectID, activityType string, activityTime time.Time) error {
 	dateStr := activityTime.Format("2006-01-02")
-	
-	// Check if summary exists for this date
 	filter := bson.M{
 		"user_id": userID,
 		"date":    dateStr,
 	}
-	
-	var existingSummary domain.DailyActivitySummary
-	err := s.summaryCollection.FindOne(ctx, filter).Decode(&existingSummary)
-	
-	if err == mongo.ErrNoDocuments {
-		// Create new daily summary
-		summary := domain.DailyActivitySummary{
-			ID:            primitive.NewObjectID(),
-			UserID:        userID,
-			Date:          dateStr,
-			ActivityCount: 1,
-			ActivityTypes: []string{activityType},
-			FirstActivity: activityTime,
-			LastActivity:  activityTime,
-			CreatedAt:     time.Now(),
-			UpdatedAt:     time.Now(),
-		}
-		
-		_, err = s.summaryCollection.InsertOne(ctx, summary)
-		return err
-	} else if err != nil {
-		return err
-	}
-	
-	// Update existing summary
 	update := bson.M{
-		"$inc": bson.M{"activity_count": 1},
-		"$set": bson.M{
-			"last_activity": activityTime,
-			"updated_at":    time.Now(),
-		},
+		"$inc":         bson.M{"activity_count": 1},
+		"$addToSet":    bson.M{"activity_types": activityType},
+		"$min":         bson.M{"first_activity": activityTime},
+		"$max":         bson.M{"last_activity": activityTime},
+		"$set":         bson.M{"updated_at": time.Now()},
+		"$setOnInsert": bson.M{"created_at": time.Now()},
 	}
-	
-	// Add activity type if not already present
-	if !contains(existingSummary.ActivityTypes, activityType) {
-		update["$addToSet"] = bson.M{"activity_types": activityType}
-	}
-	
-	// Update first activity if this one is earlier
-	if activityTime.Before(existingSummary.FirstActivity) {
-		update["$set"].(bson.M)["first_activity"] = activityTime
+
+	_, err := s.summaryCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the summary between our match and insert;
+		// it exists now, so the retry updates it
+		_, err = s.summaryCollection.UpdateOne(ctx, filter, update)
 	}
-	
-	_, err = s.summaryCollection.UpdateOne(ctx, filter, update)
 	return err
 }
 
@@ -116,6 +103,11 @@ func (s *ActivityCalendarService) GetActivityCalendar(ctx context.Context, userI
 	for _, summary := range summaries {
 		summaryMap[summary.Date] = summary
 	}
+
+	frozen, err := s.frozenDates(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Build the calendar response
 	response := &domain.ActivityCalendarResponse{
@@ -128,6 +120,7 @@ func (s *ActivityCalendarService) GetActivityCalendar(ctx context.Context, userI
 	var currentWeek domain.ActivityCalendarWeek
 	totalDays := 0
 	activeDays := 0
+	frozenDays := 0
 	totalActivities := 0
 	activityBreakdown := make(map[string]int)
 	mostActiveCount := 0
@@ -163,6 +156,9 @@ func (s *ActivityCalendarService) GetActivityCalendar(ctx context.Context, userI
 				mostActiveCount = summary.ActivityCount
 				mostActiveDay = dateStr
 			}
+		} else if frozen[dateStr] {
+			day.Frozen = true
+			frozenDays++
 		}
 		
 		currentWeek.Days = append(currentWeek.Days, day)
@@ -190,6 +186,7 @@ func (s *ActivityCalendarService) GetActivityCalendar(ctx context.Context, userI
 	// Fill in the response
 	response.TotalDays = totalDays
 	response.ActiveDays = activeDays
+	response.FrozenDays = frozenDays
 	response.CurrentStreak = user.CurrentStreak
 	response.LongestStreak = user.LongestStreak
 	response.Summary = domain.ActivityCalendarSummary{
@@ -230,6 +227,11 @@ func (s *ActivityCalendarService) GetActivityCalendarRange(ctx context.Context,
 	for _, summary := range summaries {
 		summaryMap[summary.Date] = summary
 	}
+
+	frozen, err := s.frozenDates(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Build weeks starting from the start date
 	response := &domain.ActivityCalendarResponse{
@@ -241,6 +243,7 @@ func (s *ActivityCalendarService) GetActivityCalendarRange(ctx context.Context,
 	var currentWeek domain.ActivityCalendarWeek
 	totalDays := 0
 	activeDays := 0
+	frozenDays := 0
 	totalActivities := 0
 	activityBreakdown := make(map[string]int)
 	mostActiveCount := 0
@@ -272,6 +275,9 @@ func (s *ActivityCalendarService) GetActivityCalendarRange(ctx context.Context,
 				mostActiveCount = summary.ActivityCount
 				mostActiveDay = dateStr
 			}
+		} else if frozen[dateStr] {
+			day.Frozen = true
+			frozenDays++
 		}
 		
 		currentWeek.Days = append(currentWeek.Days, day)
@@ -296,6 +302,7 @@ func (s *ActivityCalendarService) GetActivityCalendarRange(ctx context.Context,
 	
 	response.TotalDays = totalDays
 	response.ActiveDays = activeDays
+	response.FrozenDays = frozenDays
 	response.CurrentStreak = user.CurrentStreak
 	response.LongestStreak = user.LongestStreak
 	response.Summary = domain.ActivityCalendarSummary{
@@ -309,6 +316,32 @@ func (s *ActivityCalendarService) GetActivityCalendarRange(ctx context.Context,
 	return response, nil
 }
 
+// frozenDates returns the dates (YYYY-MM-DD) from startDate to endDate,
+// inclusive, that a streak freeze covered.
+func (s *ActivityCalendarService) frozenDates(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) (map[string]bool, error) {
+	// Frozen days are stored as midnight UTC of the date
+	start := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	cursor, err := s.db.Collection("streak_freezes").Find(ctx, bson.M{
+		"user_id": userID,
+		"used_on": bson.M{"$gte": start, "$lte": end},
+	}, options.Find().SetProjection(bson.M{"used_on": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var freezes []domain.StreakFreeze
+	if err := cursor.All(ctx, &freezes); err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]bool, len(freezes))
+	for _, freeze := range freezes {
+		days[freeze.UsedOn.UTC().Format("2006-01-02")] = true
+	}
+	return days, nil
+}
+
 // Helper functions
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -0,0 +1,221 @@
+// internal/service/ai_cache.go
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/sync/singleflight"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/llm"
+	"lissanai.com/backend/internal/repository"
+)
+
+const (
+	defaultAICacheTTL  = 24 * time.Hour
+	defaultAICacheSize = 1000
+
+	// How long a reply shared by concurrent requests may take; it is not
+	// cut short when the request that started it goes away
+	sharedAIReplyTimeout = 2 * time.Minute
+)
+
+// AICacheStatus says where an AI reply came from.
+type AICacheStatus string
+
+const (
+	AICacheHit    AICacheStatus = "HIT"    // the cache
+	AICacheMiss   AICacheStatus = "MISS"   // the model
+	AICacheShared AICacheStatus = "SHARED" // the model, for a concurrent identical request
+)
+
+type aiCacheStatusKey struct{}
+
+// WithAICacheStatus returns a ctx in which AICache.Fetch records the status
+// of the reply, and where it will be recorded.
+func WithAICacheStatus(ctx context.Context) (context.Context, *AICacheStatus) {
+	status := new(AICacheStatus)
+	return context.WithValue(ctx, aiCacheStatusKey{}, status), status
+}
+
+func recordAICacheStatus(ctx context.Context, status AICacheStatus) {
+	if recorded, ok := ctx.Value(aiCacheStatusKey{}).(*AICacheStatus); ok {
+		*recorded = status
+	}
+}
+
+// AICache keeps AI replies by what determines them, so a repeated request,
+// such as a client retry, is answered without calling the model again, and
+// concurrent identical requests make a single call.
+type AICache struct {
+	repo  repository.AICacheRepository // nil when caching is off
+	llm   llm.Client
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+// NewAICache caches replies in repo for ttl. Without repo identical
+// concurrent requests still share a call.
+func NewAICache(repo repository.AICacheRepository, client llm.Client, ttl time.Duration) *AICache {
+	return &AICache{repo: repo, llm: client, ttl: ttl}
+}
+
+// NewAICacheFromEnv stores replies where AI_CACHE says, for AI_CACHE_TTL
+// (24h by default):
+//
+//	memory  the AI_CACHE_SIZE (1000) most recently used replies, per instance
+//	mongo   the "ai_cache" collection, shared by all instances. Entries are
+//	        keyed by a hash of the input, not by user, so account export and
+//	        deletion do not reach them; they are gone after the TTL
+//	off     nothing is cached
+func NewAICacheFromEnv(db *mongo.Database, client llm.Client) (*AICache, error) {
+	ttl := defaultAICacheTTL
+	if value := os.Getenv("AI_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid AI_CACHE_TTL %q: use a duration such as 24h", value)
+		}
+		ttl = parsed
+	}
+
+	switch store := strings.ToLower(os.Getenv("AI_CACHE")); store {
+	case "", "memory":
+		size := defaultAICacheSize
+		if value := os.Getenv("AI_CACHE_SIZE"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid AI_CACHE_SIZE %q: use a positive number", value)
+			}
+			size = parsed
+		}
+		return NewAICache(repository.NewInMemoryAICacheRepository(size), client, ttl), nil
+	case "mongo":
+		return NewAICache(repository.NewAICacheRepository(db), client, ttl), nil
+	case "off":
+		return NewAICache(nil, client, ttl), nil
+	default:
+		return nil, fmt.Errorf("unknown AI_CACHE %q: use memory, mongo or off", store)
+	}
+}
+
+var (
+	horizontalSpace = regexp.MustCompile(`[ \t\f\v\p{Zs}]+`)
+	blankLines      = regexp.MustCompile(`\n{3,}`)
+)
+
+// normalizeAIInput removes differences in user input that do not change
+// the reply: line endings, runs of spaces and surrounding blank space.
+func normalizeAIInput(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(horizontalSpace.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// Key identifies a reply by the feature, the models it is routed to, the
+// prompt version and the input. Inputs differing only in blank space share
+// a key; the model is still sent the input as it was given.
+func (c *AICache) Key(feature, promptVersion string, input ...string) string {
+	parts := []string{feature, promptVersion}
+	if lister, ok := c.llm.(llm.ModelLister); ok {
+		parts = append(parts, strings.Join(lister.Models(feature), ","))
+	}
+	for _, text := range input {
+		parts = append(parts, normalizeAIInput(text))
+	}
+
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return feature + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Fetch decodes the reply cached under key into out. Without one, it calls
+// generate, shared with concurrent calls for the same key, and caches what
+// it returns for ttl, or the configured TTL if ttl is 0. Errors are not
+// cached.
+func (c *AICache) Fetch(ctx context.Context, key string, ttl time.Duration, out interface{}, generate func(ctx context.Context) (interface{}, error)) error {
+	if c.repo != nil {
+		entry, err := c.repo.GetEntry(key)
+		if err != nil {
+			log.Printf("Failed to read AI cache: %v", err)
+		} else if entry != nil {
+			if err := json.Unmarshal([]byte(entry.Value), out); err == nil {
+				recordAICacheStatus(ctx, AICacheHit)
+				return nil
+			}
+		}
+	}
+
+	leader := false
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		generateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedAIReplyTimeout)
+		defer cancel()
+
+		reply, err := generate(generateCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(reply)
+		if err != nil {
+			return nil, err
+		}
+		c.save(key, data, ttl)
+		return data, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return res.Err
+		}
+		if err := json.Unmarshal(res.Val.([]byte), out); err != nil {
+			return err
+		}
+		if leader {
+			recordAICacheStatus(ctx, AICacheMiss)
+		} else {
+			recordAICacheStatus(ctx, AICacheShared)
+		}
+		return nil
+	}
+}
+
+// save stores a reply; a failure only costs a future call.
+func (c *AICache) save(key string, data []byte, ttl time.Duration) {
+	if c.repo == nil {
+		return
+	}
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+	feature, _, _ := strings.Cut(key, ":")
+	now := time.Now()
+	err := c.repo.SaveEntry(&domain.AICacheEntry{
+		Key:       key,
+		Feature:   feature,
+		Value:     string(data),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		log.Printf("Failed to write AI cache: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// internal/service/apns_provider.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+	// Apple rejects provider tokens older than an hour and throttles ones
+	// refreshed more often than every 20 minutes
+	apnsTokenLifetime = 40 * time.Minute
+)
+
+type apnsProvider struct {
+	host   string
+	keyID  string
+	teamID string
+	topic  string
+	key    *ecdsa.PrivateKey
+	client *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenIssued time.Time
+}
+
+// NewAPNsProvider sends straight to Apple over HTTP/2 with token-based
+// authentication (a .p8 key from the developer account).
+func NewAPNsProvider(keyPEM []byte, keyID, teamID, topic string, sandbox bool) (PushProvider, error) {
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, errors.New("APNs needs APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM block found in APNs key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs key must be an EC private key")
+	}
+
+	host := apnsProductionHost
+	if sandbox {
+		host = apnsSandboxHost
+	}
+	return &apnsProvider{
+		host:   host,
+		keyID:  keyID,
+		teamID: teamID,
+		topic:  topic,
+		key:    key,
+		client: &http.Client{Timeout: pushSendTimeout},
+	}, nil
+}
+
+// providerToken returns the cached provider JWT, signing a new one when it is
+// about to expire or has just been rejected as expired.
+func (p *apnsProvider) providerToken(renew bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && !renew && time.Since(p.tokenIssued) < apnsTokenLifetime {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNs token: %w", err)
+	}
+	p.token = signed
+	p.tokenIssued = now
+	return signed, nil
+}
+
+func (p *apnsProvider) Send(ctx context.Context, token string, msg *PushMessage) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range pushData(msg) {
+		payload[key] = value
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	err = p.send(ctx, token, msg, body, false)
+	if errors.Is(err, errAPNsTokenExpired) {
+		err = p.send(ctx, token, msg, body, true)
+	}
+	return err
+}
+
+var errAPNsTokenExpired = errors.New("APNs provider token expired")
+
+func (p *apnsProvider) send(ctx context.Context, token string, msg *PushMessage, body []byte, renew bool) error {
+	auth, err := p.providerToken(renew)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.host+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+auth)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if msg.TTL > 0 {
+		req.Header.Set("apns-expiration", fmt.Sprint(time.Now().Add(msg.TTL).Unix()))
+	}
+	if msg.Category != "" {
+		req.Header.Set("apns-collapse-id", msg.Category)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(respBody, &apnsErr)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		apnsErr.Reason == "BadDeviceToken",
+		apnsErr.Reason == "DeviceTokenNotForTopic",
+		apnsErr.Reason == "Unregistered":
+		return ErrInvalidPushToken
+	case apnsErr.Reason == "ExpiredProviderToken":
+		return errAPNsTokenExpired
+	}
+
+	err = fmt.Errorf("APNs returned %d: %s", resp.StatusCode, apnsErr.Reason)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrPermanentPushFailure, err)
+}
@@ -0,0 +1,107 @@
+// internal/service/calendar_font.go
+package service
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// A 5x7 pixel font for the text in PNG calendars. It has capitals, digits
+// and common punctuation; lowercase letters are drawn as capitals and
+// anything else as "?".
+const (
+	pixelFontWidth   = 5
+	pixelFontHeight  = 7
+	pixelFontAdvance = pixelFontWidth + 1
+)
+
+// Rows from top to bottom, "#" for a set pixel
+var pixelFontGlyphs = map[rune]string{
+	'A':  ".###. #...# #...# ##### #...# #...# #...#",
+	'B':  "####. #...# #...# ####. #...# #...# ####.",
+	'C':  ".###. #...# #.... #.... #.... #...# .###.",
+	'D':  "####. #...# #...# #...# #...# #...# ####.",
+	'E':  "##### #.... #.... ####. #.... #.... #####",
+	'F':  "##### #.... #.... ####. #.... #.... #....",
+	'G':  ".###. #...# #.... #.### #...# #...# .####",
+	'H':  "#...# #...# #...# ##### #...# #...# #...#",
+	'I':  ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J':  "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K':  "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L':  "#.... #.... #.... #.... #.... #.... #####",
+	'M':  "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N':  "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O':  ".###. #...# #...# #...# #...# #...# .###.",
+	'P':  "####. #...# #...# ####. #.... #.... #....",
+	'Q':  ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R':  "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S':  ".#### #.... #.... .###. ....# ....# ####.",
+	'T':  "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U':  "#...# #...# #...# #...# #...# #...# .###.",
+	'V':  "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W':  "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X':  "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y':  "#...# #...# .#.#. ..#.. ..#.. ..#.. ..#..",
+	'Z':  "##### ....# ...#. ..#.. .#... #.... #####",
+	'0':  ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1':  "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2':  ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3':  "####. ....# ....# .###. ....# ....# ####.",
+	'4':  "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5':  "##### #.... ####. ....# ....# #...# .###.",
+	'6':  "..##. .#... #.... ####. #...# #...# .###.",
+	'7':  "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8':  ".###. #...# #...# .###. #...# #...# .###.",
+	'9':  ".###. #...# #...# .#### ....# ...#. .##..",
+	' ':  "..... ..... ..... ..... ..... ..... .....",
+	'@':  ".###. #...# #.### #.#.# #.### #.... .####",
+	'_':  "..... ..... ..... ..... ..... ..... #####",
+	'-':  "..... ..... ..... .###. ..... ..... .....",
+	',':  "..... ..... ..... ..... .##.. ..#.. .#...",
+	'.':  "..... ..... ..... ..... ..... .##.. .##..",
+	':':  "..... .##.. .##.. ..... .##.. .##.. .....",
+	'+':  "..... ..#.. ..#.. ##### ..#.. ..#.. .....",
+	'/':  "....# ....# ...#. ..#.. .#... #.... #....",
+	'(':  "...#. ..#.. .#... .#... .#... ..#.. ...#.",
+	')':  ".#... ..#.. ...#. ...#. ...#. ..#.. .#...",
+	'!':  "..#.. ..#.. ..#.. ..#.. ..#.. ..... ..#..",
+	'\'': "..#.. ..#.. .#... ..... ..... ..... .....",
+	'?':  ".###. #...# ....# ...#. ..#.. ..... ..#..",
+	'&':  ".##.. #..#. #.#.. .#... #.#.# #..#. .##.#",
+	'#':  ".#.#. .#.#. ##### .#.#. ##### .#.#. .#.#.",
+	'%':  "##..# ##.#. ...#. ..#.. .#... .#.## #..##",
+}
+
+// pixelFontScale is how many image pixels each font pixel takes in a PNG
+// for text of size px, so its capitals are about as tall as in the SVG.
+func pixelFontScale(size int) int {
+	return max(1, int(math.Round(float64(size*calendarPNGScale)*0.75/pixelFontHeight)))
+}
+
+// drawPixelText draws text with its baseline at y. Bold text is drawn twice,
+// one image pixel apart.
+func drawPixelText(img *image.RGBA, x, y, scale int, bold bool, fill color.RGBA, text string) {
+	top := y - pixelFontHeight*scale
+	for _, r := range text {
+		glyph, ok := pixelFontGlyphs[unicode.ToUpper(r)]
+		if !ok {
+			glyph = pixelFontGlyphs['?']
+		}
+		for row, bits := range strings.Fields(glyph) {
+			for col, bit := range bits {
+				if bit != '#' {
+					continue
+				}
+				pixel := image.Rect(x+col*scale, top+row*scale, x+(col+1)*scale, top+(row+1)*scale)
+				if bold {
+					pixel.Max.X++
+				}
+				fillRoundedRect(img, pixel.Intersect(img.Bounds()), 0, fill)
+			}
+		}
+		x += pixelFontAdvance * scale
+	}
+}
@@ -0,0 +1,285 @@
+// internal/service/calendar_image.go
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+
+	"lissanai.com/backend/internal/domain"
+)
+
+// Calendar images are laid out once and then drawn as SVG or PNG, so both
+// formats show the same graph.
+
+const (
+	calendarCell    = 11 // day squares, in px
+	calendarStep    = 14 // cell plus gap
+	calendarPadding = 16
+	calendarLabels  = 30 // width of the weekday labels
+	// Badge titles are listed on at most this many lines
+	calendarBadgeLines = 3
+	// PNGs are drawn at this multiple of the SVG size to stay sharp on
+	// high-density screens
+	calendarPNGScale = 2
+)
+
+var (
+	// Fill by activity level, from no activity to the busiest days
+	calendarLevelColors = [5]color.RGBA{
+		{0xeb, 0xed, 0xf0, 0xff},
+		{0x9b, 0xe9, 0xa8, 0xff},
+		{0x40, 0xc4, 0x63, 0xff},
+		{0x30, 0xa1, 0x4e, 0xff},
+		{0x21, 0x6e, 0x39, 0xff},
+	}
+	calendarFrozenColor     = color.RGBA{0xa5, 0xd8, 0xff, 0xff}
+	calendarBackgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	calendarBorderColor     = color.RGBA{0xd0, 0xd7, 0xde, 0xff}
+	calendarTextColor       = color.RGBA{0x24, 0x29, 0x2f, 0xff}
+	calendarMutedColor      = color.RGBA{0x57, 0x60, 0x6a, 0xff}
+)
+
+// CalendarCard is what a rendered calendar shows around the days.
+type CalendarCard struct {
+	Title  string   // e.g. "@john_doe"; the year when empty
+	Badges []string // titles of earned badges
+}
+
+type calendarShape struct {
+	x, y, w, h int
+	radius     int
+	fill       color.RGBA
+	stroke     *color.RGBA
+	tooltip    string // SVG only
+}
+
+// calendarText is a line of text; y is its baseline.
+type calendarText struct {
+	x, y int
+	size int // font size in px
+	bold bool
+	fill color.RGBA
+	text string
+}
+
+type calendarLayout struct {
+	width, height int
+	shapes        []calendarShape
+	texts         []calendarText
+}
+
+// activityLevel maps a day's activity count to one of the four shades,
+// relative to the busiest day.
+func activityLevel(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	level := (count*4 + busiest - 1) / busiest
+	return max(1, min(level, 4))
+}
+
+// textWidth is the width a line takes up. It is measured in the PNG font,
+// which is wider than the SVG's, so text laid out with it fits in both.
+func textWidth(text string, size int) int {
+	return len([]rune(text)) * pixelFontAdvance * pixelFontScale(size) / calendarPNGScale
+}
+
+func layoutCalendar(calendar *domain.ActivityCalendarResponse, card CalendarCard) *calendarLayout {
+	gridWidth := len(calendar.Weeks)*calendarStep - (calendarStep - calendarCell)
+	layout := &calendarLayout{width: 2*calendarPadding + calendarLabels + gridWidth}
+	left := calendarPadding + calendarLabels
+	y := calendarPadding
+
+	// The card itself; its height is filled in at the end
+	layout.shapes = append(layout.shapes, calendarShape{
+		radius: 6, fill: calendarBackgroundColor, stroke: &calendarBorderColor,
+	})
+
+	title := card.Title
+	if title == "" {
+		title = fmt.Sprintf("Activity in %d", calendar.Year)
+	}
+	layout.texts = append(layout.texts, calendarText{x: calendarPadding, y: y + 14, size: 16, bold: true, fill: calendarTextColor, text: title})
+	y += 26
+	stats := fmt.Sprintf("Current streak: %d days   Longest streak: %d days   Active days in %d: %d",
+		calendar.CurrentStreak, calendar.LongestStreak, calendar.Year, calendar.ActiveDays)
+	layout.texts = append(layout.texts, calendarText{x: calendarPadding, y: y + 11, size: 12, fill: calendarMutedColor, text: stats})
+	y += 26
+
+	// Month names above the column their first day is in
+	monthsY := y + 9
+	y += 16
+	lastLabel := -calendarLabels
+	busiest := calendar.Summary.MostActiveCount
+	for col, week := range calendar.Weeks {
+		for _, day := range week.Days {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				continue
+			}
+			x := left + col*calendarStep
+			// Rows run Monday to Sunday, like the weeks
+			row := (int(date.Weekday()) + 6) % 7
+
+			if date.Day() == 1 && x-lastLabel >= 3*calendarStep {
+				layout.texts = append(layout.texts, calendarText{x: x, y: monthsY, size: 10, fill: calendarMutedColor, text: date.Format("Jan")})
+				lastLabel = x
+			}
+
+			fill := calendarLevelColors[activityLevel(day.ActivityCount, busiest)]
+			tooltip := fmt.Sprintf("%s: %d activities", day.Date, day.ActivityCount)
+			if day.Frozen {
+				fill = calendarFrozenColor
+				tooltip = day.Date + ": streak freeze"
+			}
+			layout.shapes = append(layout.shapes, calendarShape{
+				x: x, y: y + row*calendarStep, w: calendarCell, h: calendarCell,
+				radius: 2, fill: fill, tooltip: tooltip,
+			})
+		}
+	}
+	for row, name := range []string{"Mon", "", "Wed", "", "Fri", "", ""} {
+		if name != "" {
+			layout.texts = append(layout.texts, calendarText{x: calendarPadding, y: y + row*calendarStep + 9, size: 9, fill: calendarMutedColor, text: name})
+		}
+	}
+	y += 7*calendarStep + 6
+
+	// Legend: Less [] [] [] [] [] More  [] Streak freeze
+	x := left
+	layout.texts = append(layout.texts, calendarText{x: x, y: y + 9, size: 9, fill: calendarMutedColor, text: "Less"})
+	x += textWidth("Less", 9) + 4
+	for _, fill := range calendarLevelColors {
+		layout.shapes = append(layout.shapes, calendarShape{x: x, y: y, w: calendarCell, h: calendarCell, radius: 2, fill: fill})
+		x += calendarStep
+	}
+	layout.texts = append(layout.texts, calendarText{x: x + 1, y: y + 9, size: 9, fill: calendarMutedColor, text: "More"})
+	x += textWidth("More", 9) + 16
+	layout.shapes = append(layout.shapes, calendarShape{x: x, y: y, w: calendarCell, h: calendarCell, radius: 2, fill: calendarFrozenColor})
+	layout.texts = append(layout.texts, calendarText{x: x + calendarStep + 1, y: y + 9, size: 9, fill: calendarMutedColor, text: "Streak freeze"})
+	y += calendarStep + 8
+
+	if len(card.Badges) > 0 {
+		for _, line := range badgeLines(card.Badges, layout.width-2*calendarPadding) {
+			layout.texts = append(layout.texts, calendarText{x: calendarPadding, y: y + 11, size: 12, fill: calendarTextColor, text: line})
+			y += 18
+		}
+	}
+
+	layout.height = y + calendarPadding - 4
+	layout.shapes[0].w, layout.shapes[0].h = layout.width, layout.height
+	return layout
+}
+
+// badgeLines lists badge titles on lines at most width wide, ending with
+// how many did not fit.
+func badgeLines(badges []string, width int) []string {
+	var lines []string
+	line := "Badges:"
+	for i, badge := range badges {
+		next := line + " " + badge
+		if i < len(badges)-1 {
+			next += ","
+		}
+		if textWidth(next, 12) <= width {
+			line = next
+			continue
+		}
+		if len(lines) == calendarBadgeLines-1 {
+			return append(lines, fmt.Sprintf("%s +%d more", strings.TrimSuffix(line, ","), len(badges)-i))
+		}
+		lines = append(lines, line)
+		line = badge
+		if i < len(badges)-1 {
+			line += ","
+		}
+	}
+	return append(lines, line)
+}
+
+// RenderCalendarSVG draws the calendar as an SVG image.
+func RenderCalendarSVG(calendar *domain.ActivityCalendarResponse, card CalendarCard) []byte {
+	layout := layoutCalendar(calendar, card)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system,BlinkMacSystemFont,'Segoe UI',Helvetica,Arial,sans-serif">`,
+		layout.width, layout.height, layout.width, layout.height)
+	for _, shape := range layout.shapes {
+		if shape.stroke != nil {
+			// Inset by half the 1px border so it is drawn inside the shape
+			fmt.Fprintf(&b, `<rect x="%d.5" y="%d.5" width="%d" height="%d" rx="%d" fill="%s" stroke="%s"/>`,
+				shape.x, shape.y, shape.w-1, shape.h-1, shape.radius, hexColor(shape.fill), hexColor(*shape.stroke))
+			continue
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"`,
+			shape.x, shape.y, shape.w, shape.h, shape.radius, hexColor(shape.fill))
+		if shape.tooltip == "" {
+			b.WriteString("/>")
+			continue
+		}
+		fmt.Fprintf(&b, `><title>%s</title></rect>`, html.EscapeString(shape.tooltip))
+	}
+	for _, text := range layout.texts {
+		weight := ""
+		if text.bold {
+			weight = ` font-weight="600"`
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="%d"%s fill="%s">%s</text>`,
+			text.x, text.y, text.size, weight, hexColor(text.fill), html.EscapeString(text.text))
+	}
+	b.WriteString("</svg>")
+	return b.Bytes()
+}
+
+// RenderCalendarPNG draws the calendar as a PNG image, without any
+// dependency on system fonts or an SVG renderer.
+func RenderCalendarPNG(calendar *domain.ActivityCalendarResponse, card CalendarCard) ([]byte, error) {
+	layout := layoutCalendar(calendar, card)
+	img := image.NewRGBA(image.Rect(0, 0, layout.width*calendarPNGScale, layout.height*calendarPNGScale))
+
+	for _, shape := range layout.shapes {
+		rect := image.Rect(shape.x, shape.y, shape.x+shape.w, shape.y+shape.h)
+		rect = image.Rect(rect.Min.X*calendarPNGScale, rect.Min.Y*calendarPNGScale, rect.Max.X*calendarPNGScale, rect.Max.Y*calendarPNGScale)
+		radius := shape.radius * calendarPNGScale
+		if shape.stroke != nil {
+			fillRoundedRect(img, rect, radius, *shape.stroke)
+			rect = rect.Inset(calendarPNGScale)
+			radius -= calendarPNGScale
+		}
+		fillRoundedRect(img, rect, radius, shape.fill)
+	}
+	for _, text := range layout.texts {
+		drawPixelText(img, text.x*calendarPNGScale, text.y*calendarPNGScale, pixelFontScale(text.size), text.bold, text.fill, text.text)
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// fillRoundedRect fills rect, leaving out the corners outside the radius.
+func fillRoundedRect(img *image.RGBA, rect image.Rectangle, radius int, fill color.RGBA) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			// Distance into the corner square the pixel is in, if any
+			dx := max(rect.Min.X+radius-x, x-(rect.Max.X-1-radius), 0)
+			dy := max(rect.Min.Y+radius-y, y-(rect.Max.Y-1-radius), 0)
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			img.SetRGBA(x, y, fill)
+		}
+	}
+}
@@ -2,93 +2,70 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 	"lissanai.com/backend/internal/domain/models"
+	"lissanai.com/backend/internal/llm"
 )
 
-// ChatAiService implements AiService interface
-type ChatAiService struct {
-	model *genai.GenerativeModel
-}
+// sessionSummaryAnswer is what the model is asked for when summarizing a session
+type sessionSummaryAnswer struct {
+	Strengths    []string `json:"strengths"`
+	Weaknesses   []string `json:"weaknesses"`
+	OverallScore int      `json:"overall_score" minimum:"0" maximum:"100"`
 
-// NewChatAiService creates a new Gemini AI service client
-func NewChatAiService(apiKey string) (*ChatAiService, error) {
-	ctx := context.Background()
+	// Scores the answers got, which the overall score must lie between
+	scores []int
+}
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini AI client: %w", err)
+func (a *sessionSummaryAnswer) Check() []string {
+	if len(a.scores) == 0 {
+		return nil
 	}
-
-	// Use gemini-1.5-flash for quick feedback tasks
-	model := client.GenerativeModel("gemini-1.5-flash")
-
-	return &ChatAiService{model: model}, nil
+	lowest, highest := slices.Min(a.scores), slices.Max(a.scores)
+	if a.OverallScore < lowest || a.OverallScore > highest {
+		return []string{fmt.Sprintf("overall_score: must be between the lowest and highest answer scores, %d and %d, not %d", lowest, highest, a.OverallScore)}
+	}
+	return nil
 }
 
-// GenerateFeedback analyzes a user's answer and returns structured feedback.
-func (cs *ChatAiService) GenerateFeedback(sessionID string, question string, answer string) (*models.Feedback, error) {
-	ctx := context.Background()
-
-	prompt := fmt.Sprintf(`
-You are an English tutor evaluating a student's interview response. 
-Analyze the answer and return structured JSON feedback. 
-Focus on grammar, clarity, fluency, and pronunciation.
-
-Question: %s
-Answer: %s
+// ChatAiService implements AiService interface
+type ChatAiService struct {
+	llm     llm.Client
+	prompts *PromptRegistry
+}
 
-Return strictly in this JSON format:
-{
-  "overall_summary": "string",
-  "feedback_points": [
-    {
-      "type": "grammar|pronunciation|structure",
-      "focus_phrase": "string",
-      "suggestion": "string"
-    }
-  ],
-  "score_percentage": number
+// NewChatAiService creates an interview feedback service on top of the LLM client
+func NewChatAiService(client llm.Client, prompts *PromptRegistry) *ChatAiService {
+	return &ChatAiService{llm: client, prompts: prompts}
 }
-`, question, answer)
 
-	resp, err := cs.model.GenerateContent(ctx, genai.Text(prompt))
+// GenerateFeedback analyzes a user's answer and returns structured feedback.
+func (cs *ChatAiService) GenerateFeedback(ctx context.Context, sessionID string, question string, answer string) (*models.Feedback, error) {
+	prompt, err := cs.prompts.Render(ctx, PromptInterviewFeedback, map[string]interface{}{"Question": question, "Answer": answer})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate feedback: %w", err)
+		return nil, err
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no valid feedback returned")
-	}
-
-	part := resp.Candidates[0].Content.Parts[0]
-	raw, ok := part.(genai.Text)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %#v", part)
-	}
-
-	jsonStr := cleanJSON(string(raw))
-
 	var feedback models.Feedback
-	if err := json.Unmarshal([]byte(jsonStr), &feedback); err != nil {
-		return nil, fmt.Errorf("failed to parse feedback JSON: %w\nRaw response: %s", err, jsonStr)
+	if _, err := llm.GenerateJSON(ctx, cs.llm, llm.FeatureInterviewFeedback, llm.Request{System: prompt.System, Prompt: prompt.Text}, &feedback); err != nil {
+		return nil, fmt.Errorf("failed to generate feedback: %w", err)
 	}
+	feedback.PromptVersion = prompt.Version
 
 	return &feedback, nil
 }
 
 // SummarizeSession produces an overall session summary
-func (cs *ChatAiService) SummarizeSession(session *models.Session, messages []models.Message) (*models.SessionSummary, error) {
-	ctx := context.Background()
-
+func (cs *ChatAiService) SummarizeSession(ctx context.Context, session *models.Session, messages []models.Message) (*models.SessionSummary, error) {
 	// Convert messages into a JSON-like string for AI context
 	msgStr := ""
+	var scores []int
 	for i, m := range messages {
 		if m.Feedback != nil {
+			scores = append(scores, m.Feedback.ScorePercent)
 			msgStr += fmt.Sprintf(
 				"Q%d: %s\nA: %s\nFeedback: %+v\n\n",
 				i+1, m.Question, m.Answer, m.Feedback,
@@ -101,42 +78,25 @@ func (cs *ChatAiService) SummarizeSession(session *models.Session, messages []mo
 		}
 	}
 
-	prompt := fmt.Sprintf(`
-You are an English tutor. Summarize the entire interview session.
-
-Here are the questions, answers, and feedback:
-%s
-
-Return strictly in this JSON format:
-{
-  "strengths": ["string", "string"],
-  "weaknesses": ["string", "string"],
-  "overall_score": number,
-  "recommendations": ["string", "string"]
-}
-`, msgStr)
-
-	resp, err := cs.model.GenerateContent(ctx, genai.Text(prompt))
+	prompt, err := cs.prompts.Render(ctx, PromptInterviewSummary, map[string]interface{}{"Transcript": msgStr})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate session summary: %w", err)
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no valid summary returned")
+		return nil, err
 	}
 
-	part := resp.Candidates[0].Content.Parts[0]
-	raw, ok := part.(genai.Text)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %#v", part)
+	answer := sessionSummaryAnswer{scores: scores}
+	if _, err := llm.GenerateJSON(ctx, cs.llm, llm.FeatureInterviewSummary, llm.Request{System: prompt.System, Prompt: prompt.Text}, &answer); err != nil {
+		return nil, fmt.Errorf("failed to generate session summary: %w", err)
 	}
 
-	jsonStr := cleanJSON(string(raw))
-
-	var summary models.SessionSummary
-	if err := json.Unmarshal([]byte(jsonStr), &summary); err != nil {
-		return nil, fmt.Errorf("failed to parse summary JSON: %w\nRaw response: %s", err, jsonStr)
+	summary := models.SessionSummary{
+		SessionID:      session.ID,
+		TotalQuestions: session.TotalQuestions,
+		Completed:      session.CompletedQuestions,
+		Strengths:      answer.Strengths,
+		Weaknesses:     answer.Weaknesses,
+		FinalScore:     answer.OverallScore,
+		CreatedAt:      time.Now().Unix(),
+		PromptVersion:  prompt.Version,
 	}
-
 	return &summary, nil
 }
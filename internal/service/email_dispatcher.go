@@ -0,0 +1,113 @@
+// internal/service/email_dispatcher.go
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+const (
+	// A message is given up after this many attempts (about an hour of retries)
+	maxEmailAttempts = 8
+	emailRetryBase   = 30 * time.Second
+	emailRetryMax    = time.Hour
+	// How long a claimed message is reserved for one send attempt
+	emailClaimLease = 2 * time.Minute
+)
+
+// EmailDispatcher delivers queued outbox messages through a transport,
+// retrying failures with exponential backoff. Messages are claimed one at a
+// time, so several server instances can dispatch the same outbox.
+type EmailDispatcher struct {
+	outbox    repository.EmailOutboxRepository
+	transport MailTransport
+	from      string
+	wake      chan struct{}
+}
+
+func NewEmailDispatcher(outbox repository.EmailOutboxRepository, transport MailTransport) *EmailDispatcher {
+	return &EmailDispatcher{
+		outbox:    outbox,
+		transport: transport,
+		from:      getEnvOrDefault("FROM_EMAIL", getEnvOrDefault("SMTP_USERNAME", "noreply@lissanai.com")),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Wake asks the dispatcher to look at the outbox now. It never blocks.
+func (d *EmailDispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Woken receives after Wake has been called.
+func (d *EmailDispatcher) Woken() <-chan struct{} {
+	return d.wake
+}
+
+// DeliverDue sends every message that is due and returns how many were sent.
+func (d *EmailDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	sent := 0
+	for ctx.Err() == nil {
+		email, err := d.outbox.ClaimNext(time.Now(), emailClaimLease)
+		if err != nil {
+			return sent, err
+		}
+		if email == nil {
+			return sent, nil
+		}
+		if d.deliver(ctx, email) {
+			sent++
+		}
+	}
+	return sent, ctx.Err()
+}
+
+func (d *EmailDispatcher) deliver(ctx context.Context, email *domain.OutboxEmail) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, emailClaimLease)
+	defer cancel()
+
+	err := d.transport.Send(sendCtx, &MailMessage{
+		From:    d.from,
+		To:      email.To,
+		Subject: email.Subject,
+		HTML:    email.HTMLBody,
+		Text:    email.TextBody,
+	})
+	if err == nil {
+		if err := d.outbox.MarkSent(email.ID); err != nil {
+			log.Printf("Failed to mark email %s as sent: %v", email.ID.Hex(), err)
+		}
+		return true
+	}
+
+	if IsPermanentMailError(err) || email.Attempts >= maxEmailAttempts {
+		log.Printf("Giving up on %s email %s after %d attempts: %v", email.Template, email.ID.Hex(), email.Attempts, err)
+		if err := d.outbox.MarkFailed(email.ID, err.Error()); err != nil {
+			log.Printf("Failed to mark email %s as failed: %v", email.ID.Hex(), err)
+		}
+		return false
+	}
+
+	next := time.Now().Add(outboxRetryDelay(email.Attempts, emailRetryBase, emailRetryMax))
+	log.Printf("Failed to send %s email %s (attempt %d), retrying at %s: %v", email.Template, email.ID.Hex(), email.Attempts, next.Format(time.RFC3339), err)
+	if err := d.outbox.MarkRetry(email.ID, err.Error(), next); err != nil {
+		log.Printf("Failed to reschedule email %s: %v", email.ID.Hex(), err)
+	}
+	return false
+}
+
+// outboxRetryDelay doubles the wait after every failed attempt, up to limit.
+func outboxRetryDelay(attempts int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
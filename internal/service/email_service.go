@@ -3,115 +3,92 @@ package service
 
 import (
 	"fmt"
-	"net/smtp"
 	"os"
+	"time"
+
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
 )
 
+// EmailService renders notification emails in the recipient's language and
+// queues them in the outbox. Delivery happens later in EmailDispatcher, so a
+// slow or unavailable mail server never blocks a request.
 type EmailService interface {
-	SendPasswordResetEmail(email, resetToken, userName string) error
+	// Send renders the named template for the user and queues it.
+	Send(user *domain.User, template string, data map[string]interface{}) error
+	SendPasswordResetEmail(user *domain.User, resetToken string) error
+	SendVerificationEmail(user *domain.User, verificationToken string) error
+	SendAccountLockedEmail(user *domain.User, lockedUntil time.Time) error
 }
 
 type emailService struct {
-	smtpHost     string
-	smtpPort     string
-	smtpUsername string
-	smtpPassword string
-	fromEmail    string
-	frontendURL  string
+	templates   *EmailTemplates
+	outbox      repository.EmailOutboxRepository
+	frontendURL string
+	queued      func()
 }
 
-func NewEmailService() EmailService {
+// NewEmailService queues into outbox. queued, if not nil, is called after
+// each message is queued so the dispatcher can send it without waiting for
+// its next poll.
+func NewEmailService(templates *EmailTemplates, outbox repository.EmailOutboxRepository, queued func()) EmailService {
 	return &emailService{
-		smtpHost:     getEnvOrDefault("SMTP_HOST", "smtp.gmail.com"),
-		smtpPort:     getEnvOrDefault("SMTP_PORT", "587"),
-		smtpUsername: os.Getenv("SMTP_USERNAME"),
-		smtpPassword: os.Getenv("SMTP_PASSWORD"),
-		fromEmail:    getEnvOrDefault("FROM_EMAIL", os.Getenv("SMTP_USERNAME")),
-		frontendURL:  getEnvOrDefault("FRONTEND_URL", "https://lissanai.onrender.com"),
+		templates:   templates,
+		outbox:      outbox,
+		frontendURL: getEnvOrDefault("FRONTEND_URL", "https://lissanai.onrender.com"),
+		queued:      queued,
 	}
 }
 
-func (s *emailService) SendPasswordResetEmail(email, resetToken, userName string) error {
-	// Skip email sending if SMTP credentials are not configured
-	if s.smtpUsername == "" || s.smtpPassword == "" {
-		fmt.Printf("📧 Password reset email would be sent to: %s\n", email)
-		fmt.Printf("🔗 Reset link: %s/reset-password?token=%s\n", s.frontendURL, resetToken)
-		return nil
+func (s *emailService) Send(user *domain.User, template string, data map[string]interface{}) error {
+	values := map[string]interface{}{"Name": user.Name}
+	for key, value := range data {
+		values[key] = value
 	}
 
-	resetURL := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, resetToken)
+	rendered, err := s.templates.Render(template, user.CurrentSettings().Language, values)
+	if err != nil {
+		return err
+	}
 
-	subject := "Reset Your LissanAI Password"
-	body := s.generatePasswordResetHTML(userName, resetURL)
+	err = s.outbox.Enqueue(&domain.OutboxEmail{
+		UserID:   user.ID,
+		To:       user.Email,
+		Template: template,
+		Locale:   rendered.Locale,
+		Subject:  rendered.Subject,
+		HTMLBody: rendered.HTML,
+		TextBody: rendered.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue %s email: %w", template, err)
+	}
 
-	return s.sendEmail(email, subject, body)
+	if s.queued != nil {
+		s.queued()
+	}
+	return nil
 }
 
-func (s *emailService) sendEmail(to, subject, body string) error {
-	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
-
-	msg := fmt.Sprintf("To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"MIME-Version: 1.0\r\n"+
-		"Content-Type: text/html; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s", to, subject, body)
+func (s *emailService) SendPasswordResetEmail(user *domain.User, resetToken string) error {
+	return s.Send(user, "password_reset", map[string]interface{}{
+		"ResetURL": fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, resetToken),
+	})
+}
 
-	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
-	return smtp.SendMail(addr, auth, s.fromEmail, []string{to}, []byte(msg))
+func (s *emailService) SendVerificationEmail(user *domain.User, verificationToken string) error {
+	return s.Send(user, "verify_email", map[string]interface{}{
+		"VerifyURL": fmt.Sprintf("%s/verify-email?token=%s", s.frontendURL, verificationToken),
+	})
 }
 
-func (s *emailService) generatePasswordResetHTML(userName, resetURL string) string {
-	return fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Reset Your Password</title>
-    <style>
-        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
-        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
-        .header { background: #4F46E5; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
-        .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 8px 8px; }
-        .button { display: inline-block; background: #4F46E5; color: white; padding: 12px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; }
-        .footer { text-align: center; margin-top: 30px; color: #666; font-size: 14px; }
-        .warning { background: #FEF3C7; border: 1px solid #F59E0B; padding: 15px; border-radius: 5px; margin: 20px 0; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <div class="header">
-            <h1>🔐 LissanAI Password Reset</h1>
-        </div>
-        <div class="content">
-            <h2>Hello %s,</h2>
-            <p>We received a request to reset your password for your LissanAI account.</p>
-            <p>Click the button below to reset your password:</p>
-            
-            <a href="%s" class="button">Reset My Password</a>
-            
-            <div class="warning">
-                <strong>⚠️ Important:</strong>
-                <ul>
-                    <li>This link will expire in 1 hour for security reasons</li>
-                    <li>If you didn't request this reset, please ignore this email</li>
-                    <li>Never share this link with anyone</li>
-                </ul>
-            </div>
-            
-            <p>If the button doesn't work, copy and paste this link into your browser:</p>
-            <p style="word-break: break-all; background: #e5e5e5; padding: 10px; border-radius: 3px;">%s</p>
-            
-            <p>Best regards,<br>The LissanAI Team</p>
-        </div>
-        <div class="footer">
-            <p>This is an automated message. Please do not reply to this email.</p>
-            <p>© 2025 LissanAI. All rights reserved.</p>
-        </div>
-    </div>
-</body>
-</html>`, userName, resetURL, resetURL)
+func (s *emailService) SendAccountLockedEmail(user *domain.User, lockedUntil time.Time) error {
+	// Shown in the user's own time zone
+	until := lockedUntil.In(user.CurrentSettings().Location())
+	return s.Send(user, "account_locked", map[string]interface{}{
+		"LockedUntil": until.Format("Jan 2, 2006 at 15:04 MST"),
+		"ResetURL":    fmt.Sprintf("%s/forgot-password", s.frontendURL),
+	})
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -0,0 +1,141 @@
+// internal/service/email_templates.go
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Email templates live in templates/email/<locale>/. Every email has a
+// <name>.html file defining "title", "heading" and "content" (wrapped in
+// layout.html, with the locale's common.html) and a <name>.txt file defining
+// "subject" and "text", the plain-text alternative.
+//
+//go:embed templates/email
+var emailTemplateFiles embed.FS
+
+const (
+	emailTemplateRoot   = "templates/email"
+	defaultEmailLocale  = "en"
+	emailTemplateLayout = "layout"
+)
+
+// RenderedEmail is an email ready to be sent.
+type RenderedEmail struct {
+	Locale  string // the locale actually used
+	Subject string
+	HTML    string
+	Text    string
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// EmailTemplates holds every parsed template, keyed by locale and then name.
+type EmailTemplates struct {
+	locales map[string]map[string]*emailTemplate
+}
+
+// LoadEmailTemplates parses the embedded templates. Every English template
+// must have both variants; other locales may leave templates untranslated.
+func LoadEmailTemplates() (*EmailTemplates, error) {
+	templates := &EmailTemplates{locales: make(map[string]map[string]*emailTemplate)}
+
+	entries, err := fs.ReadDir(emailTemplateFiles, emailTemplateRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		parsed, err := parseEmailLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		templates.locales[locale] = parsed
+	}
+
+	if _, ok := templates.locales[defaultEmailLocale]; !ok {
+		return nil, fmt.Errorf("no %q email templates found", defaultEmailLocale)
+	}
+	return templates, nil
+}
+
+func parseEmailLocale(locale string) (map[string]*emailTemplate, error) {
+	dir := path.Join(emailTemplateRoot, locale)
+	files, err := fs.ReadDir(emailTemplateFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]*emailTemplate)
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".html")
+		if !ok || name == "common" {
+			continue
+		}
+
+		html, err := htmltemplate.New(name).Option("missingkey=error").ParseFS(emailTemplateFiles,
+			path.Join(emailTemplateRoot, "layout.html"),
+			path.Join(dir, "common.html"),
+			path.Join(dir, file.Name()),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s/%s.html: %w", locale, name, err)
+		}
+		text, err := texttemplate.New(name).Option("missingkey=error").ParseFS(emailTemplateFiles, path.Join(dir, name+".txt"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s/%s.txt: %w", locale, name, err)
+		}
+		parsed[name] = &emailTemplate{html: html, text: text}
+	}
+	return parsed, nil
+}
+
+// Render executes the named template in the given locale, falling back to
+// English when it has not been translated. data is available to the templates
+// alongside Lang and Year.
+func (t *EmailTemplates) Render(name, locale string, data map[string]interface{}) (*RenderedEmail, error) {
+	tmpl, ok := t.locales[locale][name]
+	if !ok {
+		locale = defaultEmailLocale
+		tmpl, ok = t.locales[locale][name]
+		if !ok {
+			return nil, fmt.Errorf("unknown email template %q", name)
+		}
+	}
+
+	values := map[string]interface{}{"Lang": locale, "Year": time.Now().Year()}
+	for key, value := range data {
+		values[key] = value
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", values); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, emailTemplateLayout, values); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", name, err)
+	}
+
+	return &RenderedEmail{
+		Locale:  locale,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    strings.TrimLeft(text.String(), "\n"),
+	}, nil
+}
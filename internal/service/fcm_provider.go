@@ -0,0 +1,160 @@
+// internal/service/fcm_provider.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmScope        = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL      = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	pushSendTimeout = 10 * time.Second
+)
+
+type fcmProvider struct {
+	client  *http.Client
+	sendURL string
+}
+
+// NewFCMProvider sends through the FCM HTTP v1 API, authenticating with a
+// Firebase service account key.
+func NewFCMProvider(serviceAccountJSON []byte) (PushProvider, error) {
+	var account struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(serviceAccountJSON, &account); err != nil || account.ProjectID == "" {
+		return nil, errors.New("invalid FCM service account: project_id missing")
+	}
+	config, err := google.JWTConfigFromJSON(serviceAccountJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM service account: %w", err)
+	}
+
+	client := config.Client(context.Background())
+	client.Timeout = pushSendTimeout
+	return &fcmProvider{
+		client:  client,
+		sendURL: fmt.Sprintf(fcmSendURL, account.ProjectID),
+	}, nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *fcmAndroid       `json:"android,omitempty"`
+	APNs         *fcmAPNs          `json:"apns,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmAndroid struct {
+	TTL         string `json:"ttl,omitempty"`
+	CollapseKey string `json:"collapse_key,omitempty"`
+}
+
+type fcmAPNs struct {
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode       string `json:"errorCode"`
+			FieldViolations []struct {
+				Field string `json:"field"`
+			} `json:"fieldViolations"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (p *fcmProvider) Send(ctx context.Context, token string, msg *PushMessage) error {
+	message := fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         pushData(msg),
+	}
+	if msg.TTL > 0 {
+		message.Android = &fcmAndroid{TTL: fmt.Sprintf("%ds", int(msg.TTL.Seconds())), CollapseKey: msg.Category}
+		message.APNs = &fcmAPNs{Headers: map[string]string{
+			"apns-expiration": fmt.Sprint(time.Now().Add(msg.TTL).Unix()),
+		}}
+	}
+
+	body, err := json.Marshal(fcmRequest{Message: message})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(respBody, &fcmErr)
+	err = fmt.Errorf("FCM returned %d %s: %s", resp.StatusCode, fcmErr.Error.Status, fcmErr.Error.Message)
+
+	// Only errors about the token itself remove it. INVALID_ARGUMENT also
+	// covers problems with the message, such as its size, which must not
+	// cost every recipient their token.
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %v", ErrInvalidPushToken, err)
+		}
+		if fcmErr.Error.Status == "INVALID_ARGUMENT" {
+			for _, violation := range detail.FieldViolations {
+				if violation.Field == "message.token" {
+					return fmt.Errorf("%w: %v", ErrInvalidPushToken, err)
+				}
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrPermanentPushFailure, err)
+}
+
+// pushData is the data payload: the caller's values plus the category, so
+// the app knows which screen to open.
+func pushData(msg *PushMessage) map[string]string {
+	data := make(map[string]string, len(msg.Data)+1)
+	for key, value := range msg.Data {
+		data[key] = value
+	}
+	if msg.Category != "" {
+		data["category"] = msg.Category
+	}
+	return data
+}
@@ -0,0 +1,218 @@
+// internal/service/friend_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// FriendService manages friendships, which scope the friends leaderboard. A
+// request becomes a friendship once the other user accepts it.
+type FriendService struct {
+	userCollection       *mongo.Collection
+	friendshipCollection *mongo.Collection
+}
+
+func NewFriendService(db *mongo.Database) *FriendService {
+	friendshipCollection := db.Collection("friendships")
+	_, err := friendshipCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One friendship or request per pair of users, whoever asked first
+			Keys:    bson.D{{Key: "pair", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "requester_id", Value: 1}}},
+		{Keys: bson.D{{Key: "addressee_id", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Failed to create friendships indexes: %v", err)
+	}
+
+	return &FriendService{
+		userCollection:       db.Collection("users"),
+		friendshipCollection: friendshipCollection,
+	}
+}
+
+func friendshipPair(a, b primitive.ObjectID) string {
+	if a.Hex() > b.Hex() {
+		a, b = b, a
+	}
+	return a.Hex() + ":" + b.Hex()
+}
+
+func involving(userID primitive.ObjectID) bson.M {
+	return bson.M{"$or": []bson.M{{"requester_id": userID}, {"addressee_id": userID}}}
+}
+
+// SendRequest asks the user with email to be friends. If they already asked
+// the user, their request is accepted instead.
+func (s *FriendService) SendRequest(ctx context.Context, userID primitive.ObjectID, email string) (*domain.Friendship, error) {
+	var friend domain.User
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+	err := s.userCollection.FindOne(ctx, bson.M{"email": strings.TrimSpace(email)}, opts).Decode(&friend)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if friend.ID == userID {
+		return nil, fmt.Errorf("cannot add yourself as a friend")
+	}
+
+	friendship := domain.Friendship{
+		ID:          primitive.NewObjectID(),
+		RequesterID: userID,
+		AddresseeID: friend.ID,
+		Pair:        friendshipPair(userID, friend.ID),
+		Status:      domain.FriendshipPending,
+		CreatedAt:   time.Now(),
+	}
+	_, err = s.friendshipCollection.InsertOne(ctx, friendship)
+	if err == nil {
+		return &friendship, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("failed to send friend request: %w", err)
+	}
+
+	var existing domain.Friendship
+	if err := s.friendshipCollection.FindOne(ctx, bson.M{"pair": friendship.Pair}).Decode(&existing); err != nil {
+		return nil, fmt.Errorf("failed to send friend request: %w", err)
+	}
+	switch {
+	case existing.Status == domain.FriendshipAccepted:
+		return nil, fmt.Errorf("already friends")
+	case existing.RequesterID == userID:
+		return nil, fmt.Errorf("friend request already sent")
+	default:
+		return s.Accept(ctx, userID, existing.ID)
+	}
+}
+
+// Accept accepts a request sent to the user.
+func (s *FriendService) Accept(ctx context.Context, userID, friendshipID primitive.ObjectID) (*domain.Friendship, error) {
+	var friendship domain.Friendship
+	err := s.friendshipCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": friendshipID, "addressee_id": userID, "status": domain.FriendshipPending},
+		bson.M{"$set": bson.M{"status": domain.FriendshipAccepted, "accepted_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&friendship)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("friend request not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept friend request: %w", err)
+	}
+	return &friendship, nil
+}
+
+// Remove ends a friendship, or declines or withdraws a request.
+func (s *FriendService) Remove(ctx context.Context, userID, friendshipID primitive.ObjectID) error {
+	filter := involving(userID)
+	filter["_id"] = friendshipID
+	result, err := s.friendshipCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to remove friend: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("friendship not found")
+	}
+	return nil
+}
+
+// ListFriends returns the user's friends and open requests.
+func (s *FriendService) ListFriends(ctx context.Context, userID primitive.ObjectID) (*domain.FriendsResponse, error) {
+	cursor, err := s.friendshipCollection.Find(ctx, involving(userID), options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+	var friendships []domain.Friendship
+	if err := cursor.All(ctx, &friendships); err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+
+	otherIDs := make([]primitive.ObjectID, 0, len(friendships))
+	for _, friendship := range friendships {
+		otherIDs = append(otherIDs, otherUser(&friendship, userID))
+	}
+	names := make(map[primitive.ObjectID]string, len(otherIDs))
+	if len(otherIDs) > 0 {
+		cursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": otherIDs}},
+			options.Find().SetProjection(bson.M{"name": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find friends: %w", err)
+		}
+		var users []domain.User
+		if err := cursor.All(ctx, &users); err != nil {
+			return nil, fmt.Errorf("failed to find friends: %w", err)
+		}
+		for _, user := range users {
+			names[user.ID] = user.Name
+		}
+	}
+
+	response := &domain.FriendsResponse{
+		Friends:  []domain.Friend{},
+		Incoming: []domain.Friend{},
+		Outgoing: []domain.Friend{},
+	}
+	for _, friendship := range friendships {
+		otherID := otherUser(&friendship, userID)
+		friend := domain.Friend{
+			FriendshipID: friendship.ID.Hex(),
+			UserID:       otherID.Hex(),
+			Name:         names[otherID],
+			Since:        friendship.CreatedAt,
+		}
+		switch {
+		case friendship.Status == domain.FriendshipAccepted:
+			if friendship.AcceptedAt != nil {
+				friend.Since = *friendship.AcceptedAt
+			}
+			response.Friends = append(response.Friends, friend)
+		case friendship.AddresseeID == userID:
+			response.Incoming = append(response.Incoming, friend)
+		default:
+			response.Outgoing = append(response.Outgoing, friend)
+		}
+	}
+	return response, nil
+}
+
+// FriendIDs returns the IDs of the user's accepted friends.
+func (s *FriendService) FriendIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := involving(userID)
+	filter["status"] = domain.FriendshipAccepted
+	cursor, err := s.friendshipCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+	var friendships []domain.Friendship
+	if err := cursor.All(ctx, &friendships); err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(friendships))
+	for _, friendship := range friendships {
+		ids = append(ids, otherUser(&friendship, userID))
+	}
+	return ids, nil
+}
+
+func otherUser(friendship *domain.Friendship, userID primitive.ObjectID) primitive.ObjectID {
+	if friendship.RequesterID == userID {
+		return friendship.AddresseeID
+	}
+	return friendship.RequesterID
+}
@@ -2,102 +2,47 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
-	"strings"
 
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 	"lissanai.com/backend/internal/domain/models"
+	"lissanai.com/backend/internal/llm"
 )
 
 type AiService struct {
-	model *genai.GenerativeModel
+	llm     llm.Client
+	prompts *PromptRegistry
+	cache   *AICache
 }
 
-// NewAiService creates a new Gemini AI service client
-func NewAiService() (*AiService, error) {
-	ctx := context.Background()
-
-	apiKey := os.Getenv("GEMINI_API_KEY") // <-- READ FROM ENVIRONMENT
-	if apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
-	}
-
-	// Use the apiKey variable here
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini AI client: %w", err)
-	}
-
-	model := client.GenerativeModel("gemini-1.5-flash")
-	return &AiService{model: model}, nil
-}
-
-// cleanJSON strips any ```json or ``` wrappers from the AI output
-func cleanJSON(raw string) string {
-	raw = strings.TrimSpace(raw)
-	raw = strings.TrimPrefix(raw, "```json")
-	raw = strings.TrimPrefix(raw, "```")
-	raw = strings.TrimSuffix(raw, "```")
-	return strings.TrimSpace(raw)
-}
-
-// CheckGrammar sends text to Gemini AI and returns structured grammar corrections
-func (as *AiService) CheckGrammar(text string) (*models.GrammarResponse, error) {
-	ctx := context.Background()
-
-	prompt := fmt.Sprintf(`
-You are a grammar correction assistant.
-Correct the grammar and spelling of the following text.
-You must provide explanations in both English and Amharic for each correction.
-Return the result strictly in JSON format with the following structure:
-{
-  "corrected_text": "string",
-  "corrections": [
-    {
-      "original_phrase": "string",
-      "corrected_phrase": "string",
-      "explanation": {
-		"english": "string",
-		"amharic": "string"
-	  }
-    }
-  ]
+// NewAiService creates a grammar service on top of the LLM client
+func NewAiService(client llm.Client, prompts *PromptRegistry, cache *AICache) *AiService {
+	return &AiService{llm: client, prompts: prompts, cache: cache}
 }
 
-Text: %s
-`, text)
-
-	// Send request to Gemini
-	resp, err := as.model.GenerateContent(ctx, genai.Text(prompt))
+// CheckGrammar sends text to the grammar model and returns structured grammar corrections.
+// The same text is only checked once per prompt version while it is cached.
+func (as *AiService) CheckGrammar(ctx context.Context, text string) (*models.GrammarResponse, error) {
+	prompt, err := as.prompts.Render(ctx, PromptGrammar, map[string]interface{}{"Text": text})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
-	}
-
-	log.Printf("Raw AI response: %+v\n", resp)
-
-	// Validate response
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no valid content returned")
+		return nil, err
 	}
 
-	// Extract the first text part
-	part := resp.Candidates[0].Content.Parts[0]
-	raw, ok := part.(genai.Text)
-	if !ok {
-		return nil, fmt.Errorf("unexpected response type: %#v", part)
-	}
-
-	// Clean JSON string
-	jsonStr := cleanJSON(string(raw))
-
-	// Unmarshal into GrammarResponse struct
 	var grammarResp models.GrammarResponse
-	if err := json.Unmarshal([]byte(jsonStr), &grammarResp); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w\nRaw response: %s", err, jsonStr)
+	key := as.cache.Key(llm.FeatureGrammar, prompt.Version, text)
+	err = as.cache.Fetch(ctx, key, 0, &grammarResp, func(ctx context.Context) (interface{}, error) {
+		// The reply is checked against GrammarResponse and asked for again if it does not fit
+		var reply models.GrammarResponse
+		resp, err := llm.GenerateJSON(ctx, as.llm, llm.FeatureGrammar, llm.Request{System: prompt.System, Prompt: prompt.Text}, &reply)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Grammar checked by %s:%s with %s", resp.Provider, resp.Model, prompt.Version)
+		reply.PromptVersion = prompt.Version
+		return &reply, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
 	return &grammarResp, nil
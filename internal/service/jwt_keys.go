@@ -0,0 +1,327 @@
+// internal/service/jwt_keys.go
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+// Placeholder secrets shipped in example configs; never accepted outside development.
+var placeholderJWTSecrets = []string{
+	"your-secret-key-change-this-in-production",
+	"your-super-secret-jwt-key-change-this-in-production",
+}
+
+// SigningKey is a key tokens can be verified with and, if it holds the
+// private half, signed with. HMAC keys have no kid so tokens issued before
+// key rotation keep verifying.
+type SigningKey struct {
+	ID       string
+	Method   jwt.SigningMethod
+	signer   interface{} // private key or HMAC secret; nil for verify-only keys
+	verifier interface{} // public key or HMAC secret
+}
+
+// NewHMACKey wraps a shared HS256 secret.
+func NewHMACKey(secret string) *SigningKey {
+	return &SigningKey{
+		Method:   jwt.SigningMethodHS256,
+		signer:   []byte(secret),
+		verifier: []byte(secret),
+	}
+}
+
+// NewEd25519Key generates a fresh EdDSA signing key.
+func NewEd25519Key() (*SigningKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+	}
+	return newAsymmetricKey(private, public)
+}
+
+// ParseSigningKeyPEM reads an RSA (PKCS#1 or PKCS#8) or Ed25519 (PKCS#8)
+// private key. RSA keys sign with RS256, Ed25519 keys with EdDSA.
+func ParseSigningKeyPEM(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in signing key")
+	}
+	private, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		return newAsymmetricKey(key, &key.PublicKey)
+	case ed25519.PrivateKey:
+		return newAsymmetricKey(key, key.Public())
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", private)
+	}
+}
+
+// ParseVerificationKeysPEM reads every public or private key in a PEM bundle
+// as a verify-only key, e.g. the keys retired by the last rotations.
+func ParseVerificationKeysPEM(data []byte) ([]*SigningKey, error) {
+	var keys []*SigningKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var public interface{}
+		if block.Type == "PUBLIC KEY" {
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key: %w", err)
+			}
+			public = parsed
+		} else {
+			private, err := parsePrivateKey(block)
+			if err != nil {
+				return nil, err
+			}
+			switch key := private.(type) {
+			case *rsa.PrivateKey:
+				public = &key.PublicKey
+			case ed25519.PrivateKey:
+				public = key.Public()
+			}
+		}
+
+		key, err := newAsymmetricKey(nil, public)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM keys found")
+	}
+	return keys, nil
+}
+
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA private key: %w", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// newAsymmetricKey builds a key whose kid is its RFC 7638 thumbprint, so the
+// same key always gets the same kid on every instance without configuration.
+func newAsymmetricKey(private, public interface{}) (*SigningKey, error) {
+	key := &SigningKey{signer: private, verifier: public}
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", public)
+	}
+
+	jwk := key.JWK()
+	var canonical string
+	if jwk.Kty == "RSA" {
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	} else {
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	key.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return key, nil
+}
+
+// JWK returns the public half of an asymmetric key; HMAC keys have none.
+func (k *SigningKey) JWK() JWK {
+	switch pub := k.verifier.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}
+	default:
+		return JWK{}
+	}
+}
+
+// KeySet is the key new tokens are signed with plus every key still accepted
+// for verification. Rotating means signing with a new key while the old one
+// stays in the set until the tokens it signed have expired.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+func NewKeySet(active *SigningKey, verifyOnly ...*SigningKey) (*KeySet, error) {
+	if active == nil || active.signer == nil {
+		return nil, errors.New("active JWT key must be able to sign")
+	}
+	set := &KeySet{active: active, keys: map[string]*SigningKey{active.ID: active}}
+	for _, key := range verifyOnly {
+		if _, ok := set.keys[key.ID]; ok {
+			continue // the active key may also appear in the verification bundle
+		}
+		set.keys[key.ID] = key
+	}
+	return set, nil
+}
+
+// LoadKeySetFromEnv builds the key set from configuration:
+//
+//	JWT_SIGNING_KEY_FILE / JWT_SIGNING_KEY  PEM private key (RSA or Ed25519) that signs new tokens
+//	JWT_VERIFICATION_KEYS_FILE              comma separated PEM bundles of previous keys still accepted
+//	JWT_SECRET                              legacy HS256 secret; signs only when no private key is set
+//
+// Outside development mode it refuses to run without a key rather than fall
+// back to a guessable secret. In development an ephemeral Ed25519 key is used.
+func LoadKeySetFromEnv(devMode bool) (*KeySet, error) {
+	var active *SigningKey
+	var verifyOnly []*SigningKey
+
+	pemData := []byte(strings.ReplaceAll(os.Getenv("JWT_SIGNING_KEY"), `\n`, "\n"))
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT signing key: %w", err)
+		}
+		pemData = data
+	}
+	if len(strings.TrimSpace(string(pemData))) > 0 {
+		key, err := ParseSigningKeyPEM(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT signing key: %w", err)
+		}
+		active = key
+	}
+
+	for _, path := range splitEnvList("JWT_VERIFICATION_KEYS_FILE") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT verification keys: %w", err)
+		}
+		keys, err := ParseVerificationKeysPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT verification keys in %s: %w", path, err)
+		}
+		verifyOnly = append(verifyOnly, keys...)
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		if !devMode && contains(placeholderJWTSecrets, secret) {
+			return nil, errors.New("JWT_SECRET is still set to the example value")
+		}
+		if active == nil {
+			active = NewHMACKey(secret)
+		} else {
+			// Keep accepting HS256 tokens issued before the switch to asymmetric keys
+			verifyOnly = append(verifyOnly, NewHMACKey(secret))
+		}
+	}
+
+	if active == nil {
+		if !devMode {
+			return nil, errors.New("no JWT signing key configured: set JWT_SIGNING_KEY_FILE (or JWT_SECRET)")
+		}
+		key, err := NewEd25519Key()
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Warning: no JWT signing key configured, using an ephemeral development key. Tokens will not survive a restart.")
+		active = key
+	}
+
+	return NewKeySet(active, verifyOnly...)
+}
+
+// JWKS returns the public keys for the /.well-known/jwks.json document.
+// HMAC secrets are never published.
+func (s *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	// Active key first so consumers that pick the first key get the current one
+	if jwk := s.active.JWK(); jwk.Kty != "" {
+		set.Keys = append(set.Keys, jwk)
+	}
+	var previous []JWK
+	for _, key := range s.keys {
+		if key == s.active {
+			continue
+		}
+		if jwk := key.JWK(); jwk.Kty != "" {
+			previous = append(previous, jwk)
+		}
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i].Kid < previous[j].Kid })
+	set.Keys = append(set.Keys, previous...)
+	return set
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.Method, claims)
+	if s.active.ID != "" {
+		token.Header["kid"] = s.active.ID
+	}
+	return token.SignedString(s.active.signer)
+}
+
+// keyFunc picks the verification key by kid and checks the token's alg
+// matches it, so an RS256 public key can never be used as an HMAC secret.
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.verifier, nil
+}
@@ -6,39 +6,71 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type JWTService interface {
-	GenerateAccessToken(userID primitive.ObjectID) (string, error)
+	GenerateAccessToken(userID primitive.ObjectID, sessionID string, roles []string) (string, error)
+	AccessTokenTTL() time.Duration
 	GenerateRefreshToken() (string, error)
 	ValidateAccessToken(tokenString string) (*jwt.Token, error)
 	ExtractUserID(token *jwt.Token) (primitive.ObjectID, error)
+	ExtractSessionID(token *jwt.Token) (string, error)
+	ExtractRoles(token *jwt.Token) ([]string, error)
+	GenerateMFAToken(userID primitive.ObjectID) (string, error)
+	ValidateMFAToken(tokenString string) (primitive.ObjectID, error)
+	MFATokenTTL() time.Duration
+	// PublicKeys returns the JWKS other services use to verify our tokens.
+	PublicKeys() JWKSet
 }
 
 type jwtService struct {
-	secretKey       string
+	keys            *KeySet
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
+	mfaTokenTTL     time.Duration
 }
 
+// Every token we sign says what kind it is in its "typ" claim. The keys are
+// published, so services verifying our tokens must accept only "access":
+// the others share the keys but never grant API access.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenTypeMFA     = "mfa" // only proves the password step of a two-factor login
+)
+
 type Claims struct {
-	UserID primitive.ObjectID `json:"user_id"`
+	Type      string             `json:"typ"`
+	UserID    primitive.ObjectID `json:"user_id"`
+	SessionID string             `json:"sid,omitempty"`
+	Roles     []string           `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// refreshClaims are those of refresh tokens, which name no user: they are
+// looked up in the database.
+type refreshClaims struct {
+	Type string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-func NewJWTService(secretKey string) JWTService {
+func NewJWTService(keys *KeySet) JWTService {
 	return &jwtService{
-		secretKey:       secretKey,
+		keys:            keys,
 		accessTokenTTL:  7 * 24 * time.Hour,  // 7 days
 		refreshTokenTTL: 14 * 24 * time.Hour, // 14 days
-
+		mfaTokenTTL:     5 * time.Minute,
 	}
 }
 
-func (s *jwtService) GenerateAccessToken(userID primitive.ObjectID) (string, error) {
+func (s *jwtService) GenerateAccessToken(userID primitive.ObjectID, sessionID string, roles []string) (string, error) {
 	claims := Claims{
-		UserID: userID,
+		Type:      TokenTypeAccess,
+		UserID:    userID,
+		SessionID: sessionID,
+		Roles:     roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -46,27 +78,78 @@ func (s *jwtService) GenerateAccessToken(userID primitive.ObjectID) (string, err
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
+	return s.keys.sign(claims)
 }
 
 func (s *jwtService) GenerateRefreshToken() (string, error) {
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshTokenTTL)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	claims := refreshClaims{
+		Type: TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			// Unique ID so tokens minted in the same second never collide during rotation
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
+	return s.keys.sign(claims)
 }
 
 func (s *jwtService) ValidateAccessToken(tokenString string) (*jwt.Token, error) {
-	return jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return []byte(s.secretKey), nil
-	})
+	token, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	// Refresh and mfa tokens never grant API access
+	if claims, ok := token.Claims.(*Claims); !ok || claims.Type != TokenTypeAccess {
+		return nil, errors.New("invalid token type")
+	}
+	return token, nil
+}
+
+// GenerateMFAToken issues the short-lived token returned by a login that still
+// needs its second factor.
+func (s *jwtService) GenerateMFAToken(userID primitive.ObjectID) (string, error) {
+	claims := Claims{
+		Type:   TokenTypeMFA,
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.mfaTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   userID.Hex(),
+		},
+	}
+
+	return s.keys.sign(claims)
+}
+
+func (s *jwtService) ValidateMFAToken(tokenString string) (primitive.ObjectID, error) {
+	token, err := s.parse(tokenString)
+	if err != nil || !token.Valid {
+		return primitive.NilObjectID, errors.New("invalid or expired mfa token")
+	}
+	claims, ok := token.Claims.(*Claims)
+	if !ok || claims.Type != TokenTypeMFA {
+		return primitive.NilObjectID, errors.New("invalid or expired mfa token")
+	}
+	return claims.UserID, nil
+}
+
+func (s *jwtService) AccessTokenTTL() time.Duration {
+	return s.accessTokenTTL
+}
+
+func (s *jwtService) MFATokenTTL() time.Duration {
+	return s.mfaTokenTTL
+}
+
+func (s *jwtService) parse(tokenString string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, &Claims{}, s.keys.keyFunc)
+}
+
+func (s *jwtService) PublicKeys() JWKSet {
+	return s.keys.JWKS()
 }
 
 func (s *jwtService) ExtractUserID(token *jwt.Token) (primitive.ObjectID, error) {
@@ -76,3 +159,19 @@ func (s *jwtService) ExtractUserID(token *jwt.Token) (primitive.ObjectID, error)
 	}
 	return claims.UserID, nil
 }
+
+func (s *jwtService) ExtractSessionID(token *jwt.Token) (string, error) {
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return "", errors.New("invalid token claims")
+	}
+	return claims.SessionID, nil
+}
+
+func (s *jwtService) ExtractRoles(token *jwt.Token) ([]string, error) {
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims.Roles, nil
+}
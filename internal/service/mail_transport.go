@@ -0,0 +1,316 @@
+// internal/service/mail_transport.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MailMessage is one email handed to a transport.
+type MailMessage struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// MailTransport delivers a single message.
+type MailTransport interface {
+	Send(ctx context.Context, msg *MailMessage) error
+}
+
+// ErrPermanentMailFailure marks errors that retrying cannot fix, such as a
+// rejected recipient.
+var ErrPermanentMailFailure = errors.New("permanent mail failure")
+
+// IsPermanentMailError reports whether a send should not be retried. SMTP
+// 5xx replies are permanent; everything else (timeouts, 4xx) is retried.
+func IsPermanentMailError(err error) bool {
+	if errors.Is(err, ErrPermanentMailFailure) {
+		return true
+	}
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500
+}
+
+// NewMailTransportFromEnv picks the transport named by MAIL_TRANSPORT:
+//
+//	smtp    deliver through SMTP_HOST:SMTP_PORT
+//	file    write each message as an .eml file to MAIL_FILE_DIR
+//	log     print messages to the server log
+//	memory  keep messages in process memory
+//
+// Without MAIL_TRANSPORT, SMTP is used when credentials are configured and
+// the log sink otherwise, so development needs no mail server.
+func NewMailTransportFromEnv() (MailTransport, error) {
+	transport := strings.ToLower(os.Getenv("MAIL_TRANSPORT"))
+	if transport == "" {
+		transport = "log"
+		if os.Getenv("SMTP_USERNAME") != "" && os.Getenv("SMTP_PASSWORD") != "" {
+			transport = "smtp"
+		}
+	}
+
+	switch transport {
+	case "smtp":
+		return NewSMTPTransport(
+			getEnvOrDefault("SMTP_HOST", "smtp.gmail.com"),
+			getEnvOrDefault("SMTP_PORT", "587"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+		), nil
+	case "file":
+		return NewFileTransport(getEnvOrDefault("MAIL_FILE_DIR", "tmp/mail"))
+	case "log":
+		return NewLogTransport(), nil
+	case "memory":
+		return NewMemoryTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q: use smtp, file, log or memory", transport)
+	}
+}
+
+// --- SMTP ---
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	smtpSendTimeout = 30 * time.Second
+)
+
+type smtpTransport struct {
+	host     string
+	port     string
+	username string
+	password string
+}
+
+// NewSMTPTransport sends through an SMTP server, upgrading to TLS when the
+// server offers STARTTLS and authenticating when credentials are given.
+func NewSMTPTransport(host, port, username, password string) MailTransport {
+	return &smtpTransport{host: host, port: port, username: username, password: password}
+}
+
+func (t *smtpTransport) Send(ctx context.Context, msg *MailMessage) error {
+	raw, err := buildMIMEMessage(msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(t.host, t.port))
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(smtpSendTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: t.host}); err != nil {
+			return err
+		}
+	}
+	if t.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(msg.From)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(envelopeAddress(msg.To)); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// envelopeAddress strips a display name ("LissanAI <noreply@...>").
+func envelopeAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		return parsed.Address
+	}
+	return address
+}
+
+// buildMIMEMessage encodes msg as multipart/alternative with the plain-text
+// part first, so clients that cannot show HTML fall back to it.
+func buildMIMEMessage(msg *MailMessage, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var raw bytes.Buffer
+	headers := [][2]string{
+		{"From", msg.From},
+		{"To", msg.To},
+		{"Subject", mime.QEncoding.Encode("UTF-8", msg.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(msg.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&raw, "%s: %s\r\n", header[0], header[1])
+	}
+	raw.WriteString("\r\n")
+	raw.Write(body.Bytes())
+	return raw.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "lissanai.com"
+	if at := strings.LastIndex(envelopeAddress(from), "@"); at >= 0 {
+		domain = envelopeAddress(from)[at+1:]
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// --- File ---
+
+type fileTransport struct {
+	dir string
+}
+
+// NewFileTransport writes every message as an .eml file that any mail client can open.
+func NewFileTransport(dir string) (MailTransport, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &fileTransport{dir: dir}, nil
+}
+
+func (t *fileTransport) Send(_ context.Context, msg *MailMessage) error {
+	now := time.Now()
+	raw, err := buildMIMEMessage(msg, now)
+	if err != nil {
+		return err
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(b))
+	return os.WriteFile(filepath.Join(t.dir, name), raw, 0o640)
+}
+
+// --- Log ---
+
+type logTransport struct{}
+
+// NewLogTransport prints messages, including their plain-text body, to the
+// server log. Meant for development: bodies contain one-time links.
+func NewLogTransport() MailTransport {
+	return logTransport{}
+}
+
+func (logTransport) Send(_ context.Context, msg *MailMessage) error {
+	log.Printf("📧 Email to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// --- In-memory ---
+
+// MemoryTransport keeps sent messages in memory so tests can inspect them.
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []MailMessage
+	err      error
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (t *MemoryTransport) Send(_ context.Context, msg *MailMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	t.messages = append(t.messages, *msg)
+	return nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (t *MemoryTransport) Messages() []MailMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]MailMessage(nil), t.messages...)
+}
+
+// FailWith makes every following Send return err; nil restores delivery.
+func (t *MemoryTransport) FailWith(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+}
+
+// Reset forgets the sent messages.
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = nil
+}
@@ -0,0 +1,104 @@
+// internal/service/profile_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"lissanai.com/backend/internal/domain"
+)
+
+// ProfileService serves the public profiles users opt into with the
+// public_profile setting. A profile is found by the user's handle and only
+// shows streaks, badges and how active each day was.
+type ProfileService struct {
+	userCollection     *mongo.Collection
+	streakService      *StreakService
+	achievementService *AchievementService
+}
+
+func NewProfileService(db *mongo.Database, streakService *StreakService, achievementService *AchievementService) *ProfileService {
+	return &ProfileService{
+		userCollection:     db.Collection("users"),
+		streakService:      streakService,
+		achievementService: achievementService,
+	}
+}
+
+// publicUser finds the user sharing a profile under handle. Suspended
+// accounts and accounts awaiting deletion have none.
+func (s *ProfileService) publicUser(ctx context.Context, handle string) (*domain.User, error) {
+	handle, err := domain.NormalizeHandle(handle)
+	if err != nil {
+		return nil, fmt.Errorf("profile not found")
+	}
+
+	var user domain.User
+	err = s.userCollection.FindOne(ctx, bson.M{
+		"handle":                  handle,
+		"settings.public_profile": true,
+		"suspended":               bson.M{"$ne": true},
+		"deletion_requested_at":   bson.M{"$exists": false},
+	}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("profile not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetPublicProfile returns the profile shared under handle, with badge
+// titles in the user's language.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, handle string) (*domain.PublicProfile, error) {
+	user, err := s.publicUser(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := s.achievementService.GetBadges(ctx, user.ID, user.CurrentSettings().Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PublicProfile{
+		Handle:        user.Handle,
+		CurrentStreak: user.CurrentStreak,
+		LongestStreak: user.LongestStreak,
+		Badges:        badges,
+		CalendarURL:   "/u/" + user.Handle + "/calendar.svg",
+	}, nil
+}
+
+// GetPublicCalendar returns the activity calendar shared under handle for
+// year (0 for this year), and the card to render it with. Activity types are
+// left out; only how active each day was is shared.
+func (s *ProfileService) GetPublicCalendar(ctx context.Context, handle string, year int) (*domain.ActivityCalendarResponse, CalendarCard, error) {
+	user, err := s.publicUser(ctx, handle)
+	if err != nil {
+		return nil, CalendarCard{}, err
+	}
+	calendar, err := s.streakService.GetActivityCalendar(ctx, user.ID, year)
+	if err != nil {
+		return nil, CalendarCard{}, err
+	}
+	for _, week := range calendar.Weeks {
+		for i := range week.Days {
+			week.Days[i].ActivityTypes = nil
+		}
+	}
+	calendar.Summary.ActivityBreakdown = nil
+
+	// Images are in English: the PNG font only has Latin letters
+	badges, err := s.achievementService.GetBadges(ctx, user.ID, "en")
+	if err != nil {
+		return nil, CalendarCard{}, err
+	}
+	card := CalendarCard{Title: "@" + user.Handle}
+	for _, badge := range badges {
+		card.Badges = append(card.Badges, badge.Title)
+	}
+	return calendar, card, nil
+}
@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"lissanai.com/backend/internal/domain/entities"
+	"lissanai.com/backend/internal/domain/models"
+	"lissanai.com/backend/internal/llm"
+	"lissanai.com/backend/internal/service"
+)
+
+// The prompt injection corpus is run against the prompts and a fake language
+// model. For each attack the input must stay inside its <user_input> block,
+// the expected injection patterns must be found, and the reply must be
+// accepted or rejected as expected. Attacks with a "reply" get it from a
+// model that always gives it, as one fooled by the attack would; the others
+// get the deterministic fake model's reply. PROMPTS_DIR checks a directory
+// of prompts instead of the built-in ones:
+//
+//	PROMPTS_DIR=./my-prompts go test ./internal/service -run TestPromptInjectionCorpus -v
+
+//go:embed testdata/prompt_injection_corpus.json
+var corpusJSON []byte
+
+type attack struct {
+	Name   string            `json:"name"`
+	Prompt string            `json:"prompt"`
+	Input  map[string]string `json:"input"`
+	Detect []string          `json:"detect"`
+	Reply  json.RawMessage   `json:"reply"`
+	Expect string            `json:"expect"` // "accepted" or "rejected"
+}
+
+// attackTarget is what a prompt's reply is decoded into, and the variables
+// it needs besides the ones under attack
+type attackTarget struct {
+	feature string
+	reply   func() interface{} // nil for text replies
+	vars    map[string]string
+}
+
+var attackTargets = map[string]attackTarget{
+	service.PromptGrammar: {
+		feature: llm.FeatureGrammar,
+		reply:   func() interface{} { return &models.GrammarResponse{} },
+		vars:    map[string]string{"Text": "She go to school every day."},
+	},
+	service.PromptInterviewFeedback: {
+		feature: llm.FeatureInterviewFeedback,
+		reply:   func() interface{} { return &models.Feedback{} },
+		vars:    map[string]string{"Question": "Tell me about yourself.", "Answer": "I am a software engineer."},
+	},
+	service.PromptPronunciation: {
+		feature: llm.FeaturePronunciation,
+		reply:   func() interface{} { return &entities.PronunciationFeedback{} },
+		vars:    map[string]string{"TargetText": "The weather is nice today."},
+	},
+	service.PromptEmailGenerate: {
+		feature: llm.FeatureEmail,
+		reply:   func() interface{} { return &entities.EmailResponse{} },
+		vars:    map[string]string{"Tone": "formal", "TemplateType": "request", "Prompt": "Ask for a day off."},
+	},
+	service.PromptEmailEdit: {
+		feature: llm.FeatureEmail,
+		reply:   func() interface{} { return &entities.EditEmailResponse{} },
+		vars:    map[string]string{"Tone": "formal", "TemplateType": "request", "Draft": "i want day off tomorow"},
+	},
+	service.PromptConversation: {
+		feature: llm.FeatureConversation,
+		vars:    map[string]string{"Said": "How was your weekend?"},
+	},
+}
+
+// untrustedBlock matches one delimited input as llm.Untrusted writes it
+var untrustedBlock = regexp.MustCompile(`(?s)<user_input name="[^"]*">\n.*?\n</user_input>`)
+
+// replyClient answers every request with reply, or with the fake model's
+// reply if there is none.
+type replyClient struct {
+	reply json.RawMessage
+	fake  *llm.Fake
+}
+
+func (c *replyClient) Generate(ctx context.Context, feature string, req *llm.Request) (*llm.Response, error) {
+	if c.reply == nil {
+		return c.fake.Generate(ctx, "default", req)
+	}
+	return &llm.Response{Text: string(c.reply), Provider: "corpus", Model: "fooled"}, nil
+}
+
+func TestPromptInjectionCorpus(t *testing.T) {
+	var corpus []attack
+	if err := json.Unmarshal(corpusJSON, &corpus); err != nil {
+		t.Fatalf("Invalid corpus: %v", err)
+	}
+
+	templates, rollouts, err := service.LoadPromptFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompts, err := service.NewPromptRegistry(templates, rollouts, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range corpus {
+		t.Run(a.Name, func(t *testing.T) {
+			for _, problem := range runAttack(prompts, a) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+func runAttack(prompts *service.PromptRegistry, a attack) []string {
+	target, ok := attackTargets[a.Prompt]
+	if !ok {
+		return []string{fmt.Sprintf("unknown prompt %q", a.Prompt)}
+	}
+	vars := make(map[string]interface{})
+	for name, value := range target.vars {
+		vars[name] = value
+	}
+	var inputs []string
+	for name, value := range a.Input {
+		vars[name] = value
+		inputs = append(inputs, value)
+	}
+
+	ctx := context.Background()
+	prompt, err := prompts.Render(ctx, a.Prompt, vars)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if !strings.HasPrefix(prompt.System, llm.UntrustedInputInstruction) {
+		problems = append(problems, prompt.Version+" does not delimit user input")
+	}
+	outside := untrustedBlock.ReplaceAllString(prompt.Text, "")
+	if strings.Contains(outside, "<user_input") || strings.Contains(outside, "</user_input>") {
+		problems = append(problems, "the input broke out of its block")
+	}
+	for _, input := range inputs {
+		for _, line := range strings.Split(input, "\n") {
+			if len(line) >= 8 && strings.Contains(outside, line) {
+				problems = append(problems, fmt.Sprintf("input outside its block: %q", line))
+			}
+		}
+	}
+
+	detected := llm.DetectInjection(inputs...)
+	if !slices.Equal(detected, a.Detect) {
+		problems = append(problems, fmt.Sprintf("detected %v, expected %v", detected, a.Detect))
+	}
+
+	client := &replyClient{reply: a.Reply, fake: llm.NewFake()}
+	req := llm.Request{System: prompt.System, Prompt: prompt.Text}
+	if target.reply == nil {
+		_, err = client.Generate(ctx, target.feature, &req)
+	} else {
+		_, err = llm.GenerateJSON(ctx, client, target.feature, req, target.reply())
+	}
+	switch {
+	case a.Expect == "accepted" && err != nil:
+		problems = append(problems, "the reply was rejected: "+err.Error())
+	case a.Expect == "rejected" && err == nil:
+		problems = append(problems, "the reply was accepted")
+	case a.Expect == "rejected" && !errors.Is(err, llm.ErrInvalidOutput):
+		problems = append(problems, "the reply failed for another reason: "+err.Error())
+	case a.Expect != "accepted" && a.Expect != "rejected":
+		problems = append(problems, fmt.Sprintf("unknown expectation %q", a.Expect))
+	}
+	return problems
+}
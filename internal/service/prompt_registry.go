@@ -0,0 +1,455 @@
+// internal/service/prompt_registry.go
+package service
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/llm"
+	"lissanai.com/backend/internal/repository"
+)
+
+//go:embed prompts
+var defaultPrompts embed.FS
+
+// Prompts rendered by the AI features
+const (
+	PromptGrammar             = "grammar"
+	PromptInterviewFeedback   = "interview_feedback"
+	PromptInterviewSummary    = "interview_summary"
+	PromptPracticeSentence    = "practice_sentence"
+	PromptPronunciation       = "pronunciation"
+	PromptEmailGenerate       = "email_generate"
+	PromptEmailEdit           = "email_edit"
+	PromptConversation        = "conversation"
+	PromptConversationRewrite = "conversation_rewrite"
+)
+
+// promptFileName matches "v2.tmpl" and "v2.am.tmpl"
+var promptFileName = regexp.MustCompile(`^v([1-9][0-9]*)(?:\.([a-z]{2,3}))?\.tmpl$`)
+
+// LoadPromptFiles reads the prompt templates and rollouts from the
+// directory in PROMPTS_DIR, or the built-in internal/service/prompts if it
+// is not set. See the README there for the layout.
+func LoadPromptFiles() ([]*domain.PromptTemplate, []*domain.PromptRollout, error) {
+	var fsys fs.FS
+	if dir := os.Getenv("PROMPTS_DIR"); dir != "" {
+		fsys = os.DirFS(dir)
+	} else {
+		var err error
+		if fsys, err = fs.Sub(defaultPrompts, "prompts"); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var templates []*domain.PromptTemplate
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		dir, file := path.Split(filePath)
+		match := promptFileName.FindStringSubmatch(file)
+		if match == nil || strings.Count(dir, "/") != 1 {
+			return nil
+		}
+
+		text, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return err
+		}
+		version, _ := strconv.Atoi(match[1])
+		templates = append(templates, &domain.PromptTemplate{
+			Name:    strings.TrimSuffix(dir, "/"),
+			Version: version,
+			Locale:  match[2],
+			Text:    string(text),
+			Source:  domain.PromptSourceFile,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read prompts: %w", err)
+	}
+
+	var rollouts []*domain.PromptRollout
+	data, err := fs.ReadFile(fsys, "rollouts.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed to read prompt rollouts: %w", err)
+	default:
+		if err := json.Unmarshal(data, &rollouts); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse prompt rollouts: %w", err)
+		}
+	}
+	return templates, rollouts, nil
+}
+
+// PromptAudience is who a prompt is rendered for. UserID picks the
+// experiment bucket; Locale the language variant, unless the user's
+// settings name one.
+type PromptAudience struct {
+	UserID string
+	Locale string
+}
+
+type promptAudienceKey struct{}
+
+// WithPromptAudience attaches the audience to ctx for the prompts rendered
+// while handling a request.
+func WithPromptAudience(ctx context.Context, audience PromptAudience) context.Context {
+	return context.WithValue(ctx, promptAudienceKey{}, audience)
+}
+
+// RenderedPrompt is a prompt ready to send. Version identifies the
+// template, e.g. "grammar@v2.am", and is recorded with the result.
+type RenderedPrompt struct {
+	System  string
+	Text    string
+	Version string
+}
+
+type compiledPrompt struct {
+	template *domain.PromptTemplate
+	compiled *template.Template
+}
+
+// promptSet is one prompt's compiled templates by version and locale.
+// Without a rollout naming a version the released one is served: the
+// highest file version, so stored versions wait until a rollout or
+// experiment names them.
+type promptSet struct {
+	versions map[int]map[string]*compiledPrompt
+	latest   int
+	released int
+	rollout  domain.PromptRollout
+}
+
+// PromptRegistry renders the prompts of the AI features. Templates and
+// rollouts come from files, with those stored in MongoDB added on top;
+// Reload picks up changes to the stored ones.
+type PromptRegistry struct {
+	fileTemplates []*domain.PromptTemplate
+	fileRollouts  []*domain.PromptRollout
+	repo          repository.PromptRepository
+	settings      SettingsService
+
+	mu      sync.RWMutex
+	prompts map[string]*promptSet
+}
+
+// NewPromptRegistry compiles the file templates and loads the stored ones.
+// A broken file template is an error; a broken stored one is logged and
+// left out.
+func NewPromptRegistry(templates []*domain.PromptTemplate, rollouts []*domain.PromptRollout, repo repository.PromptRepository, settings SettingsService) (*PromptRegistry, error) {
+	r := &PromptRegistry{fileTemplates: templates, fileRollouts: rollouts, repo: repo, settings: settings}
+	for _, t := range templates {
+		if _, err := compilePrompt(t); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.Reload(context.Background()); err != nil {
+		// Serve the files until the database can be read
+		log.Printf("Failed to load stored prompts: %v", err)
+		r.prompts = buildPromptSets(templates, rollouts)
+	}
+	return r, nil
+}
+
+// Reload rebuilds the prompts from the files and the stored templates and
+// rollouts. On error the current prompts are kept.
+func (r *PromptRegistry) Reload(ctx context.Context) error {
+	templates := append([]*domain.PromptTemplate{}, r.fileTemplates...)
+	rollouts := append([]*domain.PromptRollout{}, r.fileRollouts...)
+	if r.repo != nil {
+		stored, err := r.repo.GetTemplates()
+		if err != nil {
+			return err
+		}
+		storedRollouts, err := r.repo.GetRollouts()
+		if err != nil {
+			return err
+		}
+		templates = append(templates, stored...)
+		rollouts = append(rollouts, storedRollouts...)
+	}
+
+	prompts := buildPromptSets(templates, rollouts)
+	r.mu.Lock()
+	r.prompts = prompts
+	r.mu.Unlock()
+	return nil
+}
+
+// buildPromptSets compiles templates by name, version and locale; later
+// templates and rollouts replace earlier ones for the same key.
+func buildPromptSets(templates []*domain.PromptTemplate, rollouts []*domain.PromptRollout) map[string]*promptSet {
+	prompts := make(map[string]*promptSet)
+	for _, t := range templates {
+		compiled, err := compilePrompt(t)
+		if err != nil {
+			log.Printf("Skipping prompt %s: %v", t.Ref(), err)
+			continue
+		}
+		set := prompts[t.Name]
+		if set == nil {
+			set = &promptSet{versions: make(map[int]map[string]*compiledPrompt), rollout: domain.PromptRollout{Name: t.Name}}
+			prompts[t.Name] = set
+		}
+		if set.versions[t.Version] == nil {
+			set.versions[t.Version] = make(map[string]*compiledPrompt)
+		}
+		set.versions[t.Version][t.Locale] = compiled
+		if t.Version > set.latest {
+			set.latest = t.Version
+		}
+		if t.Source == domain.PromptSourceFile && t.Locale == "" && t.Version > set.released {
+			set.released = t.Version
+		}
+	}
+
+	for _, rollout := range rollouts {
+		set := prompts[rollout.Name]
+		if set == nil {
+			log.Printf("Skipping rollout of unknown prompt %s", rollout.Name)
+			continue
+		}
+		if err := rollout.Validate(set.defaultVersions()); err != nil {
+			log.Printf("Skipping rollout of prompt %s: %v", rollout.Name, err)
+			continue
+		}
+		set.rollout = *rollout
+	}
+	return prompts
+}
+
+// defaultVersions are the versions with a default-locale template, the
+// only ones that can be served to everybody.
+func (s *promptSet) defaultVersions() map[int]bool {
+	versions := make(map[int]bool)
+	for version, locales := range s.versions {
+		if locales[""] != nil {
+			versions[version] = true
+		}
+	}
+	return versions
+}
+
+// promptFuncs are the functions templates can call. Render replaces
+// untrusted with one that also collects the input.
+var promptFuncs = template.FuncMap{
+	"untrusted": func(label string, value interface{}) string {
+		return llm.Untrusted(label, fmt.Sprint(value))
+	},
+}
+
+func compilePrompt(t *domain.PromptTemplate) (*compiledPrompt, error) {
+	compiled, err := template.New(t.Name).Funcs(promptFuncs).Option("missingkey=error").Parse(t.Text)
+	if err == nil && t.System != "" {
+		_, err = compiled.New("system").Parse(t.System)
+	}
+	if err != nil {
+		return nil, &domain.PromptValidationError{Message: fmt.Sprintf("invalid prompt template %s: %v", t.Ref(), err)}
+	}
+	return &compiledPrompt{template: t, compiled: compiled}, nil
+}
+
+// Render fills in the prompt's template for the audience in ctx: the
+// version of the user's experiment bucket, in their language if there is a
+// variant for it. Input the template marks with {{untrusted "label" .Var}}
+// is delimited, the system instruction says how to treat it, and input that
+// looks like an injection attempt is logged.
+func (r *PromptRegistry) Render(ctx context.Context, name string, vars map[string]interface{}) (*RenderedPrompt, error) {
+	r.mu.RLock()
+	set := r.prompts[name]
+	r.mu.RUnlock()
+	if set == nil {
+		return nil, fmt.Errorf("prompt %s not found", name)
+	}
+
+	audience, _ := ctx.Value(promptAudienceKey{}).(PromptAudience)
+	locales := set.versions[set.version(audience.UserID)]
+	prompt := locales[r.locale(ctx, audience)]
+	if prompt == nil {
+		prompt = locales[""]
+	}
+	if prompt == nil {
+		return nil, fmt.Errorf("prompt %s has no version to serve", name)
+	}
+
+	compiled, err := prompt.compiled.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.template.Ref(), err)
+	}
+	var inputs []string
+	compiled.Funcs(template.FuncMap{
+		"untrusted": func(label string, value interface{}) string {
+			input := fmt.Sprint(value)
+			inputs = append(inputs, input)
+			return llm.Untrusted(label, input)
+		},
+	})
+
+	var text, system strings.Builder
+	if err := compiled.Execute(&text, vars); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.template.Ref(), err)
+	}
+	if compiled.Lookup("system") != nil {
+		if err := compiled.ExecuteTemplate(&system, "system", vars); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", prompt.template.Ref(), err)
+		}
+	}
+
+	rendered := &RenderedPrompt{
+		System:  strings.TrimSpace(system.String()),
+		Text:    strings.TrimSpace(text.String()),
+		Version: prompt.template.Ref(),
+	}
+	if len(inputs) > 0 {
+		patterns := llm.DetectInjection(inputs...)
+		if len(patterns) > 0 {
+			log.Printf("Possible prompt injection in %s input (user %q): %s", rendered.Version, audience.UserID, strings.Join(patterns, ", "))
+		}
+		rendered.System = llm.GuardSystem(rendered.System, len(patterns) > 0)
+	}
+	return rendered, nil
+}
+
+// version picks the version to serve to userID; users without an ID are
+// not part of experiments. It is 0, which has no templates, for a prompt
+// with neither a rollout version nor a released version.
+func (s *promptSet) version(userID string) int {
+	if experiment := s.rollout.Experiment; experiment != nil && userID != "" {
+		hash := fnv.New32a()
+		hash.Write([]byte(experiment.ID + ":" + userID))
+		bucket := int(hash.Sum32() % 100)
+		for _, variant := range experiment.Variants {
+			if bucket < variant.Weight {
+				return variant.Version
+			}
+			bucket -= variant.Weight
+		}
+	}
+	if s.rollout.Version != 0 {
+		return s.rollout.Version
+	}
+	return s.released
+}
+
+// locale prefers the signed-in user's language setting over the audience's.
+func (r *PromptRegistry) locale(ctx context.Context, audience PromptAudience) string {
+	if r.settings != nil && audience.UserID != "" {
+		if userID, err := primitive.ObjectIDFromHex(audience.UserID); err == nil {
+			if settings, err := r.settings.GetSettings(ctx, userID); err == nil && settings.Language != "" {
+				return settings.Language
+			}
+		}
+	}
+	return audience.Locale
+}
+
+// Prompts lists every prompt with its templates and the rollout in effect.
+func (r *PromptRegistry) Prompts() []*domain.Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prompts := make([]*domain.Prompt, 0, len(r.prompts))
+	for name, set := range r.prompts {
+		prompt := &domain.Prompt{Name: name, Rollout: set.rollout}
+		for _, locales := range set.versions {
+			for _, compiled := range locales {
+				prompt.Templates = append(prompt.Templates, compiled.template)
+			}
+		}
+		sort.Slice(prompt.Templates, func(i, j int) bool {
+			a, b := prompt.Templates[i], prompt.Templates[j]
+			if a.Version != b.Version {
+				return a.Version < b.Version
+			}
+			return a.Locale < b.Locale
+		})
+		prompts = append(prompts, prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts
+}
+
+// AddTemplate validates and stores a new template, see
+// domain.PromptTemplateRequest. A new version is served once a rollout or
+// experiment names it; a locale variant as soon as its version is.
+func (r *PromptRegistry) AddTemplate(ctx context.Context, name string, req *domain.PromptTemplateRequest) (*domain.PromptTemplate, error) {
+	r.mu.RLock()
+	set := r.prompts[name]
+	r.mu.RUnlock()
+	if set == nil {
+		return nil, errors.New("prompt not found")
+	}
+
+	t := &domain.PromptTemplate{
+		Name:    name,
+		Version: req.Version,
+		Locale:  strings.ToLower(strings.TrimSpace(req.Locale)),
+		System:  req.System,
+		Text:    req.Text,
+	}
+	switch {
+	case t.Version == 0 && t.Locale != "":
+		return nil, &domain.PromptValidationError{Message: "a new version needs its default template before locale variants"}
+	case t.Version == 0:
+		t.Version = set.latest + 1
+	case set.versions[t.Version] == nil:
+		return nil, errors.New("prompt version not found")
+	case set.versions[t.Version][t.Locale] != nil:
+		return nil, repository.ErrPromptTemplateExists
+	}
+	if _, err := compilePrompt(t); err != nil {
+		return nil, err
+	}
+
+	if err := r.repo.CreateTemplate(t); err != nil {
+		return nil, err
+	}
+	if err := r.Reload(ctx); err != nil {
+		log.Printf("Failed to reload prompts: %v", err)
+	}
+	return t, nil
+}
+
+// SetRollout validates and stores the rollout of a prompt and applies it
+// right away.
+func (r *PromptRegistry) SetRollout(ctx context.Context, name string, req *domain.PromptRolloutRequest) (*domain.PromptRollout, error) {
+	r.mu.RLock()
+	set := r.prompts[name]
+	r.mu.RUnlock()
+	if set == nil {
+		return nil, errors.New("prompt not found")
+	}
+
+	rollout := &domain.PromptRollout{Name: name, Version: req.Version, Experiment: req.Experiment}
+	if err := rollout.Validate(set.defaultVersions()); err != nil {
+		return nil, err
+	}
+	if err := r.repo.SaveRollout(rollout); err != nil {
+		return nil, err
+	}
+	if err := r.Reload(ctx); err != nil {
+		log.Printf("Failed to reload prompts: %v", err)
+	}
+	return rollout, nil
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/service"
+)
+
+// memoryPromptRepository stores prompts in memory the way the MongoDB
+// repository does, marking templates as stored.
+type memoryPromptRepository struct {
+	templates []*domain.PromptTemplate
+	rollouts  map[string]*domain.PromptRollout
+}
+
+func (m *memoryPromptRepository) GetTemplates() ([]*domain.PromptTemplate, error) {
+	return m.templates, nil
+}
+
+func (m *memoryPromptRepository) CreateTemplate(template *domain.PromptTemplate) error {
+	stored := *template
+	stored.Source = domain.PromptSourceMongo
+	m.templates = append(m.templates, &stored)
+	return nil
+}
+
+func (m *memoryPromptRepository) GetRollouts() ([]*domain.PromptRollout, error) {
+	var rollouts []*domain.PromptRollout
+	for _, rollout := range m.rollouts {
+		rollouts = append(rollouts, rollout)
+	}
+	return rollouts, nil
+}
+
+func (m *memoryPromptRepository) SaveRollout(rollout *domain.PromptRollout) error {
+	if m.rollouts == nil {
+		m.rollouts = make(map[string]*domain.PromptRollout)
+	}
+	m.rollouts[rollout.Name] = rollout
+	return nil
+}
+
+func TestNewPromptVersionServesNobodyUntilRolledOut(t *testing.T) {
+	files := []*domain.PromptTemplate{
+		{Name: "greeting", Version: 1, Text: "v1 {{.Name}}", Source: domain.PromptSourceFile},
+		{Name: "greeting", Version: 2, Text: "v2 {{.Name}}", Source: domain.PromptSourceFile},
+	}
+	registry, err := service.NewPromptRegistry(files, nil, &memoryPromptRepository{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := service.WithPromptAudience(context.Background(), service.PromptAudience{UserID: "65f000000000000000000001"})
+	served := func() string {
+		t.Helper()
+		rendered, err := registry.Render(ctx, "greeting", map[string]interface{}{"Name": "Abebe"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rendered.Version
+	}
+
+	if got := served(); got != "greeting@v2" {
+		t.Fatalf("served %s before adding a version, want greeting@v2", got)
+	}
+
+	draft, err := registry.AddTemplate(ctx, "greeting", &domain.PromptTemplateRequest{Text: "v3 {{.Name}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft.Version != 3 {
+		t.Fatalf("new version is %d, want 3", draft.Version)
+	}
+	if got := served(); got != "greeting@v2" {
+		t.Errorf("served %s after adding version 3 without a rollout, want greeting@v2", got)
+	}
+
+	if _, err := registry.SetRollout(ctx, "greeting", &domain.PromptRolloutRequest{Version: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if got := served(); got != "greeting@v3" {
+		t.Errorf("served %s after rolling out version 3, want greeting@v3", got)
+	}
+
+	if _, err := registry.SetRollout(ctx, "greeting", &domain.PromptRolloutRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := served(); got != "greeting@v2" {
+		t.Errorf("served %s after resetting the rollout, want greeting@v2", got)
+	}
+}
@@ -0,0 +1,170 @@
+// internal/service/push_provider.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"lissanai.com/backend/internal/domain"
+)
+
+// PushMessage is what a provider shows on one device.
+type PushMessage struct {
+	Category string // domain.PushCategory*; sent to the app in the data payload
+	Title    string
+	Body     string
+	Data     map[string]string
+	TTL      time.Duration // how long the provider may hold the message for an offline device
+}
+
+var (
+	// ErrInvalidPushToken means the device token will never work again
+	// (app uninstalled, token rotated) and should be removed.
+	ErrInvalidPushToken = errors.New("push token is no longer valid")
+	// ErrPermanentPushFailure means the provider rejected the message in a
+	// way retrying cannot fix.
+	ErrPermanentPushFailure = errors.New("permanent push failure")
+)
+
+// PushProvider delivers a message to one device token.
+type PushProvider interface {
+	Send(ctx context.Context, token string, msg *PushMessage) error
+}
+
+// PushProviders picks the provider for a device token: the one that issued
+// it, as recorded when it was registered. iOS apps may register with either.
+type PushProviders struct {
+	FCM  PushProvider
+	APNs PushProvider
+}
+
+func (p PushProviders) forToken(token domain.PushToken) (PushProvider, error) {
+	if token.Provider == domain.PushProviderAPNs {
+		if p.APNs == nil {
+			return nil, fmt.Errorf("%w: APNs is not configured", ErrPermanentPushFailure)
+		}
+		return p.APNs, nil
+	}
+	// Tokens registered before providers were recorded are all FCM tokens
+	return p.FCM, nil
+}
+
+// NewPushProvidersFromEnv configures the providers that have credentials:
+//
+//	FCM_SERVICE_ACCOUNT_FILE  Firebase service account JSON (FCM HTTP v1)
+//	APNS_KEY_FILE             APNs auth key (.p8), with APNS_KEY_ID, APNS_TEAM_ID,
+//	                          APNS_TOPIC (the app bundle ID) and APNS_ENVIRONMENT
+//	                          ("production" or "sandbox")
+//
+// Without FCM credentials, notifications for FCM tokens are only logged, so
+// development needs no provider account.
+func NewPushProvidersFromEnv() (PushProviders, error) {
+	var providers PushProviders
+
+	if path := os.Getenv("FCM_SERVICE_ACCOUNT_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return providers, fmt.Errorf("failed to read FCM service account: %w", err)
+		}
+		fcm, err := NewFCMProvider(data)
+		if err != nil {
+			return providers, err
+		}
+		providers.FCM = fcm
+	} else {
+		providers.FCM = NewLogPushProvider()
+	}
+
+	if path := os.Getenv("APNS_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return providers, fmt.Errorf("failed to read APNs key: %w", err)
+		}
+		apns, err := NewAPNsProvider(data,
+			os.Getenv("APNS_KEY_ID"),
+			os.Getenv("APNS_TEAM_ID"),
+			os.Getenv("APNS_TOPIC"),
+			os.Getenv("APNS_ENVIRONMENT") == "sandbox",
+		)
+		if err != nil {
+			return providers, err
+		}
+		providers.APNs = apns
+	}
+
+	return providers, nil
+}
+
+// --- Log ---
+
+type logPushProvider struct{}
+
+// NewLogPushProvider prints notifications to the server log instead of sending them.
+func NewLogPushProvider() PushProvider {
+	return logPushProvider{}
+}
+
+func (logPushProvider) Send(_ context.Context, token string, msg *PushMessage) error {
+	log.Printf("🔔 Push to %s…: [%s] %s: %s", token[:min(len(token), 12)], msg.Category, msg.Title, msg.Body)
+	return nil
+}
+
+// --- Fake ---
+
+// FakePushDelivery is one message accepted by FakePushProvider.
+type FakePushDelivery struct {
+	Token   string
+	Message PushMessage
+}
+
+// FakePushProvider records deliveries in memory for tests. Tokens marked
+// invalid fail with ErrInvalidPushToken, like an uninstalled app.
+type FakePushProvider struct {
+	mu         sync.Mutex
+	deliveries []FakePushDelivery
+	invalid    map[string]bool
+	err        error
+}
+
+func NewFakePushProvider() *FakePushProvider {
+	return &FakePushProvider{invalid: make(map[string]bool)}
+}
+
+func (p *FakePushProvider) Send(_ context.Context, token string, msg *PushMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.invalid[token] {
+		return ErrInvalidPushToken
+	}
+	if p.err != nil {
+		return p.err
+	}
+	p.deliveries = append(p.deliveries, FakePushDelivery{Token: token, Message: *msg})
+	return nil
+}
+
+// Deliveries returns a copy of everything delivered so far.
+func (p *FakePushProvider) Deliveries() []FakePushDelivery {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]FakePushDelivery(nil), p.deliveries...)
+}
+
+// MarkInvalid makes sends to token fail with ErrInvalidPushToken.
+func (p *FakePushProvider) MarkInvalid(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invalid[token] = true
+}
+
+// FailWith makes every following send to a valid token return err; nil restores delivery.
+func (p *FakePushProvider) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
@@ -0,0 +1,222 @@
+// internal/service/push_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+const (
+	// Notifications not delivered within this time are dropped, since a
+	// reminder arriving hours late does more harm than good
+	defaultPushTTL = 6 * time.Hour
+
+	maxPushAttempts = 5
+	pushRetryBase   = time.Minute
+	pushRetryMax    = 30 * time.Minute
+	pushClaimLease  = time.Minute
+)
+
+// PushService queues notifications for every device of a user. Streak,
+// lesson and interview features enqueue through Notify; PushDispatcher
+// delivers them in the background.
+type PushService interface {
+	Notify(userID primitive.ObjectID, msg *PushMessage) error
+}
+
+type pushService struct {
+	outbox repository.PushOutboxRepository
+	queued func()
+}
+
+// NewPushService queues into outbox. queued, if not nil, is called after
+// each notification is queued so the dispatcher can send it right away.
+func NewPushService(outbox repository.PushOutboxRepository, queued func()) PushService {
+	return &pushService{outbox: outbox, queued: queued}
+}
+
+func (s *pushService) Notify(userID primitive.ObjectID, msg *PushMessage) error {
+	ttl := msg.TTL
+	if ttl <= 0 {
+		ttl = defaultPushTTL
+	}
+
+	err := s.outbox.Enqueue(&domain.PushNotification{
+		UserID:    userID,
+		Category:  msg.Category,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		Data:      msg.Data,
+		DeliverBy: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", msg.Category, err)
+	}
+
+	if s.queued != nil {
+		s.queued()
+	}
+	return nil
+}
+
+// PushDispatcher delivers queued notifications to each registered device of
+// the user. A notification counts as delivered once any device accepted it;
+// it is retried with backoff only if every device failed temporarily. Tokens
+// a provider reports as invalid are removed from the user.
+type PushDispatcher struct {
+	outbox    repository.PushOutboxRepository
+	users     repository.UserRepository
+	sessions  repository.UserSessionRepository
+	providers PushProviders
+	wake      chan struct{}
+}
+
+func NewPushDispatcher(
+	outbox repository.PushOutboxRepository,
+	users repository.UserRepository,
+	sessions repository.UserSessionRepository,
+	providers PushProviders,
+) *PushDispatcher {
+	return &PushDispatcher{
+		outbox:    outbox,
+		users:     users,
+		sessions:  sessions,
+		providers: providers,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Wake asks the dispatcher to look at the outbox now. It never blocks.
+func (d *PushDispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Woken receives after Wake has been called.
+func (d *PushDispatcher) Woken() <-chan struct{} {
+	return d.wake
+}
+
+// DeliverDue sends every notification that is due and returns how many were delivered.
+func (d *PushDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	sent := 0
+	for ctx.Err() == nil {
+		notification, err := d.outbox.ClaimNext(time.Now(), pushClaimLease)
+		if err != nil {
+			return sent, err
+		}
+		if notification == nil {
+			return sent, nil
+		}
+		if d.deliver(ctx, notification) {
+			sent++
+		}
+	}
+	return sent, ctx.Err()
+}
+
+func (d *PushDispatcher) deliver(ctx context.Context, notification *domain.PushNotification) bool {
+	if time.Now().After(notification.DeliverBy) {
+		d.fail(notification, "expired before it could be delivered")
+		return false
+	}
+
+	user, err := d.users.GetUserByID(notification.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			d.fail(notification, "user not found")
+			return false
+		}
+		d.retry(notification, err)
+		return false
+	}
+	if len(user.PushTokens) == 0 {
+		d.fail(notification, "no devices registered")
+		return false
+	}
+
+	msg := &PushMessage{
+		Category: notification.Category,
+		Title:    notification.Title,
+		Body:     notification.Body,
+		Data:     notification.Data,
+		TTL:      time.Until(notification.DeliverBy),
+	}
+
+	delivered := 0
+	var lastErr error
+	retryable := false
+	for _, token := range user.PushTokens {
+		provider, err := d.providers.forToken(token)
+		if err == nil {
+			sendCtx, cancel := context.WithTimeout(ctx, pushSendTimeout)
+			err = provider.Send(sendCtx, token.Token, msg)
+			cancel()
+		}
+
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ErrInvalidPushToken):
+			d.prune(user.ID, token)
+			lastErr = err
+		case errors.Is(err, ErrPermanentPushFailure):
+			lastErr = err
+		default:
+			retryable = true
+			lastErr = err
+		}
+	}
+
+	switch {
+	case delivered > 0:
+		if err := d.outbox.MarkSent(notification.ID); err != nil {
+			log.Printf("Failed to mark push notification %s as sent: %v", notification.ID.Hex(), err)
+		}
+		return true
+	case retryable && notification.Attempts < maxPushAttempts:
+		d.retry(notification, lastErr)
+	default:
+		d.fail(notification, lastErr.Error())
+	}
+	return false
+}
+
+// prune removes a token the provider will never accept again, along with its
+// link to the device session.
+func (d *PushDispatcher) prune(userID primitive.ObjectID, token domain.PushToken) {
+	if err := d.users.RemovePushToken(userID, token.Token); err != nil {
+		log.Printf("Failed to remove invalid push token of user %s: %v", userID.Hex(), err)
+		return
+	}
+	if sessionID, err := primitive.ObjectIDFromHex(token.SessionID); err == nil {
+		if err := d.sessions.SetSessionPushToken(sessionID, ""); err != nil {
+			log.Printf("Failed to unlink push token from session %s: %v", token.SessionID, err)
+		}
+	}
+	log.Printf("Removed invalid %s push token of user %s", token.Platform, userID.Hex())
+}
+
+func (d *PushDispatcher) retry(notification *domain.PushNotification, err error) {
+	next := time.Now().Add(outboxRetryDelay(notification.Attempts, pushRetryBase, pushRetryMax))
+	log.Printf("Failed to deliver %s push notification %s (attempt %d), retrying at %s: %v",
+		notification.Category, notification.ID.Hex(), notification.Attempts, next.Format(time.RFC3339), err)
+	if err := d.outbox.MarkRetry(notification.ID, err.Error(), next); err != nil {
+		log.Printf("Failed to reschedule push notification %s: %v", notification.ID.Hex(), err)
+	}
+}
+
+func (d *PushDispatcher) fail(notification *domain.PushNotification, reason string) {
+	if err := d.outbox.MarkFailed(notification.ID, reason); err != nil {
+		log.Printf("Failed to mark push notification %s as failed: %v", notification.ID.Hex(), err)
+	}
+}
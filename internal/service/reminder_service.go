@@ -0,0 +1,223 @@
+// internal/service/reminder_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+const (
+	// A reminder missed while the server was down is still sent this long
+	// after its time
+	reminderWindow = 2 * time.Hour
+	// At most one reminder a day, even across DST changes or a user moving
+	// their reminder later after already getting one
+	reminderSpacing = 20 * time.Hour
+)
+
+type practiceReminderText struct {
+	streakTitle, streakBody string // when a streak is about to be lost
+	title, body             string
+}
+
+// Practice reminder notifications by UI language
+var practiceReminderMessages = map[string]practiceReminderText{
+	"en": {
+		streakTitle: "🔥 Don't lose your %d-day streak!",
+		streakBody:  "You haven't practiced today. A few minutes keeps your streak alive.",
+		title:       "⏰ Time to practice",
+		body:        "A few minutes of English practice today keeps you moving toward your goal.",
+	},
+	"am": {
+		streakTitle: "🔥 የ%d ቀን ተከታታይነትዎን አያጡ!",
+		streakBody:  "ዛሬ ገና አልተለማመዱም። ጥቂት ደቂቃዎች ተከታታይነትዎን ያስቀጥላሉ።",
+		title:       "⏰ የልምምድ ሰዓት ደርሷል",
+		body:        "ዛሬ ጥቂት ደቂቃዎች የእንግሊዝኛ ልምምድ ወደ ግብዎ ያቀርብዎታል።",
+	},
+}
+
+// ReminderService sends each user a daily practice reminder at their
+// reminder time in their own time zone, unless they have already practiced
+// that day. Users with an active streak get a nudge not to lose it.
+type ReminderService struct {
+	userCollection    *mongo.Collection
+	summaryCollection *mongo.Collection
+	emailService      EmailService
+	pushService       PushService
+	frontendURL       string
+}
+
+func NewReminderService(db *mongo.Database, emailService EmailService, pushService PushService) *ReminderService {
+	return &ReminderService{
+		userCollection:    db.Collection("users"),
+		summaryCollection: db.Collection("daily_activity_summaries"),
+		emailService:      emailService,
+		pushService:       pushService,
+		frontendURL:       getEnvOrDefault("FRONTEND_URL", "https://lissanai.onrender.com"),
+	}
+}
+
+// SendDueReminders sends the reminders that are due now and returns how many
+// were sent. It is meant to run every few minutes; each user is claimed
+// atomically, so several instances may run it at once.
+func (s *ReminderService) SendDueReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+	notRemindedToday := []bson.M{
+		{"reminder_sent_at": bson.M{"$exists": false}},
+		{"reminder_sent_at": bson.M{"$lt": now.Add(-reminderSpacing)}},
+	}
+
+	// An empty reminder time is the opt-out; users without settings get the default time
+	cursor, err := s.userCollection.Find(ctx, bson.M{
+		"settings.reminder_time": bson.M{"$ne": ""},
+		"suspended":              bson.M{"$ne": true},
+		"deletion_scheduled_for": bson.M{"$exists": false},
+		"$or":                    notRemindedToday,
+	}, options.Find().SetProjection(bson.M{
+		"name": 1, "email": 1, "email_verified": 1, "settings": 1, "push_tokens": 1,
+		"current_streak": 1, "last_activity_date": 1, "streak_frozen_until": 1,
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find users to remind: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	sent := 0
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			log.Printf("Error decoding user: %v", err)
+			continue
+		}
+
+		settings := user.CurrentSettings()
+		local := now.In(settings.Location())
+		channel := reminderChannel(&user, settings)
+		if channel == "" || !reminderDue(settings, local) {
+			continue
+		}
+
+		practiced, err := s.summaryCollection.CountDocuments(ctx, bson.M{
+			"user_id": user.ID,
+			"date":    local.Format("2006-01-02"),
+		})
+		if err != nil {
+			log.Printf("Failed to check today's practice for user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		if practiced > 0 {
+			continue
+		}
+
+		// Claim the user so no other run reminds them again today
+		result, err := s.userCollection.UpdateOne(ctx,
+			bson.M{"_id": user.ID, "$or": notRemindedToday},
+			bson.M{"$set": bson.M{"reminder_sent_at": now}},
+		)
+		if err != nil || result.ModifiedCount == 0 {
+			continue
+		}
+
+		if err := s.send(&user, settings, local, channel); err != nil {
+			log.Printf("Failed to send practice reminder to user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		sent++
+	}
+	return sent, cursor.Err()
+}
+
+// reminderDue reports whether local (the user's current local time) is
+// within the window after today's reminder time and outside quiet hours. A
+// reminder time inside quiet hours moves to the end of them, or is skipped
+// if they last past midnight.
+func reminderDue(settings domain.UserSettings, local time.Time) bool {
+	sendAt := settings.ReminderTime
+	if sendAt == "" {
+		return false
+	}
+	if settings.InQuietHours(sendAt) {
+		if settings.QuietHoursEnd < sendAt {
+			return false
+		}
+		sendAt = settings.QuietHoursEnd
+	}
+
+	clock, err := time.Parse("15:04", sendAt)
+	if err != nil {
+		return false
+	}
+	due := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	return !local.Before(due) && local.Before(due.Add(reminderWindow)) &&
+		!settings.InQuietHours(local.Format("15:04"))
+}
+
+// reminderChannel resolves "auto" and returns "" when the user cannot be
+// reached: emails only go to verified addresses.
+func reminderChannel(user *domain.User, settings domain.UserSettings) string {
+	channel := settings.ReminderChannel
+	if channel == "auto" {
+		channel = "email"
+		if len(user.PushTokens) > 0 {
+			channel = "push"
+		}
+	}
+	if channel == "email" && !user.EmailVerified {
+		return ""
+	}
+	return channel
+}
+
+func (s *ReminderService) send(user *domain.User, settings domain.UserSettings, local time.Time, channel string) error {
+	// The streak is only at stake if it was kept yesterday, by practice or a freeze
+	streak := 0
+	yesterday := settings.Day(local).AddDate(0, 0, -1)
+	if user.CurrentStreak > 0 && streakKeptThrough(user).Equal(yesterday) {
+		streak = user.CurrentStreak
+	}
+
+	if channel == "email" {
+		return s.emailService.Send(user, "practice_reminder", map[string]interface{}{
+			"Streak":      streak,
+			"PracticeURL": s.frontendURL,
+			"SettingsURL": s.frontendURL + "/settings",
+		})
+	}
+
+	text, ok := practiceReminderMessages[settings.Language]
+	if !ok {
+		text = practiceReminderMessages["en"]
+	}
+	msg := &PushMessage{
+		Category: domain.PushCategoryStreak,
+		Title:    text.title,
+		Body:     text.body,
+		TTL:      reminderTTL(settings, local),
+	}
+	if streak > 0 {
+		msg.Title = fmt.Sprintf(text.streakTitle, streak)
+		msg.Body = text.streakBody
+		msg.Data = map[string]string{"streak": fmt.Sprint(streak)}
+	}
+	return s.pushService.Notify(user.ID, msg)
+}
+
+// reminderTTL keeps a delayed notification from arriving after midnight or
+// during quiet hours.
+func reminderTTL(settings domain.UserSettings, local time.Time) time.Duration {
+	deadline := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, local.Location())
+	if clock, err := time.Parse("15:04", settings.QuietHoursStart); err == nil {
+		quietStart := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+		if quietStart.After(local) && quietStart.Before(deadline) {
+			deadline = quietStart
+		}
+	}
+	return deadline.Sub(local)
+}
@@ -0,0 +1,68 @@
+// internal/service/settings_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// SettingsService reads a user's settings by ID and is the only place that
+// writes them. Subsystems that have already loaded the user (streaks,
+// reminders, emails, leagues) read User.CurrentSettings instead, which
+// applies the same schema migration without another query.
+type SettingsService interface {
+	// GetSettings returns the user's settings, migrated to the current
+	// schema, or the defaults if none are stored.
+	GetSettings(ctx context.Context, userID primitive.ObjectID) (*domain.UserSettings, error)
+	UpdateSettings(ctx context.Context, userID primitive.ObjectID, req *domain.UpdateSettingsRequest) (*domain.UserSettings, error)
+}
+
+type settingsService struct {
+	userCollection *mongo.Collection
+}
+
+func NewSettingsService(db *mongo.Database) SettingsService {
+	return &settingsService{
+		userCollection: db.Collection("users"),
+	}
+}
+
+func (s *settingsService) GetSettings(ctx context.Context, userID primitive.ObjectID) (*domain.UserSettings, error) {
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	settings := user.CurrentSettings()
+	return &settings, nil
+}
+
+func (s *settingsService) UpdateSettings(ctx context.Context, userID primitive.ObjectID, req *domain.UpdateSettingsRequest) (*domain.UserSettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.ApplyTo(settings); err != nil {
+		return nil, err
+	}
+
+	// The whole document is written so it is always stored at the current version
+	_, err = s.userCollection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"settings": settings, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return nil, errors.New("failed to update settings")
+	}
+	return settings, nil
+}
@@ -0,0 +1,312 @@
+// internal/service/social_auth_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SocialIdentity is the verified identity extracted from a provider token.
+// Subject is the provider's stable user identifier and is what we store as
+// User.ProviderID; it never changes even if the user changes their email.
+type SocialIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// SocialVerifier validates a token issued by a single identity provider.
+type SocialVerifier interface {
+	Verify(ctx context.Context, token string) (*SocialIdentity, error)
+}
+
+// SocialAuthService dispatches token verification to the verifier registered for a provider.
+type SocialAuthService interface {
+	VerifyToken(ctx context.Context, provider, token string) (*SocialIdentity, error)
+}
+
+var (
+	ErrUnsupportedProvider = errors.New("unsupported social provider")
+	ErrInvalidSocialToken  = errors.New("invalid or expired social token")
+)
+
+type socialAuthService struct {
+	verifiers map[string]SocialVerifier
+}
+
+func NewSocialAuthService(verifiers map[string]SocialVerifier) SocialAuthService {
+	normalized := make(map[string]SocialVerifier, len(verifiers))
+	for provider, verifier := range verifiers {
+		normalized[strings.ToLower(provider)] = verifier
+	}
+	return &socialAuthService{verifiers: normalized}
+}
+
+// NewSocialAuthServiceFromEnv registers a verifier for every provider that has
+// credentials configured. Providers without configuration are rejected.
+func NewSocialAuthServiceFromEnv() SocialAuthService {
+	verifiers := make(map[string]SocialVerifier)
+
+	if audiences := splitEnvList("GOOGLE_CLIENT_ID"); len(audiences) > 0 {
+		verifiers["google"] = NewGoogleVerifier(audiences)
+	}
+	if audiences := splitEnvList("APPLE_CLIENT_ID"); len(audiences) > 0 {
+		verifiers["apple"] = NewAppleVerifier(audiences)
+	}
+	if appID, appSecret := os.Getenv("FACEBOOK_APP_ID"), os.Getenv("FACEBOOK_APP_SECRET"); appID != "" && appSecret != "" {
+		verifiers["facebook"] = NewFacebookVerifier(FacebookVerifierConfig{AppID: appID, AppSecret: appSecret})
+	}
+
+	return NewSocialAuthService(verifiers)
+}
+
+func (s *socialAuthService) VerifyToken(ctx context.Context, provider, token string) (*SocialIdentity, error) {
+	verifier, ok := s.verifiers[strings.ToLower(provider)]
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+
+	identity, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSocialToken, err)
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidSocialToken)
+	}
+	identity.Provider = strings.ToLower(provider)
+	identity.Email = strings.ToLower(strings.TrimSpace(identity.Email))
+	return identity, nil
+}
+
+// --- OpenID Connect ID tokens (Google, Apple) ---
+
+// OIDCVerifierConfig describes an OpenID Connect provider whose ID tokens are
+// signed with keys published at JWKSURL. Pointing JWKSURL at a local server
+// serving fixture keys allows the verifier to be exercised offline.
+type OIDCVerifierConfig struct {
+	JWKSURL    string
+	Issuers    []string
+	Audiences  []string
+	HTTPClient *http.Client
+	// Leeway tolerates small clock differences between us and the provider.
+	Leeway time.Duration
+}
+
+type oidcVerifier struct {
+	config OIDCVerifierConfig
+	keys   *jwksCache
+}
+
+func NewOIDCVerifier(config OIDCVerifierConfig) SocialVerifier {
+	if config.Leeway == 0 {
+		config.Leeway = 30 * time.Second
+	}
+	return &oidcVerifier{
+		config: config,
+		keys:   newJWKSCache(config.JWKSURL, config.HTTPClient),
+	}
+}
+
+// NewGoogleVerifier verifies Google Sign-In ID tokens for the given OAuth client IDs.
+func NewGoogleVerifier(clientIDs []string) SocialVerifier {
+	return NewOIDCVerifier(OIDCVerifierConfig{
+		JWKSURL:   "https://www.googleapis.com/oauth2/v3/certs",
+		Issuers:   []string{"accounts.google.com", "https://accounts.google.com"},
+		Audiences: clientIDs,
+	})
+}
+
+// NewAppleVerifier verifies Sign in with Apple ID tokens for the given service/bundle IDs.
+func NewAppleVerifier(clientIDs []string) SocialVerifier {
+	return NewOIDCVerifier(OIDCVerifierConfig{
+		JWKSURL:   "https://appleid.apple.com/auth/keys",
+		Issuers:   []string{"https://appleid.apple.com"},
+		Audiences: clientIDs,
+	})
+}
+
+type idTokenClaims struct {
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // Apple sends "true"/"false" strings
+	Name          string      `json:"name"`
+	jwt.RegisteredClaims
+}
+
+func (v *oidcVerifier) Verify(ctx context.Context, token string) (*SocialIdentity, error) {
+	claims := &idTokenClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid header")
+		}
+		return v.keys.Key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.config.Leeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	if !contains(v.config.Issuers, claims.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !audienceMatches(claims.Audience, v.config.Audiences) {
+		return nil, errors.New("token audience does not match")
+	}
+
+	return &SocialIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: parseBoolClaim(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// --- Facebook access tokens ---
+
+// FacebookVerifierConfig configures verification of Facebook user access
+// tokens through the Graph API. GraphURL can point at a local stand-in server.
+type FacebookVerifierConfig struct {
+	AppID      string
+	AppSecret  string
+	GraphURL   string
+	HTTPClient *http.Client
+}
+
+type facebookVerifier struct {
+	config FacebookVerifierConfig
+}
+
+func NewFacebookVerifier(config FacebookVerifierConfig) SocialVerifier {
+	if config.GraphURL == "" {
+		config.GraphURL = "https://graph.facebook.com"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &facebookVerifier{config: config}
+}
+
+func (v *facebookVerifier) Verify(ctx context.Context, token string) (*SocialIdentity, error) {
+	// 1. Ask Facebook whether the token is valid and was issued to our app.
+	var debug struct {
+		Data struct {
+			AppID     string `json:"app_id"`
+			IsValid   bool   `json:"is_valid"`
+			UserID    string `json:"user_id"`
+			ExpiresAt int64  `json:"expires_at"`
+		} `json:"data"`
+	}
+	debugParams := url.Values{
+		"input_token":  {token},
+		"access_token": {v.config.AppID + "|" + v.config.AppSecret},
+	}
+	if err := v.getJSON(ctx, "/debug_token", debugParams, &debug); err != nil {
+		return nil, err
+	}
+	if !debug.Data.IsValid {
+		return nil, errors.New("token is not valid")
+	}
+	if debug.Data.AppID != v.config.AppID {
+		return nil, errors.New("token was issued to a different app")
+	}
+	if debug.Data.ExpiresAt != 0 && time.Unix(debug.Data.ExpiresAt, 0).Before(time.Now()) {
+		return nil, errors.New("token is expired")
+	}
+
+	// 2. Fetch the profile the token belongs to.
+	var profile struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	profileParams := url.Values{
+		"fields":       {"id,name,email"},
+		"access_token": {token},
+	}
+	if err := v.getJSON(ctx, "/me", profileParams, &profile); err != nil {
+		return nil, err
+	}
+	if profile.ID != debug.Data.UserID {
+		return nil, errors.New("token subject mismatch")
+	}
+
+	return &SocialIdentity{
+		Subject: profile.ID,
+		Email:   profile.Email,
+		// Graph API only returns confirmed primary emails.
+		EmailVerified: profile.Email != "",
+		Name:          profile.Name,
+	}, nil
+}
+
+func (v *facebookVerifier) getJSON(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.config.GraphURL, "/")+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create facebook request: %w", err)
+	}
+
+	resp, err := v.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call facebook graph api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("facebook graph api returned status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode facebook response: %w", err)
+	}
+	return nil
+}
+
+// --- helpers ---
+
+func audienceMatches(tokenAudience jwt.ClaimStrings, allowed []string) bool {
+	for _, aud := range tokenAudience {
+		if contains(allowed, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBoolClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+// splitEnvList reads a comma separated environment variable, e.g. one OAuth
+// client ID per platform.
+func splitEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://issuer.test"
+	testAudience = "client-id"
+	testKid      = "fixture-key"
+)
+
+// newJWKSServer serves the public half of key under kid, as an identity
+// provider's JWKS endpoint would.
+func newJWKSServer(t *testing.T, kid string, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	set := JWKSet{Keys: []JWK{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newFixtureKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signIDToken(t *testing.T, key *rsa.PrivateKey, kid string, claims idTokenClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() idTokenClaims {
+	return idTokenClaims{
+		Email:         "Learner@Example.com",
+		EmailVerified: "true",
+		Name:          "Learner",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testIssuer,
+			Subject:   "subject-1",
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	key := newFixtureKey(t)
+	otherKey := newFixtureKey(t)
+	server := newJWKSServer(t, testKid, key)
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: func() string { return signIDToken(t, key, testKid, validClaims()) },
+		},
+		{
+			name:    "bad signature",
+			token:   func() string { return signIDToken(t, otherKey, testKid, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"someone-else"}
+				return signIDToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "https://attacker.test"
+				return signIDToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return signIDToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return signIDToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   func() string { return signIDToken(t, key, "rotated-away", validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "no kid",
+			token:   func() string { return signIDToken(t, key, "", validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+				token.Header["kid"] = testKid
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewSocialAuthService(map[string]SocialVerifier{
+				"google": NewOIDCVerifier(OIDCVerifierConfig{
+					JWKSURL:   server.URL,
+					Issuers:   []string{testIssuer},
+					Audiences: []string{testAudience},
+					Leeway:    time.Second,
+				}),
+			})
+
+			identity, err := auth.VerifyToken(context.Background(), "Google", tt.token())
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSocialToken) {
+					t.Fatalf("expected ErrInvalidSocialToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if identity.Provider != "google" || identity.Subject != "subject-1" {
+				t.Errorf("unexpected identity %+v", identity)
+			}
+			if identity.Email != "learner@example.com" || !identity.EmailVerified {
+				t.Errorf("unexpected email %q (verified %v)", identity.Email, identity.EmailVerified)
+			}
+		})
+	}
+}
+
+func TestSocialAuthServiceUnsupportedProvider(t *testing.T) {
+	auth := NewSocialAuthService(nil)
+	_, err := auth.VerifyToken(context.Background(), "myspace", "token")
+	if !errors.Is(err, ErrUnsupportedProvider) {
+		t.Fatalf("expected ErrUnsupportedProvider, got %v", err)
+	}
+}
+
+// newGraphServer stands in for the Facebook Graph API, answering
+// debug_token with debug and /me with the profile of user-1.
+func newGraphServer(t *testing.T, debug map[string]interface{}) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "app-id|app-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": debug})
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"id": "user-1", "name": "Learner", "email": "learner@example.com"})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFacebookVerifier(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		debug   map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			debug: map[string]interface{}{"app_id": "app-id", "is_valid": true, "user_id": "user-1", "expires_at": future},
+		},
+		{
+			name:    "invalid token",
+			debug:   map[string]interface{}{"app_id": "app-id", "is_valid": false, "user_id": "user-1", "expires_at": future},
+			wantErr: true,
+		},
+		{
+			name:    "issued to another app",
+			debug:   map[string]interface{}{"app_id": "other-app", "is_valid": true, "user_id": "user-1", "expires_at": future},
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			debug:   map[string]interface{}{"app_id": "app-id", "is_valid": true, "user_id": "user-1", "expires_at": time.Now().Add(-time.Hour).Unix()},
+			wantErr: true,
+		},
+		{
+			name:    "profile of another user",
+			debug:   map[string]interface{}{"app_id": "app-id", "is_valid": true, "user_id": "user-2", "expires_at": future},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newGraphServer(t, tt.debug)
+			verifier := NewFacebookVerifier(FacebookVerifierConfig{
+				AppID:     "app-id",
+				AppSecret: "app-secret",
+				GraphURL:  server.URL,
+			})
+
+			identity, err := verifier.Verify(context.Background(), "user-token")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got identity %+v", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if identity.Subject != "user-1" || identity.Email != "learner@example.com" || !identity.EmailVerified {
+				t.Errorf("unexpected identity %+v", identity)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"lissanai.com/backend/internal/client"
+	"lissanai.com/backend/internal/llm"
 	// If you moved shared interfaces/types to internal/common, import it here:
 	// "lissanai.com/backend/internal/common"
 )
@@ -16,15 +17,17 @@ type SpeakingService interface {
 
 
 type speakingServiceImpl struct {
-	groqClient         *client.GroqClient
+	llm                llm.Client
+	prompts            *PromptRegistry
 	whisperClient      *client.WhisperClient
 	unrealSpeechClient *client.UnrealSpeechTTSClient // 1. Renamed the field
 }
 
 // 2. Updated the function signature to accept the new client type
-func NewSpeakingService(groq *client.GroqClient, whisper *client.WhisperClient, unreal *client.UnrealSpeechTTSClient) SpeakingService {
+func NewSpeakingService(llmClient llm.Client, prompts *PromptRegistry, whisper *client.WhisperClient, unreal *client.UnrealSpeechTTSClient) SpeakingService {
 	return &speakingServiceImpl{
-		groqClient:         groq,
+		llm:                llmClient,
+		prompts:            prompts,
 		whisperClient:      whisper,
 		unrealSpeechClient: unreal, // 3. Updated the assignment
 	}
@@ -41,15 +44,7 @@ func (s *speakingServiceImpl) ProcessAudioFeedback(ctx context.Context, audioDat
 	fmt.Println("transcripted text:", text)
 
 	// 2️⃣ LLM: Generate response with strong English-only instruction
-	prompt := fmt.Sprintf(`
-You are a friendly person having a casual chat. 
-Always reply only in English, in a natural and conversational way.
-Keep your answers short and relaxed, like talking to a friend.
-
-User said: %s
-`, text)
-
-	response, err := s.groqClient.GenerateContent(ctx, prompt)
+	response, err := s.converse(ctx, PromptConversation, map[string]interface{}{"Said": text})
 	if err != nil {
 		return nil, fmt.Errorf("LLM error: %w", err)
 	}
@@ -63,8 +58,7 @@ User said: %s
 	// 🔒 Extra safeguard: ensure response is English only
 	if !isEnglish(cleanedResponse) {
 		fmt.Println("⚠️ Non-English detected, rewriting...")
-		rePrompt := "Rewrite this strictly in English, simple and clear: " + cleanedResponse
-		englishResponse, err := s.groqClient.GenerateContent(ctx, rePrompt)
+		englishResponse, err := s.converse(ctx, PromptConversationRewrite, map[string]interface{}{"Reply": cleanedResponse})
 		if err == nil && len(strings.TrimSpace(englishResponse)) > 0 {
 			cleanedResponse = strings.TrimSpace(englishResponse)
 		}
@@ -93,3 +87,17 @@ func isEnglish(s string) bool {
 	}
 	return true
 }
+
+// converse renders a conversation prompt and asks the conversation model
+// for a short, chatty reply.
+func (s *speakingServiceImpl) converse(ctx context.Context, name string, vars map[string]interface{}) (string, error) {
+	prompt, err := s.prompts.Render(ctx, name, vars)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.llm.Generate(ctx, llm.FeatureConversation, &llm.Request{System: prompt.System, Prompt: prompt.Text})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
@@ -0,0 +1,263 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/service"
+)
+
+// The streak write path is checked against a real MongoDB with many
+// concurrent requests, so no update is lost or applied twice. The tests work
+// in a scratch database that is dropped afterwards and are skipped unless
+// MONGODB_URI is set:
+//
+//	MONGODB_URI=mongodb://localhost:27017 go test ./internal/service -run TestStreakConcurrency
+
+const (
+	streakCheckDatabase = "lissanai_streak_test"
+	streakCheckWorkers  = 50
+	streakCheckRounds   = 10
+)
+
+var activityTypes = []string{"grammar_check", "lesson_completed", "quiz_passed", "mock_interview"}
+
+type streakChecker struct {
+	t       *testing.T
+	ctx     context.Context
+	db      *mongo.Database
+	streaks *service.StreakService
+}
+
+func TestStreakConcurrency(t *testing.T) {
+	if os.Getenv("MONGODB_URI") == "" {
+		t.Skip("MONGODB_URI is not set")
+	}
+
+	// Never touch the real data
+	t.Setenv("MONGODB_DATABASE", streakCheckDatabase)
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	ctx := context.Background()
+	t.Cleanup(func() { db.Drop(ctx) })
+	streaks := service.NewStreakService(db, nil, nil, nil)
+
+	rounds := streakCheckRounds
+	if testing.Short() {
+		rounds = 1
+	}
+
+	checks := []struct {
+		name string
+		run  func(c *streakChecker)
+	}{
+		{"first activities of a new user start a 1 day streak", checkFirstActivity},
+		{"activities on the day after extend the streak once", checkConsecutiveDay},
+		{"activities after a gap restart the streak once", checkBrokenStreak},
+		{"concurrent freezes of today use one freeze", checkFreezeToday},
+		{"concurrent expiry checks use one freeze per missed day", checkMissedDayFreeze},
+		{"expiry never undoes a concurrent activity", checkExpiryRace},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			for round := 0; round < rounds; round++ {
+				check.run(&streakChecker{t: t, ctx: ctx, db: db, streaks: streaks})
+			}
+		})
+	}
+}
+
+// newUser inserts a user in the default time zone with the given streak
+// state and number of freezes in their inventory.
+func (c *streakChecker) newUser(streak int, lastActivity time.Time, freezes int) primitive.ObjectID {
+	c.t.Helper()
+	settings := domain.DefaultSettings()
+	result, err := c.db.Collection("users").InsertOne(c.ctx, bson.M{
+		"email":              fmt.Sprintf("streak-check-%s@example.com", primitive.NewObjectID().Hex()),
+		"settings":           settings,
+		"current_streak":     streak,
+		"longest_streak":     streak,
+		"last_activity_date": lastActivity,
+		"created_at":         time.Now(),
+	})
+	if err != nil {
+		c.t.Fatalf("Failed to create user: %v", err)
+	}
+	userID := result.InsertedID.(primitive.ObjectID)
+
+	for i := 0; i < freezes; i++ {
+		_, err := c.db.Collection("streak_freezes").InsertOne(c.ctx, domain.StreakFreeze{
+			UserID:    userID,
+			Source:    domain.FreezeSourceMonthly,
+			SourceKey: fmt.Sprintf("check:%d", i),
+			EarnedAt:  time.Now(),
+		})
+		if err != nil {
+			c.t.Fatalf("Failed to create streak freeze: %v", err)
+		}
+	}
+	return userID
+}
+
+func (c *streakChecker) freezesAvailable(userID primitive.ObjectID) int64 {
+	c.t.Helper()
+	count, err := c.db.Collection("streak_freezes").CountDocuments(c.ctx, bson.M{
+		"user_id": userID,
+		"used_on": bson.M{"$exists": false},
+	})
+	if err != nil {
+		c.t.Fatalf("Failed to count streak freezes: %v", err)
+	}
+	return count
+}
+
+// parallel runs fn on every worker at once and returns how many calls succeeded.
+func (c *streakChecker) parallel(fn func(worker int) error) int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	start := make(chan struct{})
+	succeeded := 0
+	for i := 0; i < streakCheckWorkers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			<-start
+			if err := fn(worker); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return succeeded
+}
+
+func (c *streakChecker) user(id primitive.ObjectID) domain.User {
+	c.t.Helper()
+	var user domain.User
+	if err := c.db.Collection("users").FindOne(c.ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		c.t.Fatalf("Failed to read user: %v", err)
+	}
+	return user
+}
+
+func (c *streakChecker) expect(what string, got, want interface{}) {
+	c.t.Helper()
+	if got != want {
+		c.t.Errorf("%s is %v, want %v", what, got, want)
+	}
+}
+
+func today() time.Time {
+	return domain.DefaultSettings().Day(time.Now())
+}
+
+func (c *streakChecker) recordActivities(userID primitive.ObjectID) int {
+	return c.parallel(func(worker int) error {
+		return c.streaks.RecordActivity(c.ctx, userID, activityTypes[worker%len(activityTypes)])
+	})
+}
+
+// checkCalendar expects one summary for today counting every activity.
+func (c *streakChecker) checkCalendar(userID primitive.ObjectID, recorded int) {
+	c.t.Helper()
+	summaries := c.db.Collection("daily_activity_summaries")
+	date := today().Format("2006-01-02")
+	count, err := summaries.CountDocuments(c.ctx, bson.M{"user_id": userID, "date": date})
+	if err != nil {
+		c.t.Fatalf("Failed to count summaries: %v", err)
+	}
+	c.expect("summaries for today", count, int64(1))
+
+	var summary domain.DailyActivitySummary
+	if err := summaries.FindOne(c.ctx, bson.M{"user_id": userID, "date": date}).Decode(&summary); err != nil {
+		c.t.Fatalf("Failed to read summary: %v", err)
+	}
+	c.expect("activity count", summary.ActivityCount, recorded)
+	c.expect("activity types", len(summary.ActivityTypes), min(recorded, len(activityTypes)))
+}
+
+func checkFirstActivity(c *streakChecker) {
+	userID := c.newUser(0, time.Time{}, 0)
+	recorded := c.recordActivities(userID)
+	c.expect("recorded activities", recorded, streakCheckWorkers)
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 1)
+	c.expect("longest streak", user.LongestStreak, 1)
+	c.expect("last activity", user.LastActivityDate.Equal(today()), true)
+	c.checkCalendar(userID, recorded)
+}
+
+func checkConsecutiveDay(c *streakChecker) {
+	userID := c.newUser(5, today().AddDate(0, 0, -1), 0)
+	recorded := c.recordActivities(userID)
+	c.expect("recorded activities", recorded, streakCheckWorkers)
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 6)
+	c.expect("longest streak", user.LongestStreak, 6)
+	c.checkCalendar(userID, recorded)
+}
+
+func checkBrokenStreak(c *streakChecker) {
+	userID := c.newUser(9, today().AddDate(0, 0, -3), 0)
+	c.recordActivities(userID)
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 1)
+	c.expect("longest streak", user.LongestStreak, 9)
+}
+
+func checkFreezeToday(c *streakChecker) {
+	userID := c.newUser(4, today().AddDate(0, 0, -1), 2)
+	frozen := c.parallel(func(int) error {
+		return c.streaks.FreezeStreak(c.ctx, userID, "concurrency check")
+	})
+	c.expect("successful freezes", frozen, 1)
+	c.expect("freezes available", c.freezesAvailable(userID), int64(1))
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 4)
+	c.expect("frozen until", user.StreakFrozenUntil.Equal(today()), true)
+}
+
+func checkMissedDayFreeze(c *streakChecker) {
+	userID := c.newUser(4, today().AddDate(0, 0, -2), 2)
+	c.parallel(func(int) error {
+		return c.streaks.CheckAndUpdateExpiredStreaks(c.ctx)
+	})
+	c.expect("freezes available", c.freezesAvailable(userID), int64(1))
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 4)
+	c.expect("frozen until", user.StreakFrozenUntil.Equal(today().AddDate(0, 0, -1)), true)
+}
+
+func checkExpiryRace(c *streakChecker) {
+	// Whichever runs first, the streak must end up restarted by today's activity
+	userID := c.newUser(4, today().AddDate(0, 0, -3), 0)
+	c.parallel(func(worker int) error {
+		if worker%2 == 0 {
+			return c.streaks.CheckAndUpdateExpiredStreaks(c.ctx)
+		}
+		return c.streaks.RecordActivity(c.ctx, userID, "lesson_completed")
+	})
+
+	user := c.user(userID)
+	c.expect("current streak", user.CurrentStreak, 1)
+	c.expect("last activity", user.LastActivityDate.Equal(today()), true)
+}
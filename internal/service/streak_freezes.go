@@ -0,0 +1,224 @@
+// internal/service/streak_freezes.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// A user holds at most this many unused freezes; grants beyond it are skipped
+const maxStreakFreezes = 2
+
+// errDayAlreadyFrozen is returned by useFreeze when another freeze already
+// covers the day.
+var errDayAlreadyFrozen = errors.New("day is already frozen")
+
+func newFreezeCollection(db *mongo.Database) *mongo.Collection {
+	collection := db.Collection("streak_freezes")
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Each milestone day or month grants one freeze, however often it is processed
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "source_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Unused freezes each hold one of the maxStreakFreezes inventory
+			// slots, so concurrent grants cannot overfill the inventory
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "slot", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slot": bson.M{"$exists": true}}),
+		},
+		{
+			// A day is covered by one freeze, so concurrent checks of a missed day use only one
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "used_on", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"used_on": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create streak_freezes indexes: %v", err)
+	}
+	return collection
+}
+
+// streakKeptThrough returns the last day the user's streak was kept, by
+// practice or by a freeze.
+func streakKeptThrough(user *domain.User) time.Time {
+	if user.StreakFrozenUntil.After(user.LastActivityDate) {
+		return user.StreakFrozenUntil
+	}
+	return user.LastActivityDate
+}
+
+// grantFreeze adds a freeze to the user's inventory unless it is full or
+// sourceKey was already granted. It reports whether a freeze was added.
+func (s *StreakService) grantFreeze(ctx context.Context, userID primitive.ObjectID, source, sourceKey string) (bool, error) {
+	// Freezes granted before slots existed hold none, so count them too
+	available, err := s.freezeCollection.CountDocuments(ctx, bson.M{
+		"user_id": userID,
+		"used_on": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to count streak freezes: %w", err)
+	}
+	if available >= maxStreakFreezes {
+		return false, nil
+	}
+
+	for slot := 0; slot < maxStreakFreezes; slot++ {
+		_, err = s.freezeCollection.InsertOne(ctx, domain.StreakFreeze{
+			UserID:    userID,
+			Source:    source,
+			SourceKey: sourceKey,
+			Slot:      &slot,
+			EarnedAt:  time.Now(),
+		})
+		if err == nil {
+			return true, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return false, fmt.Errorf("failed to grant streak freeze: %w", err)
+		}
+
+		// Either sourceKey was granted already or the slot is taken
+		granted, err := s.freezeCollection.CountDocuments(ctx, bson.M{"user_id": userID, "source_key": sourceKey})
+		if err != nil {
+			return false, fmt.Errorf("failed to grant streak freeze: %w", err)
+		}
+		if granted > 0 {
+			return false, nil
+		}
+	}
+	// Every slot is taken: the inventory is full
+	return false, nil
+}
+
+// useFreeze takes the oldest freeze from the user's inventory to cover day
+// and records it on the user. It returns false if the inventory is empty and
+// errDayAlreadyFrozen if the day was already covered, in which case the
+// user is still updated so a freeze recorded only halfway is completed.
+func (s *StreakService) useFreeze(ctx context.Context, user *domain.User, day time.Time, reason, note string) (bool, error) {
+	now := time.Now()
+	set := bson.M{"used_on": day, "used_reason": reason, "used_at": now}
+	if note != "" {
+		set["note"] = note
+	}
+
+	err := s.freezeCollection.FindOneAndUpdate(ctx,
+		bson.M{"user_id": user.ID, "used_on": bson.M{"$exists": false}},
+		bson.M{"$set": set, "$unset": bson.M{"slot": ""}},
+		options.FindOneAndUpdate().SetSort(bson.M{"earned_at": 1}),
+	).Err()
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return false, nil
+	case err != nil && !mongo.IsDuplicateKeyError(err):
+		return false, fmt.Errorf("failed to use streak freeze: %w", err)
+	}
+	used := err == nil
+
+	_, err = s.userCollection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{
+		"$max": bson.M{"streak_frozen_until": day},
+		"$set": bson.M{"updated_at": now},
+	})
+	if err != nil {
+		return used, fmt.Errorf("failed to record streak freeze: %w", err)
+	}
+	if day.After(user.StreakFrozenUntil) {
+		user.StreakFrozenUntil = day
+	}
+	if !used {
+		return false, errDayAlreadyFrozen
+	}
+	log.Printf("🧊 Used a streak freeze for user %s on %s (%s)", user.ID.Hex(), day.Format("2006-01-02"), reason)
+	return true, nil
+}
+
+// coverMissedDays uses a freeze for every day between the last day the
+// streak was kept and today, in order, and reports whether the streak
+// survived. It stops at the first day no freeze is left for.
+func (s *StreakService) coverMissedDays(ctx context.Context, user *domain.User, today time.Time) (bool, error) {
+	if user.CurrentStreak == 0 || user.LastActivityDate.IsZero() {
+		return false, nil
+	}
+	for day := streakKeptThrough(user).AddDate(0, 0, 1); day.Before(today); day = day.AddDate(0, 0, 1) {
+		used, err := s.useFreeze(ctx, user, day, domain.FreezeUseMissedDay, "")
+		if errors.Is(err, errDayAlreadyFrozen) {
+			// Covered by a concurrent check
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if !used {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GetStreakFreezes returns the user's freeze inventory and history.
+func (s *StreakService) GetStreakFreezes(ctx context.Context, userID primitive.ObjectID) (*domain.StreakFreezesResponse, error) {
+	cursor, err := s.freezeCollection.Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.M{"earned_at": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find streak freezes: %w", err)
+	}
+	freezes := []domain.StreakFreeze{}
+	if err := cursor.All(ctx, &freezes); err != nil {
+		return nil, fmt.Errorf("failed to find streak freezes: %w", err)
+	}
+
+	available := 0
+	for _, freeze := range freezes {
+		if freeze.UsedOn == nil {
+			available++
+		}
+	}
+	return &domain.StreakFreezesResponse{Available: available, Max: maxStreakFreezes, Freezes: freezes}, nil
+}
+
+// GrantMonthlyFreezes gives every user with an active account one freeze for
+// the current month, up to the inventory size. Each month is granted once,
+// so it is safe to run daily.
+func (s *StreakService) GrantMonthlyFreezes(ctx context.Context) error {
+	month := time.Now().UTC().Format("2006-01")
+	cursor, err := s.userCollection.Find(ctx, bson.M{
+		"suspended":              bson.M{"$ne": true},
+		"deletion_scheduled_for": bson.M{"$exists": false},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	granted := 0
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			log.Printf("Error decoding user: %v", err)
+			continue
+		}
+		ok, err := s.grantFreeze(ctx, user.ID, domain.FreezeSourceMonthly, "monthly:"+month)
+		if err != nil {
+			log.Printf("Failed to grant monthly streak freeze to user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		if ok {
+			granted++
+		}
+	}
+	if granted > 0 {
+		log.Printf("🧊 Granted %d monthly streak freezes for %s", granted, month)
+	}
+	return cursor.Err()
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -15,39 +16,67 @@ import (
 type StreakService struct {
 	userCollection     *mongo.Collection
 	activityCollection *mongo.Collection
+	freezeCollection   *mongo.Collection
 	calendarService    *ActivityCalendarService
+	pushService        PushService
+	achievementService *AchievementService
+	xpService          *XPService
 }
 
-func NewStreakService(db *mongo.Database) *StreakService {
+// NewStreakService creates the streak service. achievementService and
+// xpService, if not nil, award badges and XP for every recorded activity.
+func NewStreakService(db *mongo.Database, pushService PushService, achievementService *AchievementService, xpService *XPService) *StreakService {
 	calendarService := NewActivityCalendarService(db)
 	return &StreakService{
 		userCollection:     db.Collection("users"),
 		activityCollection: db.Collection("streak_activities"),
+		freezeCollection:   newFreezeCollection(db),
 		calendarService:    calendarService,
+		pushService:        pushService,
+		achievementService: achievementService,
+		xpService:          xpService,
 	}
 }
 
-// RecordActivity records a user activity and updates their streak
+// How often updateStreak re-reads a user changed by a concurrent request
+// before giving up
+const maxStreakUpdateAttempts = 5
+
+// Streak milestone notifications by UI language
+var streakMilestoneMessages = map[string][2]string{
+	"en": {"🔥 %d-day streak!", "You've practiced %d days in a row. Keep it going!"},
+	"am": {"🔥 የ%d ቀን ተከታታይነት!", "ለ%d ተከታታይ ቀናት ተለማምደዋል። በዚሁ ይቀጥሉ!"},
+}
+
+// RecordActivity records a user activity and updates their streak. The
+// activity counts for the current day in the user's time zone.
 func (s *StreakService) RecordActivity(ctx context.Context, userID primitive.ObjectID, activityType string) error {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return s.recordActivity(ctx, userID, activityType, false)
+}
 
-	// Check if user already has activity today
-	existingActivity, err := s.activityCollection.CountDocuments(ctx, bson.M{
-		"user_id": userID,
-		"date": bson.M{
-			"$gte": today,
-			"$lt":  today.Add(24 * time.Hour),
-		},
-	})
+// RecordManualActivity keeps the streak like RecordActivity, for activities
+// reported through the API rather than by the feature itself. It earns no
+// badges or XP, and activity_count badges do not count it.
+func (s *StreakService) RecordManualActivity(ctx context.Context, userID primitive.ObjectID, activityType string) error {
+	return s.recordActivity(ctx, userID, activityType, true)
+}
+
+func (s *StreakService) recordActivity(ctx context.Context, userID primitive.ObjectID, activityType string, manual bool) error {
+	var user domain.User
+	err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
 	if err != nil {
-		return fmt.Errorf("failed to check existing activity: %w", err)
+		return fmt.Errorf("failed to find user: %w", err)
 	}
 
+	settings := user.CurrentSettings()
+	now := time.Now()
+	today := settings.Day(now)
+
 	// Record the activity
 	activity := domain.StreakActivity{
 		UserID:       userID,
 		ActivityType: activityType,
+		Manual:       manual,
 		Date:         today,
 		CreatedAt:    now,
 	}
@@ -58,85 +87,155 @@ func (s *StreakService) RecordActivity(ctx context.Context, userID primitive.Obj
 	}
 
 	// Record activity in calendar service for GitHub-like visualization
-	if err := s.calendarService.RecordDailyActivity(ctx, userID, activityType, now); err != nil {
+	if err := s.calendarService.RecordDailyActivity(ctx, userID, activityType, now.In(settings.Location())); err != nil {
 		log.Printf("Failed to record daily activity for user %s: %v", userID.Hex(), err)
 		// Don't fail the main operation if calendar recording fails
 	}
 
-	// Update streak only if this is the first activity today
-	if existingActivity == 0 {
-		return s.updateStreak(ctx, userID, today)
+	// Only the first activity of the day changes the streak
+	if err := s.updateStreak(ctx, &user, today); err != nil {
+		return err
 	}
 
+	if manual {
+		return nil
+	}
+
+	// Badges are checked after the streak so streak achievements count today
+	if s.achievementService != nil {
+		if _, err := s.achievementService.EvaluateActivity(ctx, userID, activityType); err != nil {
+			log.Printf("Failed to evaluate achievements for user %s: %v", userID.Hex(), err)
+			// Don't fail the main operation if achievements fail
+		}
+	}
+	if s.xpService != nil {
+		if err := s.xpService.AwardActivity(ctx, userID, activityType); err != nil {
+			log.Printf("Failed to award XP to user %s: %v", userID.Hex(), err)
+		}
+	}
 	return nil
 }
 
-// updateStreak calculates and updates the user's streak
-func (s *StreakService) updateStreak(ctx context.Context, userID primitive.ObjectID, activityDate time.Time) error {
-	var user domain.User
-	err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
-	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
-	}
+// updateStreak calculates and updates the user's streak. activityDate is a
+// day as returned by UserSettings.Day.
+//
+// Days missed since the streak was last kept are first covered with freezes
+// from the inventory; the streak continues if they covered all of them.
+//
+// The user is only updated if the streak fields still hold the values the
+// new streak was calculated from, so concurrent activities, freezes and
+// expiry checks cannot overwrite each other: the loser re-reads the user and
+// tries again, and only one activity per day ever extends the streak.
+func (s *StreakService) updateStreak(ctx context.Context, user *domain.User, activityDate time.Time) error {
+	userID := user.ID
+	yesterday := activityDate.AddDate(0, 0, -1)
+
+	for attempt := 1; ; attempt++ {
+		if !user.LastActivityDate.Before(activityDate) {
+			// Today's activity has already been counted
+			return nil
+		}
+		if _, err := s.coverMissedDays(ctx, user, activityDate); err != nil {
+			return err
+		}
+		lastActivityDate := time.Date(user.LastActivityDate.Year(), user.LastActivityDate.Month(), user.LastActivityDate.Day(), 0, 0, 0, 0, time.UTC)
+		keptThrough := streakKeptThrough(user)
+
+		var newStreak int
+		streakBroken := false
+
+		if user.LastActivityDate.IsZero() {
+			// First activity ever
+			newStreak = 1
+		} else if !keptThrough.Before(yesterday) {
+			// Consecutive day, or the days in between were frozen; a freeze
+			// used for today in advance is simply not needed
+			newStreak = user.CurrentStreak + 1
+		} else {
+			// Streak broken
+			newStreak = 1
+			streakBroken = true
+		}
 
-	yesterday := activityDate.Add(-24 * time.Hour)
-	lastActivityDate := time.Date(user.LastActivityDate.Year(), user.LastActivityDate.Month(), user.LastActivityDate.Day(), 0, 0, 0, 0, time.UTC)
-
-	var newStreak int
-	streakBroken := false
-
-	if user.LastActivityDate.IsZero() {
-		// First activity ever
-		newStreak = 1
-	} else if lastActivityDate.Equal(yesterday) {
-		// Consecutive day
-		newStreak = user.CurrentStreak + 1
-	} else if lastActivityDate.Equal(activityDate) {
-		// Same day (shouldn't happen due to check above, but just in case)
-		newStreak = user.CurrentStreak
-	} else if user.StreakFrozen && lastActivityDate.Before(yesterday) {
-		// Streak was frozen, continue from where we left off
-		newStreak = user.CurrentStreak + 1
-		user.StreakFrozen = false // Unfreeze after activity
-	} else {
-		// Streak broken
-		newStreak = 1
-		streakBroken = true
-		log.Printf("Streak broken for user %s. Last activity: %v, Current activity: %v", userID.Hex(), lastActivityDate, activityDate)
-	}
+		// Update longest streak if current streak is higher
+		longestStreak := user.LongestStreak
+		if newStreak > longestStreak {
+			longestStreak = newStreak
+		}
 
-	// Update longest streak if current streak is higher
-	longestStreak := user.LongestStreak
-	if newStreak > longestStreak {
-		longestStreak = newStreak
-	}
+		result, err := s.userCollection.UpdateOne(ctx, bson.M{
+			"_id":                 userID,
+			"current_streak":      unchangedField(user.CurrentStreak, user.CurrentStreak == 0),
+			"last_activity_date":  unchangedField(user.LastActivityDate, user.LastActivityDate.IsZero()),
+			"streak_frozen_until": unchangedField(user.StreakFrozenUntil, user.StreakFrozenUntil.IsZero()),
+		}, bson.M{
+			"$set": bson.M{
+				"current_streak":     newStreak,
+				"longest_streak":     longestStreak,
+				"last_activity_date": activityDate,
+				"updated_at":         time.Now(),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update user streak: %w", err)
+		}
 
-	// Update user
-	update := bson.M{
-		"$set": bson.M{
-			"current_streak":      newStreak,
-			"longest_streak":      longestStreak,
-			"last_activity_date":  activityDate,
-			"streak_frozen":       false, // Always unfreeze on activity
-			"updated_at":          time.Now(),
-		},
-	}
+		if result.MatchedCount == 0 {
+			// Changed since it was read; start over from the current values
+			if attempt == maxStreakUpdateAttempts {
+				return fmt.Errorf("failed to update user streak: changed concurrently %d times", attempt)
+			}
+			user = &domain.User{}
+			if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(user); err != nil {
+				return fmt.Errorf("failed to find user: %w", err)
+			}
+			continue
+		}
 
-	_, err = s.userCollection.UpdateOne(ctx, bson.M{"_id": userID}, update)
-	if err != nil {
-		return fmt.Errorf("failed to update user streak: %w", err)
-	}
+		// Log streak milestone; each one earns a freeze
+		if newStreak > 0 && newStreak%7 == 0 {
+			log.Printf("🔥 User %s reached %d day streak milestone!", userID.Hex(), newStreak)
+			if _, err := s.grantFreeze(ctx, userID, domain.FreezeSourceMilestone, "milestone:"+activityDate.Format("2006-01-02")); err != nil {
+				log.Printf("Failed to grant milestone streak freeze to user %s: %v", userID.Hex(), err)
+			}
+			s.notifyMilestone(user, newStreak)
+		}
+
+		if streakBroken && user.CurrentStreak > 1 {
+			log.Printf("💔 User %s lost their %d day streak. Last activity: %v, Current activity: %v", userID.Hex(), user.CurrentStreak, lastActivityDate, activityDate)
+		}
 
-	// Log streak milestone
-	if newStreak > 0 && newStreak%7 == 0 {
-		log.Printf("🔥 User %s reached %d day streak milestone!", userID.Hex(), newStreak)
+		return nil
 	}
+}
 
-	if streakBroken && user.CurrentStreak > 1 {
-		log.Printf("💔 User %s lost their %d day streak", userID.Hex(), user.CurrentStreak)
+// unchangedField matches a user field that still holds the value read from
+// it. Zero values also match a missing field, which decodes to zero.
+func unchangedField(value interface{}, isZero bool) interface{} {
+	if isZero {
+		return bson.M{"$in": bson.A{value, nil}}
 	}
+	return value
+}
 
-	return nil
+// notifyMilestone congratulates the user on their devices
+func (s *StreakService) notifyMilestone(user *domain.User, streak int) {
+	if s.pushService == nil {
+		return
+	}
+	message, ok := streakMilestoneMessages[user.CurrentSettings().Language]
+	if !ok {
+		message = streakMilestoneMessages["en"]
+	}
+	err := s.pushService.Notify(user.ID, &PushMessage{
+		Category: domain.PushCategoryStreak,
+		Title:    fmt.Sprintf(message[0], streak),
+		Body:     fmt.Sprintf(message[1], streak),
+		Data:     map[string]string{"streak": fmt.Sprint(streak)},
+	})
+	if err != nil {
+		log.Printf("Failed to queue streak milestone notification for user %s: %v", user.ID.Hex(), err)
+	}
 }
 
 // GetStreakInfo returns the user's current streak information
@@ -147,38 +246,57 @@ func (s *StreakService) GetStreakInfo(ctx context.Context, userID primitive.Obje
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-	lastActivityDate := time.Date(user.LastActivityDate.Year(), user.LastActivityDate.Month(), user.LastActivityDate.Day(), 0, 0, 0, 0, time.UTC)
+	available, err := s.freezeCollection.CountDocuments(ctx, bson.M{
+		"user_id": userID,
+		"used_on": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count streak freezes: %w", err)
+	}
 
-	// Calculate days until streak loss
+	today := user.CurrentSettings().Day(time.Now())
+	keptThrough := streakKeptThrough(&user)
+
+	// Calculate days until streak loss: today if the streak was last kept
+	// yesterday, and one more for every freeze that would cover a missed day
 	daysUntilLoss := 0
-	if !user.LastActivityDate.IsZero() && !user.StreakFrozen {
-		daysSinceActivity := int(today.Sub(lastActivityDate).Hours() / 24)
-		if daysSinceActivity >= 1 {
-			daysUntilLoss = 0 // Already lost or about to lose
-		} else {
-			daysUntilLoss = 1 - daysSinceActivity
+	if !user.LastActivityDate.IsZero() && user.CurrentStreak > 0 {
+		daysSinceKept := int(today.Sub(keptThrough).Hours() / 24)
+		if daysSinceKept < 1 {
+			daysUntilLoss = 1 - daysSinceKept
+		}
+		if daysSinceKept <= 1 {
+			daysUntilLoss += int(available)
 		}
 	}
 
-	// Check if user can freeze (max 2 freezes per month)
-	maxFreezes := 2
-	canFreeze := user.FreezeCount < maxFreezes && user.CurrentStreak > 0 && !user.StreakFrozen
+	// A freeze is keeping the streak alive if it covered a day since the last practice
+	streakFrozen := user.CurrentStreak > 0 && user.StreakFrozenUntil.After(user.LastActivityDate) &&
+		!user.StreakFrozenUntil.Before(today.AddDate(0, 0, -1))
+	var frozenUntil *time.Time
+	if streakFrozen {
+		frozenUntil = &user.StreakFrozenUntil
+	}
+
+	// Today can be frozen in advance unless it is already kept
+	canFreeze := available > 0 && user.CurrentStreak > 0 && keptThrough.Before(today)
 
 	return &domain.StreakInfo{
 		CurrentStreak:    user.CurrentStreak,
 		LongestStreak:    user.LongestStreak,
 		LastActivityDate: user.LastActivityDate,
-		StreakFrozen:     user.StreakFrozen,
-		FreezeCount:      user.FreezeCount,
-		MaxFreezes:       maxFreezes,
+		StreakFrozen:     streakFrozen,
+		FrozenUntil:      frozenUntil,
+		FreezesAvailable: int(available),
+		MaxFreezes:       maxStreakFreezes,
 		CanFreeze:        canFreeze,
 		DaysUntilLoss:    daysUntilLoss,
 	}, nil
 }
 
-// FreezeStreak freezes the user's streak for one day
+// FreezeStreak uses a freeze from the inventory for today, so the streak
+// survives without practice today. Freezes are otherwise used automatically
+// for missed days; this lets the user use one in advance with a reason.
 func (s *StreakService) FreezeStreak(ctx context.Context, userID primitive.ObjectID, reason string) error {
 	var user domain.User
 	err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
@@ -186,65 +304,45 @@ func (s *StreakService) FreezeStreak(ctx context.Context, userID primitive.Objec
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 
-	// Check if user can freeze
-	if user.FreezeCount >= 2 {
-		return fmt.Errorf("maximum freeze limit reached for this month")
+	today := user.CurrentSettings().Day(time.Now())
+	alive, err := s.coverMissedDays(ctx, &user, today)
+	if err != nil {
+		return err
 	}
-
-	if user.CurrentStreak == 0 {
+	if !alive {
 		return fmt.Errorf("cannot freeze a streak of 0 days")
 	}
-
-	if user.StreakFrozen {
-		return fmt.Errorf("streak is already frozen")
+	if !user.LastActivityDate.Before(today) {
+		return fmt.Errorf("already practiced today")
 	}
 
-	// Update user
-	update := bson.M{
-		"$set": bson.M{
-			"streak_frozen": true,
-			"updated_at":    time.Now(),
-		},
-		"$inc": bson.M{
-			"freeze_count": 1,
-		},
+	used, err := s.useFreeze(ctx, &user, today, domain.FreezeUseManual, reason)
+	if errors.Is(err, errDayAlreadyFrozen) {
+		return fmt.Errorf("streak is already frozen")
 	}
-
-	_, err = s.userCollection.UpdateOne(ctx, bson.M{"_id": userID}, update)
 	if err != nil {
-		return fmt.Errorf("failed to freeze streak: %w", err)
+		return err
 	}
-
-	log.Printf("🧊 User %s froze their %d day streak. Reason: %s", userID.Hex(), user.CurrentStreak, reason)
-	return nil
-}
-
-// ResetMonthlyFreezes resets the freeze count for all users (should be called monthly)
-func (s *StreakService) ResetMonthlyFreezes(ctx context.Context) error {
-	result, err := s.userCollection.UpdateMany(ctx, bson.M{}, bson.M{
-		"$set": bson.M{
-			"freeze_count": 0,
-			"updated_at":   time.Now(),
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to reset monthly freezes: %w", err)
+	if !used {
+		return fmt.Errorf("no streak freezes available")
 	}
 
-	log.Printf("🔄 Reset freeze count for %d users", result.ModifiedCount)
+	log.Printf("🧊 User %s froze their %d day streak. Reason: %s", userID.Hex(), user.CurrentStreak, reason)
 	return nil
 }
 
-// CheckAndUpdateExpiredStreaks checks for users whose streaks should be reset due to inactivity
+// CheckAndUpdateExpiredStreaks checks for users whose streaks should be reset
+// due to inactivity: no activity yesterday, in their own time zone.
 func (s *StreakService) CheckAndUpdateExpiredStreaks(ctx context.Context) error {
 	now := time.Now()
-	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+	// No time zone is more than a day ahead of UTC, so anyone whose yesterday
+	// is already over last practiced before today in UTC
+	utcToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
-	// Find users whose streaks should be reset (no activity yesterday and not frozen)
+	// Find users whose streaks may have to be reset
 	cursor, err := s.userCollection.Find(ctx, bson.M{
 		"current_streak": bson.M{"$gt": 0},
-		"last_activity_date": bson.M{"$lt": yesterday},
-		"streak_frozen": false,
+		"last_activity_date": bson.M{"$lt": utcToday},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to find users with expired streaks: %w", err)
@@ -259,8 +357,28 @@ func (s *StreakService) CheckAndUpdateExpiredStreaks(ctx context.Context) error
 			continue
 		}
 
-		// Reset streak to 0
-		_, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{
+		today := user.CurrentSettings().Day(now)
+		if !streakKeptThrough(&user).Before(today.AddDate(0, 0, -1)) {
+			continue
+		}
+
+		// Missed days use up freezes first
+		alive, err := s.coverMissedDays(ctx, &user, today)
+		if err != nil {
+			log.Printf("Error using streak freezes for user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		if alive {
+			continue
+		}
+
+		// Reset streak to 0, unless the user practiced meanwhile
+		result, err := s.userCollection.UpdateOne(ctx, bson.M{
+			"_id":                 user.ID,
+			"current_streak":      user.CurrentStreak,
+			"last_activity_date":  user.LastActivityDate,
+			"streak_frozen_until": unchangedField(user.StreakFrozenUntil, user.StreakFrozenUntil.IsZero()),
+		}, bson.M{
 			"$set": bson.M{
 				"current_streak": 0,
 				"updated_at":     now,
@@ -270,6 +388,9 @@ func (s *StreakService) CheckAndUpdateExpiredStreaks(ctx context.Context) error
 			log.Printf("Error resetting streak for user %s: %v", user.ID.Hex(), err)
 			continue
 		}
+		if result.MatchedCount == 0 {
+			continue
+		}
 
 		log.Printf("💔 Reset expired streak for user %s (was %d days)", user.ID.Hex(), user.CurrentStreak)
 		expiredCount++
@@ -0,0 +1,324 @@
+// internal/service/xp_leagues.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+const (
+	// Users joining a league in a week fill groups of this size in turn
+	leagueGroupSize = 30
+	// At most this many of a group move up and down each week
+	leaguePromotions = 5
+	leagueDemotions  = 5
+)
+
+// leagueWeek returns the ISO week of t in UTC, e.g. "2026-W42". League
+// weeks start on Monday at midnight UTC for everyone.
+func leagueWeek(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// leagueWeekStart returns the Monday midnight UTC that starts t's week.
+func leagueWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func userLeague(user *domain.User) string {
+	if user.League == "" {
+		return domain.Leagues[0]
+	}
+	return user.League
+}
+
+// leagueCutoffs returns the last rank promoted and the first rank demoted in
+// a group of size members, 0 if nobody is. A third of a small group moves
+// each way; the top league has no promotion and the lowest no demotion.
+func leagueCutoffs(league string, size int) (int, int) {
+	index := slices.Index(domain.Leagues, league)
+	promotionRank, demotionRank := 0, 0
+	if index < len(domain.Leagues)-1 {
+		promotionRank = min(leaguePromotions, (size+2)/3)
+	}
+	if demotions := min(leagueDemotions, size/3); index > 0 && demotions > 0 {
+		demotionRank = size - demotions + 1
+	}
+	return promotionRank, demotionRank
+}
+
+// rankEntries orders entries by XP, keeping the given order for ties, and
+// numbers them.
+func rankEntries(entries []domain.LeaderboardEntry) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].XP > entries[j].XP })
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+}
+
+// addLeagueXP adds points to the user's league group for week, joining a
+// group with their first XP of the week.
+func (s *XPService) addLeagueXP(ctx context.Context, user *domain.User, week string, points int, now time.Time) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := s.membershipCollection.UpdateOne(ctx,
+			bson.M{"user_id": user.ID, "week": week},
+			bson.M{"$inc": bson.M{"xp": points}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update league XP: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			return nil
+		}
+
+		// Concurrent joins may overfill a group by a few members, which is harmless
+		league := userLeague(user)
+		group, err := s.openGroup(ctx, week, league)
+		if err != nil {
+			return err
+		}
+		_, err = s.membershipCollection.InsertOne(ctx, domain.LeagueMembership{
+			UserID:   user.ID,
+			Week:     week,
+			League:   league,
+			Group:    group,
+			XP:       points,
+			JoinedAt: now,
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			// Joined by a concurrent award; add the points to that group
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to join league: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to update league XP of user %s", user.ID.Hex())
+}
+
+// openGroup returns the group of a league new members join in week.
+func (s *XPService) openGroup(ctx context.Context, week, league string) (int, error) {
+	members, err := s.membershipCollection.CountDocuments(ctx, bson.M{"week": week, "league": league})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count league members: %w", err)
+	}
+	return int(members) / leagueGroupSize, nil
+}
+
+// GetLeagueLeaderboard returns the user's league group for this week and
+// how they did last week.
+func (s *XPService) GetLeagueLeaderboard(ctx context.Context, userID primitive.ObjectID) (*domain.LeagueLeaderboardResponse, error) {
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1, "league": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	week := leagueWeek(now)
+	response := &domain.LeagueLeaderboardResponse{
+		Week:    week,
+		League:  userLeague(&user),
+		EndsAt:  leagueWeekStart(now).AddDate(0, 0, 7),
+		Entries: []domain.LeaderboardEntry{},
+	}
+
+	var lastWeek domain.LeagueMembership
+	err := s.membershipCollection.FindOne(ctx, bson.M{
+		"user_id": userID,
+		"week":    leagueWeek(now.AddDate(0, 0, -7)),
+		"result":  bson.M{"$exists": true},
+	}).Decode(&lastWeek)
+	if err == nil {
+		response.LastWeek = &lastWeek
+	} else if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to find last week's league: %w", err)
+	}
+
+	if user.CurrentSettings().HideFromLeaderboards {
+		response.HiddenByUser = true
+		return response, nil
+	}
+
+	var membership domain.LeagueMembership
+	err = s.membershipCollection.FindOne(ctx, bson.M{"user_id": userID, "week": week}).Decode(&membership)
+	if err == mongo.ErrNoDocuments {
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find league membership: %w", err)
+	}
+	response.League = membership.League
+
+	members, users, err := s.groupMembers(ctx, week, membership.League, membership.Group)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		response.Entries = append(response.Entries, domain.LeaderboardEntry{
+			UserID: member.UserID.Hex(),
+			Name:   users[member.UserID].Name,
+			XP:     member.XP,
+			IsYou:  member.UserID == userID,
+		})
+	}
+	rankEntries(response.Entries)
+	response.PromotionRank, response.DemotionRank = leagueCutoffs(membership.League, len(response.Entries))
+	return response, nil
+}
+
+// groupMembers returns the members of a league group by XP, leaving out
+// users who have since opted out of leaderboards.
+func (s *XPService) groupMembers(ctx context.Context, week, league string, group int) ([]domain.LeagueMembership, map[primitive.ObjectID]domain.User, error) {
+	cursor, err := s.membershipCollection.Find(ctx,
+		bson.M{"week": week, "league": league, "group": group},
+		options.Find().SetSort(bson.D{{Key: "xp", Value: -1}, {Key: "joined_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find league members: %w", err)
+	}
+	var members []domain.LeagueMembership
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, nil, fmt.Errorf("failed to find league members: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.UserID)
+	}
+	users, err := s.usersByID(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	visible := members[:0]
+	for _, member := range members {
+		if user, ok := users[member.UserID]; ok && !user.CurrentSettings().HideFromLeaderboards {
+			visible = append(visible, member)
+		}
+	}
+	return visible, users, nil
+}
+
+// RolloverLeagues ends last week: the members of each league group are
+// ranked by their XP, the best move up a league and the last move down.
+// A week is only rolled over once, so it is safe to run often.
+func (s *XPService) RolloverLeagues(ctx context.Context) error {
+	now := time.Now()
+	week := leagueWeek(now.AddDate(0, 0, -7))
+	done, err := s.weekCollection.CountDocuments(ctx, bson.M{"_id": week})
+	if err != nil {
+		return fmt.Errorf("failed to check league week: %w", err)
+	}
+	if done > 0 {
+		return nil
+	}
+
+	cursor, err := s.membershipCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"week": week}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"league": "$league", "group": "$group"}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find league groups: %w", err)
+	}
+	var groups []struct {
+		ID struct {
+			League string `bson:"league"`
+			Group  int    `bson:"group"`
+		} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return fmt.Errorf("failed to find league groups: %w", err)
+	}
+
+	for _, group := range groups {
+		if err := s.rolloverGroup(ctx, week, leagueWeek(now), group.ID.League, group.ID.Group); err != nil {
+			// Left for the next run; finished members are not moved again
+			return fmt.Errorf("failed to roll over %s league group %d: %w", group.ID.League, group.ID.Group, err)
+		}
+	}
+
+	_, err = s.weekCollection.InsertOne(ctx, bson.M{"_id": week, "rolled_over_at": now})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to record league week: %w", err)
+	}
+	log.Printf("🏆 Rolled over %d league groups of %s", len(groups), week)
+	return nil
+}
+
+func (s *XPService) rolloverGroup(ctx context.Context, week, currentWeek, league string, group int) error {
+	members, _, err := s.groupMembers(ctx, week, league, group)
+	if err != nil {
+		return err
+	}
+	promotionRank, demotionRank := leagueCutoffs(league, len(members))
+	index := slices.Index(domain.Leagues, league)
+
+	for i, member := range members {
+		if member.Result != "" {
+			// Finished by an interrupted run
+			continue
+		}
+		rank := i + 1
+		result, newLeague := domain.LeagueResultStayed, league
+		switch {
+		case rank <= promotionRank:
+			result, newLeague = domain.LeagueResultPromoted, domain.Leagues[index+1]
+		case demotionRank > 0 && rank >= demotionRank:
+			result, newLeague = domain.LeagueResultDemoted, domain.Leagues[index-1]
+		}
+
+		if newLeague != league {
+			// Only moves a user still in the league, so a rerun never moves them twice
+			_, err := s.userCollection.UpdateOne(ctx,
+				bson.M{"_id": member.UserID, "league": unchangedField(league, index == 0)},
+				bson.M{"$set": bson.M{"league": newLeague}},
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.moveMembership(ctx, member.UserID, currentWeek, league, newLeague); err != nil {
+				return err
+			}
+		}
+
+		_, err := s.membershipCollection.UpdateOne(ctx, bson.M{"_id": member.ID}, bson.M{
+			"$set": bson.M{"rank": rank, "result": result},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveMembership moves a user who already earned XP this week, before the
+// rollover, to a group of their new league.
+func (s *XPService) moveMembership(ctx context.Context, userID primitive.ObjectID, week, from, to string) error {
+	group, err := s.openGroup(ctx, week, to)
+	if err != nil {
+		return err
+	}
+	_, err = s.membershipCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "week": week, "league": from},
+		bson.M{"$set": bson.M{"league": to, "group": group}},
+	)
+	return err
+}
@@ -0,0 +1,357 @@
+// internal/service/xp_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/domain"
+)
+
+// defaultXPWeights are the points per activity type. The score sources are
+// the points for a score of 100%, scaled down for lower scores.
+var defaultXPWeights = map[string]int{
+	"grammar_check":               5,
+	"email_draft":                 5,
+	"mock_interview":              10,
+	"pronunciation_session":       10,
+	"lesson_completed":            20,
+	"quiz_passed":                 10,
+	"daily_goal_met":              10,
+	domain.XPSourceQuizScore:      20,
+	domain.XPSourceInterviewScore: 10,
+}
+
+// xpDailyAwards is how many times a day each activity type earns XP, so
+// repeating an activity cannot farm league XP.
+const xpDailyAwards = 10
+
+// LoadXPWeights returns the default XP weights with the overrides in
+// XP_WEIGHTS applied, e.g. "lesson_completed=30,grammar_check=2". A weight
+// of 0 turns a source off.
+func LoadXPWeights() (map[string]int, error) {
+	weights := make(map[string]int, len(defaultXPWeights))
+	for source, points := range defaultXPWeights {
+		weights[source] = points
+	}
+
+	overrides := strings.TrimSpace(os.Getenv("XP_WEIGHTS"))
+	if overrides == "" {
+		return weights, nil
+	}
+	for _, pair := range strings.Split(overrides, ",") {
+		source, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		points, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || points < 0 {
+			return nil, fmt.Errorf("invalid XP_WEIGHTS entry %q: must be source=points", pair)
+		}
+		weights[strings.TrimSpace(source)] = points
+	}
+	return weights, nil
+}
+
+// XPService keeps the XP ledger and the weekly leagues built from it. Every
+// award is a ledger entry; the user's total and their league group's weekly
+// XP are counters kept alongside.
+type XPService struct {
+	weights              map[string]int
+	userCollection       *mongo.Collection
+	eventCollection      *mongo.Collection
+	membershipCollection *mongo.Collection
+	weekCollection       *mongo.Collection
+	friendService        *FriendService
+}
+
+func NewXPService(db *mongo.Database, weights map[string]int, friendService *FriendService) *XPService {
+	eventCollection := db.Collection("xp_events")
+	_, err := eventCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "week", Value: 1}},
+		},
+		{
+			// A scored quiz or interview question has one entry, holding its
+			// best score, and each daily activity award its own reference
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "source", Value: 1}, {Key: "reference", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"reference": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create xp_events indexes: %v", err)
+	}
+
+	membershipCollection := db.Collection("league_memberships")
+	_, err = membershipCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One group per user and week, however many awards race to join it
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "week", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "week", Value: 1}, {Key: "league", Value: 1}, {Key: "group", Value: 1}, {Key: "xp", Value: -1}},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create league_memberships indexes: %v", err)
+	}
+
+	return &XPService{
+		weights:              weights,
+		userCollection:       db.Collection("users"),
+		eventCollection:      eventCollection,
+		membershipCollection: membershipCollection,
+		weekCollection:       db.Collection("league_weeks"),
+		friendService:        friendService,
+	}
+}
+
+// AwardActivity awards the XP weight of an activity type, up to
+// xpDailyAwards times a day in the user's time zone.
+func (s *XPService) AwardActivity(ctx context.Context, userID primitive.ObjectID, activityType string) error {
+	return s.award(ctx, userID, activityType, s.weights[activityType])
+}
+
+// AwardScore awards the weight of source scaled by a score in percent, for
+// quizzes and interview answers. reference identifies what was scored and
+// earns XP once, for its best score: a better score later raises that
+// ledger entry, and the totals, by the difference.
+func (s *XPService) AwardScore(ctx context.Context, userID primitive.ObjectID, source string, percent float64, reference string) error {
+	percent = math.Max(0, math.Min(100, percent))
+	points := int(math.Round(float64(s.weights[source]) * percent / 100))
+	if points <= 0 {
+		return nil
+	}
+
+	user, err := s.awardee(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var before domain.XPEvent
+	for tries := 1; ; tries++ {
+		// Returns the entry as it was, so concurrent awards each see the
+		// best score they improved on
+		err = s.eventCollection.FindOneAndUpdate(ctx,
+			bson.M{"user_id": userID, "source": source, "reference": reference},
+			bson.M{
+				"$max":         bson.M{"points": points},
+				"$setOnInsert": bson.M{"week": leagueWeek(now), "created_at": now},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+		).Decode(&before)
+		if mongo.IsDuplicateKeyError(err) && tries < 3 {
+			continue
+		}
+		break
+	}
+	switch {
+	case err == mongo.ErrNoDocuments:
+		return s.credit(ctx, user, leagueWeek(now), points, now)
+	case err != nil:
+		return fmt.Errorf("failed to record XP: %w", err)
+	case points > before.Points:
+		return s.credit(ctx, user, before.Week, points-before.Points, now)
+	default:
+		return nil
+	}
+}
+
+func (s *XPService) award(ctx context.Context, userID primitive.ObjectID, source string, points int) error {
+	if points <= 0 {
+		return nil
+	}
+
+	user, err := s.awardee(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Each award takes one of the day's numbered references; the unique
+	// reference index makes concurrent awards take different ones
+	now := time.Now()
+	week := leagueWeek(now)
+	day := user.CurrentSettings().Day(now).Format("2006-01-02")
+	taken, err := s.eventCollection.CountDocuments(ctx, bson.M{
+		"user_id":   userID,
+		"source":    source,
+		"reference": bson.M{"$regex": "^" + day + "/"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count XP awards: %w", err)
+	}
+	for slot := int(taken); slot < xpDailyAwards; slot++ {
+		_, err = s.eventCollection.InsertOne(ctx, domain.XPEvent{
+			UserID:    userID,
+			Source:    source,
+			Points:    points,
+			Reference: fmt.Sprintf("%s/%d", day, slot),
+			Week:      week,
+			CreatedAt: now,
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record XP: %w", err)
+		}
+		return s.credit(ctx, user, week, points, now)
+	}
+	// The day's XP for this activity is used up
+	return nil
+}
+
+// awardee loads what awarding XP needs to know about the user.
+func (s *XPService) awardee(ctx context.Context, userID primitive.ObjectID) (*domain.User, error) {
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1, "league": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return &user, nil
+}
+
+// credit adds points recorded in the ledger for week to the user's total
+// and, while week is running, to their league group.
+func (s *XPService) credit(ctx context.Context, user *domain.User, week string, points int, now time.Time) error {
+	_, err := s.userCollection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$inc": bson.M{"xp": points}})
+	if err != nil {
+		return fmt.Errorf("failed to update XP total: %w", err)
+	}
+
+	// Past weeks are settled
+	if user.CurrentSettings().HideFromLeaderboards || week != leagueWeek(now) {
+		return nil
+	}
+	return s.addLeagueXP(ctx, user, week, points, now)
+}
+
+// GetXPSummary returns the user's XP total, this week's XP and their most
+// recent awards.
+func (s *XPService) GetXPSummary(ctx context.Context, userID primitive.ObjectID) (*domain.XPSummaryResponse, error) {
+	var user domain.User
+	opts := options.FindOne().SetProjection(bson.M{"xp": 1, "league": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+
+	week := leagueWeek(time.Now())
+	weekly, err := s.weeklyXP(ctx, []primitive.ObjectID{userID}, week)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.eventCollection.Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find XP events: %w", err)
+	}
+	recent := []domain.XPEvent{}
+	if err := cursor.All(ctx, &recent); err != nil {
+		return nil, fmt.Errorf("failed to find XP events: %w", err)
+	}
+
+	return &domain.XPSummaryResponse{
+		Total:    user.XP,
+		ThisWeek: weekly[userID],
+		League:   userLeague(&user),
+		Week:     week,
+		Recent:   recent,
+	}, nil
+}
+
+// GetFriendsLeaderboard ranks the user and their friends by this week's XP.
+// Friends who opted out of leaderboards are left out.
+func (s *XPService) GetFriendsLeaderboard(ctx context.Context, userID primitive.ObjectID) (*domain.FriendsLeaderboardResponse, error) {
+	friendIDs, err := s.friendService.FriendIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.usersByID(ctx, append(friendIDs, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []primitive.ObjectID
+	for id, user := range users {
+		if id == userID || !user.CurrentSettings().HideFromLeaderboards {
+			ids = append(ids, id)
+		}
+	}
+	week := leagueWeek(time.Now())
+	weekly, err := s.weeklyXP(ctx, ids, week)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.LeaderboardEntry, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, domain.LeaderboardEntry{
+			UserID: id.Hex(),
+			Name:   users[id].Name,
+			XP:     weekly[id],
+			IsYou:  id == userID,
+		})
+	}
+	// Ties are listed by name
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	rankEntries(entries)
+	return &domain.FriendsLeaderboardResponse{Week: week, Entries: entries}, nil
+}
+
+// weeklyXP sums the XP each user earned in week.
+func (s *XPService) weeklyXP(ctx context.Context, userIDs []primitive.ObjectID, week string) (map[primitive.ObjectID]int, error) {
+	cursor, err := s.eventCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": bson.M{"$in": userIDs}, "week": week}}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "xp": bson.M{"$sum": "$points"}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum weekly XP: %w", err)
+	}
+	var results []struct {
+		UserID primitive.ObjectID `bson:"_id"`
+		XP     int                `bson:"xp"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to sum weekly XP: %w", err)
+	}
+
+	weekly := make(map[primitive.ObjectID]int, len(results))
+	for _, result := range results {
+		weekly[result.UserID] = result.XP
+	}
+	return weekly, nil
+}
+
+// usersByID loads the names and settings of users.
+func (s *XPService) usersByID(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]domain.User, error) {
+	cursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"name": 1, "settings": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	var users []domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+
+	byID := make(map[primitive.ObjectID]domain.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	return byID, nil
+}
@@ -0,0 +1,143 @@
+// internal/usecase/account_usecase.go
+package usecase
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+// Accounts purged per PurgeDueDeletions run; the rest wait for the next run.
+const deletionPurgeBatch = 100
+
+// AccountUsecase covers the user's rights over their data: exporting it and
+// deleting the account together with everything stored about it.
+type AccountUsecase interface {
+	ExportData(userID primitive.ObjectID) (*domain.UserDataExport, error)
+	// DeleteAccount deletes the account right away, or schedules the deletion
+	// when a grace period is configured.
+	DeleteAccount(userID primitive.ObjectID) (*domain.AccountDeletionResponse, error)
+	RestoreAccount(userID primitive.ObjectID) error
+	// PurgeDueDeletions deletes accounts whose grace period has ended.
+	PurgeDueDeletions() (int, error)
+}
+
+type accountUsecase struct {
+	userRepo         repository.UserRepository
+	userDataRepo     repository.UserDataRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessions         *sessionRevoker
+	gracePeriod      time.Duration
+}
+
+func NewAccountUsecase(
+	userRepo repository.UserRepository,
+	userDataRepo repository.UserDataRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	gracePeriod time.Duration,
+) AccountUsecase {
+	return &accountUsecase{
+		userRepo:         userRepo,
+		userDataRepo:     userDataRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessions:         newSessionRevoker(userRepo, refreshTokenRepo, sessionRepo),
+		gracePeriod:      gracePeriod,
+	}
+}
+
+func (u *accountUsecase) ExportData(userID primitive.ObjectID) (*domain.UserDataExport, error) {
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	collections, err := u.userDataRepo.ExportUserData(userID)
+	if err != nil {
+		log.Printf("Failed to export data of user %s: %v", userID.Hex(), err)
+		return nil, errors.New("failed to export data")
+	}
+
+	return &domain.UserDataExport{
+		UserID:      userID,
+		ExportedAt:  time.Now().UTC(),
+		Collections: collections,
+	}, nil
+}
+
+func (u *accountUsecase) DeleteAccount(userID primitive.ObjectID) (*domain.AccountDeletionResponse, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if u.gracePeriod <= 0 {
+		if err := u.purge(user); err != nil {
+			return nil, err
+		}
+		return &domain.AccountDeletionResponse{Message: "Account deleted successfully"}, nil
+	}
+
+	// Asking again during the grace period keeps the original date
+	if user.DeletionScheduledFor == nil {
+		scheduledFor := time.Now().Add(u.gracePeriod).UTC()
+		if err := u.userRepo.ScheduleDeletion(userID, scheduledFor); err != nil {
+			return nil, errors.New("failed to schedule account deletion")
+		}
+		user.DeletionScheduledFor = &scheduledFor
+	}
+
+	// Log out everywhere; logging back in during the grace period is what allows a restore
+	if err := u.sessions.revokeAll(userID, primitive.NilObjectID); err != nil {
+		log.Printf("Failed to revoke sessions of user %s pending deletion: %v", userID.Hex(), err)
+	}
+	if err := u.refreshTokenRepo.DeleteUserRefreshTokens(userID); err != nil {
+		log.Printf("Failed to delete refresh tokens of user %s pending deletion: %v", userID.Hex(), err)
+	}
+
+	return &domain.AccountDeletionResponse{
+		Message:              "Account scheduled for deletion",
+		DeletionScheduledFor: user.DeletionScheduledFor,
+	}, nil
+}
+
+func (u *accountUsecase) RestoreAccount(userID primitive.ObjectID) error {
+	restored, err := u.userRepo.CancelDeletion(userID)
+	if err != nil {
+		return errors.New("failed to restore account")
+	}
+	if !restored {
+		return errors.New("account is not scheduled for deletion")
+	}
+	return nil
+}
+
+func (u *accountUsecase) PurgeDueDeletions() (int, error) {
+	users, err := u.userRepo.GetUsersDueForDeletion(time.Now(), deletionPurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := u.purge(user); err != nil {
+			// Left scheduled, so the next run tries again
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (u *accountUsecase) purge(user *domain.User) error {
+	deleted, err := u.userDataRepo.DeleteUserData(user.ID, user.Email)
+	if err != nil {
+		log.Printf("Failed to delete data of user %s: %v", user.ID.Hex(), err)
+		return errors.New("failed to delete account")
+	}
+	log.Printf("Deleted account %s: %v", user.ID.Hex(), deleted)
+	return nil
+}
@@ -0,0 +1,635 @@
+// internal/usecase/admin_usecase.go
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+// Audit log actions
+const (
+	AuditUserSearch         = "user.search"
+	AuditUserSuspend        = "user.suspend"
+	AuditUserUnsuspend      = "user.unsuspend"
+	AuditUserRolesUpdate    = "user.roles.update"
+	AuditLearningPathCreate = "learning_path.create"
+	AuditLearningPathUpdate = "learning_path.update"
+	AuditLearningPathDelete = "learning_path.delete"
+	AuditLessonCreate       = "lesson.create"
+	AuditLessonUpdate       = "lesson.update"
+	AuditLessonDelete       = "lesson.delete"
+	AuditQuizCreate         = "quiz.create"
+	AuditQuizUpdate         = "quiz.update"
+	AuditQuizDelete         = "quiz.delete"
+	AuditLogList            = "audit_log.list"
+	AuditJobList            = "job.list"
+	AuditJobRun             = "job.run"
+	AuditPromptList         = "prompt.list"
+	AuditPromptCreate       = "prompt.template.create"
+	AuditPromptRollout      = "prompt.rollout.update"
+)
+
+// JobScheduler runs the scheduled background jobs; see jobs.Scheduler.
+type JobScheduler interface {
+	Jobs() ([]*domain.ScheduledJob, error)
+	Trigger(name, triggeredBy string) (*domain.JobRun, error)
+}
+
+// PromptManager edits the AI prompts; see service.PromptRegistry.
+type PromptManager interface {
+	Prompts() []*domain.Prompt
+	AddTemplate(ctx context.Context, name string, req *domain.PromptTemplateRequest) (*domain.PromptTemplate, error)
+	SetRollout(ctx context.Context, name string, req *domain.PromptRolloutRequest) (*domain.PromptRollout, error)
+}
+
+// AdminUsecase covers user administration and content management. Every
+// method records what the actor does in the audit log before doing it, and
+// refuses to act if the entry cannot be written.
+type AdminUsecase interface {
+	// Users
+	SearchUsers(actor domain.AuditActor, filter domain.UserSearchFilter, page, limit int) (*domain.UserSearchResponse, error)
+	SuspendUser(actor domain.AuditActor, userID string, req *domain.SuspendUserRequest) error
+	UnsuspendUser(actor domain.AuditActor, userID string) error
+	UpdateUserRoles(actor domain.AuditActor, userID string, req *domain.UpdateRolesRequest) error
+
+	// Content
+	CreateLearningPath(actor domain.AuditActor, req *domain.LearningPathRequest) (*domain.LearningPath, error)
+	UpdateLearningPath(actor domain.AuditActor, pathID string, req *domain.LearningPathRequest) (*domain.LearningPath, error)
+	DeleteLearningPath(actor domain.AuditActor, pathID string) error
+	CreateLesson(actor domain.AuditActor, req *domain.LessonRequest) (*domain.Lesson, error)
+	UpdateLesson(actor domain.AuditActor, lessonID string, req *domain.LessonRequest) (*domain.Lesson, error)
+	DeleteLesson(actor domain.AuditActor, lessonID string) error
+	CreateQuiz(actor domain.AuditActor, req *domain.QuizRequest) (*domain.Quiz, error)
+	UpdateQuiz(actor domain.AuditActor, quizID string, req *domain.QuizRequest) (*domain.Quiz, error)
+	DeleteQuiz(actor domain.AuditActor, quizID string) error
+
+	// Audit log
+	ListAuditLogs(actor domain.AuditActor, filter domain.AuditLogFilter, page, limit int) (*domain.AuditLogListResponse, error)
+
+	// Scheduled jobs
+	ListJobs(actor domain.AuditActor) ([]*domain.ScheduledJob, error)
+	RunJob(actor domain.AuditActor, name string) (*domain.JobRun, error)
+
+	// AI prompts
+	ListPrompts(actor domain.AuditActor) ([]*domain.Prompt, error)
+	CreatePromptTemplate(actor domain.AuditActor, name string, req *domain.PromptTemplateRequest) (*domain.PromptTemplate, error)
+	UpdatePromptRollout(actor domain.AuditActor, name string, req *domain.PromptRolloutRequest) (*domain.PromptRollout, error)
+}
+
+type adminUsecase struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	learningRepo     repository.LearningRepository
+	auditLogRepo     repository.AuditLogRepository
+	scheduler        JobScheduler
+	prompts          PromptManager
+	sessions         *sessionRevoker
+}
+
+func NewAdminUsecase(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	learningRepo repository.LearningRepository,
+	auditLogRepo repository.AuditLogRepository,
+	scheduler JobScheduler,
+	prompts PromptManager,
+) AdminUsecase {
+	return &adminUsecase{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		learningRepo:     learningRepo,
+		auditLogRepo:     auditLogRepo,
+		scheduler:        scheduler,
+		prompts:          prompts,
+		sessions:         newSessionRevoker(userRepo, refreshTokenRepo, sessionRepo),
+	}
+}
+
+// --- Users ---
+
+func (u *adminUsecase) SearchUsers(actor domain.AuditActor, filter domain.UserSearchFilter, page, limit int) (*domain.UserSearchResponse, error) {
+	if filter.Role != "" && !domain.IsValidRole(filter.Role) {
+		return nil, errors.New("invalid role")
+	}
+	page, limit = normalizePage(page, limit)
+
+	details := map[string]interface{}{"query": filter.Query, "role": filter.Role, "page": page}
+	if filter.Suspended != nil {
+		details["suspended"] = *filter.Suspended
+	}
+	var users []*domain.User
+	var total int64
+	err := u.audited(actor, AuditUserSearch, "user", "", details, func() (err error) {
+		users, total, err = u.userRepo.SearchUsers(filter, int64((page-1)*limit), int64(limit))
+		if err != nil {
+			return errors.New("failed to search users")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []*domain.User{}
+	}
+
+	return &domain.UserSearchResponse{Users: users, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (u *adminUsecase) SuspendUser(actor domain.AuditActor, userID string, req *domain.SuspendUserRequest) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if id == actor.UserID {
+		return errors.New("cannot suspend your own account")
+	}
+
+	err = u.audited(actor, AuditUserSuspend, "user", userID, map[string]interface{}{"reason": req.Reason}, func() error {
+		if err := u.userRepo.SetUserSuspended(id, true, req.Reason); err != nil {
+			if err.Error() == "user not found" {
+				return err
+			}
+			return errors.New("failed to suspend user")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Log the user out everywhere; suspended users cannot log in or refresh
+	if err := u.sessions.revokeAll(id, primitive.NilObjectID); err != nil {
+		log.Printf("Failed to revoke sessions of suspended user %s: %v", userID, err)
+	}
+	if err := u.refreshTokenRepo.DeleteUserRefreshTokens(id); err != nil {
+		log.Printf("Failed to delete refresh tokens of suspended user %s: %v", userID, err)
+	}
+	return nil
+}
+
+func (u *adminUsecase) UnsuspendUser(actor domain.AuditActor, userID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	return u.audited(actor, AuditUserUnsuspend, "user", userID, nil, func() error {
+		if err := u.userRepo.SetUserSuspended(id, false, ""); err != nil {
+			if err.Error() == "user not found" {
+				return err
+			}
+			return errors.New("failed to unsuspend user")
+		}
+		return nil
+	})
+}
+
+func (u *adminUsecase) UpdateUserRoles(actor domain.AuditActor, userID string, req *domain.UpdateRolesRequest) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if id == actor.UserID && !slices.Contains(req.Roles, domain.RoleAdmin) {
+		return errors.New("cannot remove your own admin role")
+	}
+
+	user, err := u.userRepo.GetUserByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	previous := user.EffectiveRoles()
+
+	roles := make([]string, 0, len(req.Roles))
+	for _, role := range req.Roles {
+		if !domain.IsValidRole(role) {
+			return errors.New("invalid role")
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return u.audited(actor, AuditUserRolesUpdate, "user", userID, map[string]interface{}{"previous": previous, "roles": roles}, func() error {
+		if err := u.userRepo.UpdateUserRoles(id, roles); err != nil {
+			return errors.New("failed to update roles")
+		}
+		return nil
+	})
+}
+
+// --- Content ---
+
+func (u *adminUsecase) CreateLearningPath(actor domain.AuditActor, req *domain.LearningPathRequest) (*domain.LearningPath, error) {
+	path := &domain.LearningPath{ID: primitive.NewObjectID()}
+	applyLearningPathRequest(path, req)
+
+	err := u.audited(actor, AuditLearningPathCreate, "learning_path", path.ID.Hex(), map[string]interface{}{"title": path.Title}, func() error {
+		if err := u.learningRepo.CreateLearningPath(path); err != nil {
+			return errors.New("failed to create learning path")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+func (u *adminUsecase) UpdateLearningPath(actor domain.AuditActor, pathID string, req *domain.LearningPathRequest) (*domain.LearningPath, error) {
+	id, err := primitive.ObjectIDFromHex(pathID)
+	if err != nil {
+		return nil, errors.New("learning path not found")
+	}
+	path, err := u.learningRepo.GetLearningPathByID(id)
+	if err != nil {
+		return nil, errors.New("learning path not found")
+	}
+
+	applyLearningPathRequest(path, req)
+	err = u.audited(actor, AuditLearningPathUpdate, "learning_path", pathID, map[string]interface{}{"title": path.Title}, func() error {
+		if err := u.learningRepo.UpdateLearningPath(path); err != nil {
+			return errors.New("failed to update learning path")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+func (u *adminUsecase) DeleteLearningPath(actor domain.AuditActor, pathID string) error {
+	id, err := primitive.ObjectIDFromHex(pathID)
+	if err != nil {
+		return errors.New("learning path not found")
+	}
+	path, err := u.learningRepo.GetLearningPathByID(id)
+	if err != nil {
+		return errors.New("learning path not found")
+	}
+
+	lessons, err := u.learningRepo.GetLessonsByPathID(id)
+	if err != nil {
+		return errors.New("failed to delete learning path")
+	}
+	if len(lessons) > 0 {
+		return errors.New("learning path still has lessons")
+	}
+
+	return u.audited(actor, AuditLearningPathDelete, "learning_path", pathID, map[string]interface{}{"title": path.Title}, func() error {
+		if err := u.learningRepo.DeleteLearningPath(id); err != nil {
+			return errors.New("failed to delete learning path")
+		}
+		return nil
+	})
+}
+
+func (u *adminUsecase) CreateLesson(actor domain.AuditActor, req *domain.LessonRequest) (*domain.Lesson, error) {
+	lesson := &domain.Lesson{ID: primitive.NewObjectID()}
+	if err := u.applyLessonRequest(lesson, req); err != nil {
+		return nil, err
+	}
+
+	err := u.audited(actor, AuditLessonCreate, "lesson", lesson.ID.Hex(), map[string]interface{}{"title": lesson.Title, "path_id": req.PathID}, func() error {
+		if err := u.learningRepo.CreateLesson(lesson); err != nil {
+			return errors.New("failed to create lesson")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+func (u *adminUsecase) UpdateLesson(actor domain.AuditActor, lessonID string, req *domain.LessonRequest) (*domain.Lesson, error) {
+	id, err := primitive.ObjectIDFromHex(lessonID)
+	if err != nil {
+		return nil, errors.New("lesson not found")
+	}
+	lesson, err := u.learningRepo.GetLessonByID(id)
+	if err != nil {
+		return nil, errors.New("lesson not found")
+	}
+
+	if err := u.applyLessonRequest(lesson, req); err != nil {
+		return nil, err
+	}
+	err = u.audited(actor, AuditLessonUpdate, "lesson", lessonID, map[string]interface{}{"title": lesson.Title, "path_id": req.PathID}, func() error {
+		if err := u.learningRepo.UpdateLesson(lesson); err != nil {
+			return errors.New("failed to update lesson")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+func (u *adminUsecase) DeleteLesson(actor domain.AuditActor, lessonID string) error {
+	id, err := primitive.ObjectIDFromHex(lessonID)
+	if err != nil {
+		return errors.New("lesson not found")
+	}
+	lesson, err := u.learningRepo.GetLessonByID(id)
+	if err != nil {
+		return errors.New("lesson not found")
+	}
+
+	return u.audited(actor, AuditLessonDelete, "lesson", lessonID, map[string]interface{}{"title": lesson.Title, "path_id": lesson.PathID.Hex()}, func() error {
+		if err := u.learningRepo.DeleteLesson(id); err != nil {
+			return errors.New("failed to delete lesson")
+		}
+		return nil
+	})
+}
+
+func (u *adminUsecase) CreateQuiz(actor domain.AuditActor, req *domain.QuizRequest) (*domain.Quiz, error) {
+	lessonID, err := primitive.ObjectIDFromHex(req.LessonID)
+	if err != nil {
+		return nil, errors.New("lesson not found")
+	}
+	if _, err := u.learningRepo.GetLessonByID(lessonID); err != nil {
+		return nil, errors.New("lesson not found")
+	}
+	existing, err := u.learningRepo.GetQuizByLessonID(lessonID)
+	if err != nil {
+		return nil, errors.New("failed to create quiz")
+	}
+	if existing != nil {
+		return nil, errors.New("lesson already has a quiz")
+	}
+
+	quiz := &domain.Quiz{ID: primitive.NewObjectID(), LessonID: lessonID}
+	applyQuizRequest(quiz, req)
+	err = u.audited(actor, AuditQuizCreate, "quiz", quiz.ID.Hex(), map[string]interface{}{"title": quiz.Title, "lesson_id": req.LessonID}, func() error {
+		if err := u.learningRepo.CreateQuiz(quiz); err != nil {
+			return errors.New("failed to create quiz")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (u *adminUsecase) UpdateQuiz(actor domain.AuditActor, quizID string, req *domain.QuizRequest) (*domain.Quiz, error) {
+	id, err := primitive.ObjectIDFromHex(quizID)
+	if err != nil {
+		return nil, errors.New("quiz not found")
+	}
+	quiz, err := u.learningRepo.GetQuizByID(id)
+	if err != nil {
+		return nil, errors.New("quiz not found")
+	}
+	if req.LessonID != quiz.LessonID.Hex() {
+		return nil, errors.New("a quiz cannot be moved to another lesson")
+	}
+
+	applyQuizRequest(quiz, req)
+	err = u.audited(actor, AuditQuizUpdate, "quiz", quizID, map[string]interface{}{"title": quiz.Title, "questions": len(quiz.Questions)}, func() error {
+		if err := u.learningRepo.UpdateQuiz(quiz); err != nil {
+			return errors.New("failed to update quiz")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (u *adminUsecase) DeleteQuiz(actor domain.AuditActor, quizID string) error {
+	id, err := primitive.ObjectIDFromHex(quizID)
+	if err != nil {
+		return errors.New("quiz not found")
+	}
+	quiz, err := u.learningRepo.GetQuizByID(id)
+	if err != nil {
+		return errors.New("quiz not found")
+	}
+
+	return u.audited(actor, AuditQuizDelete, "quiz", quizID, map[string]interface{}{"title": quiz.Title, "lesson_id": quiz.LessonID.Hex()}, func() error {
+		if err := u.learningRepo.DeleteQuiz(id); err != nil {
+			return errors.New("failed to delete quiz")
+		}
+		return nil
+	})
+}
+
+// --- Audit log ---
+
+func (u *adminUsecase) ListAuditLogs(actor domain.AuditActor, filter domain.AuditLogFilter, page, limit int) (*domain.AuditLogListResponse, error) {
+	page, limit = normalizePage(page, limit)
+
+	var logs []*domain.AuditLog
+	var total int64
+	err := u.audited(actor, AuditLogList, "audit_log", "", map[string]interface{}{"action": filter.Action, "target_id": filter.TargetID, "page": page}, func() (err error) {
+		logs, total, err = u.auditLogRepo.GetAuditLogs(filter, int64((page-1)*limit), int64(limit))
+		if err != nil {
+			return errors.New("failed to fetch audit logs")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []*domain.AuditLog{}
+	}
+
+	return &domain.AuditLogListResponse{Logs: logs, Total: total, Page: page, Limit: limit}, nil
+}
+
+// --- Scheduled jobs ---
+
+func (u *adminUsecase) ListJobs(actor domain.AuditActor) ([]*domain.ScheduledJob, error) {
+	var jobs []*domain.ScheduledJob
+	err := u.audited(actor, AuditJobList, "job", "", nil, func() (err error) {
+		jobs, err = u.scheduler.Jobs()
+		if err != nil {
+			return errors.New("failed to fetch jobs")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (u *adminUsecase) RunJob(actor domain.AuditActor, name string) (*domain.JobRun, error) {
+	// The run records the actor as triggered_by
+	var run *domain.JobRun
+	err := u.audited(actor, AuditJobRun, "job", name, nil, func() (err error) {
+		run, err = u.scheduler.Trigger(name, actor.UserID.Hex())
+		if err != nil {
+			if err.Error() == "job not found" || err.Error() == "job is already running" {
+				return err
+			}
+			return errors.New("failed to start job")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// --- AI prompts ---
+
+func (u *adminUsecase) ListPrompts(actor domain.AuditActor) ([]*domain.Prompt, error) {
+	var prompts []*domain.Prompt
+	err := u.audited(actor, AuditPromptList, "prompt", "", nil, func() error {
+		prompts = u.prompts.Prompts()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prompts, nil
+}
+
+func (u *adminUsecase) CreatePromptTemplate(actor domain.AuditActor, name string, req *domain.PromptTemplateRequest) (*domain.PromptTemplate, error) {
+	// Version 0 asks for the next version
+	var template *domain.PromptTemplate
+	err := u.audited(actor, AuditPromptCreate, "prompt", name, map[string]interface{}{
+		"version": req.Version,
+		"locale":  req.Locale,
+	}, func() (err error) {
+		template, err = u.prompts.AddTemplate(context.Background(), name, req)
+		if err != nil {
+			return promptError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (u *adminUsecase) UpdatePromptRollout(actor domain.AuditActor, name string, req *domain.PromptRolloutRequest) (*domain.PromptRollout, error) {
+	details := map[string]interface{}{"version": req.Version}
+	if req.Experiment != nil {
+		details["experiment"] = req.Experiment.ID
+		details["variants"] = req.Experiment.Variants
+	}
+	var rollout *domain.PromptRollout
+	err := u.audited(actor, AuditPromptRollout, "prompt", name, details, func() (err error) {
+		rollout, err = u.prompts.SetRollout(context.Background(), name, req)
+		if err != nil {
+			return promptError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// promptError passes on the errors an editor can fix and hides the rest.
+func promptError(err error) error {
+	var invalid *domain.PromptValidationError
+	if errors.As(err, &invalid) {
+		return err
+	}
+	switch err.Error() {
+	case "prompt not found", "prompt version not found", "prompt version already exists":
+		return err
+	}
+	log.Printf("Failed to save prompt: %v", err)
+	return errors.New("failed to save prompt")
+}
+
+// --- helpers ---
+
+// audited records an admin action in the audit log, then applies it. The
+// action is refused if the entry cannot be written, and the entry is marked
+// failed if the action fails, so the log never misses an applied action.
+func (u *adminUsecase) audited(actor domain.AuditActor, action, targetType, targetID string, details map[string]interface{}, apply func() error) error {
+	entry := &domain.AuditLog{
+		ActorID:    actor.UserID,
+		ActorIP:    actor.IPAddress,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	}
+	if err := u.auditLogRepo.CreateAuditLog(entry); err != nil {
+		log.Printf("Failed to write audit log %s by %s on %s: %v", action, actor.UserID.Hex(), targetID, err)
+		return errors.New("failed to record the action in the audit log")
+	}
+
+	if err := apply(); err != nil {
+		if markErr := u.auditLogRepo.MarkAuditLogFailed(entry.ID); markErr != nil {
+			log.Printf("Failed to mark audit log %s as failed: %v", entry.ID.Hex(), markErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (u *adminUsecase) applyLessonRequest(lesson *domain.Lesson, req *domain.LessonRequest) error {
+	pathID, err := primitive.ObjectIDFromHex(req.PathID)
+	if err != nil {
+		return errors.New("learning path not found")
+	}
+	if _, err := u.learningRepo.GetLearningPathByID(pathID); err != nil {
+		return errors.New("learning path not found")
+	}
+
+	lesson.PathID = pathID
+	lesson.Title = req.Title
+	lesson.Description = req.Description
+	lesson.Content = req.Content
+	lesson.Type = req.Type
+	lesson.Duration = req.Duration
+	lesson.Order = req.Order
+	return nil
+}
+
+func applyLearningPathRequest(path *domain.LearningPath, req *domain.LearningPathRequest) {
+	path.Title = req.Title
+	path.Description = req.Description
+	path.Level = req.Level
+	path.Category = req.Category
+	path.Duration = req.Duration
+}
+
+func applyQuizRequest(quiz *domain.Quiz, req *domain.QuizRequest) {
+	quiz.Title = req.Title
+	quiz.Questions = req.Questions
+	for i := range quiz.Questions {
+		if quiz.Questions[i].ID == "" {
+			quiz.Questions[i].ID = uuid.New().String()
+		}
+	}
+}
+
+// normalizePage clamps pagination parameters to page >= 1 and 1 <= limit <= 100.
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
@@ -0,0 +1,190 @@
+// internal/usecase/attempt_limiter.go
+package usecase
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/repository"
+)
+
+// AttemptPolicy describes how failures for one kind of key are throttled.
+// The first FreeAttempts attempts are not delayed; after that every failure
+// makes the caller wait BaseDelay, doubling up to MaxDelay, before trying again.
+// After LockoutAfter failures the key is locked for LockoutDuration.
+// Failures older than Window are forgotten.
+type AttemptPolicy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// delay is how long to wait after the given number of failures.
+func (p AttemptPolicy) delay(failures int) time.Duration {
+	if failures < p.FreeAttempts || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// ThrottledError is returned when a key is cooling down or locked out.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Locked {
+		return "too many failed attempts, account temporarily locked"
+	}
+	return "too many attempts, please try again later"
+}
+
+// AttemptLimiter applies an AttemptPolicy to counters kept in an AttemptRepository.
+// Storage errors are logged and let the request through, so an unavailable
+// store degrades protection rather than blocking every login.
+type AttemptLimiter struct {
+	store  repository.AttemptRepository
+	policy AttemptPolicy
+	now    func() time.Time
+}
+
+func NewAttemptLimiter(store repository.AttemptRepository, policy AttemptPolicy) *AttemptLimiter {
+	return NewAttemptLimiterWithClock(store, policy, time.Now)
+}
+
+// NewAttemptLimiterWithClock is NewAttemptLimiter with a controllable clock.
+func NewAttemptLimiterWithClock(store repository.AttemptRepository, policy AttemptPolicy, now func() time.Time) *AttemptLimiter {
+	return &AttemptLimiter{store: store, policy: policy, now: now}
+}
+
+// Check returns a *ThrottledError if the key may not be tried right now.
+func (l *AttemptLimiter) Check(key string) error {
+	if key == "" {
+		return nil
+	}
+	attempt, err := l.store.GetAttempts(key)
+	if err != nil {
+		log.Printf("Failed to read attempts for %s: %v", key, err)
+		return nil
+	}
+	if attempt == nil {
+		return nil
+	}
+
+	now := l.now()
+	if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+		return &ThrottledError{RetryAfter: attempt.LockedUntil.Sub(now), Locked: true}
+	}
+	if now.Sub(attempt.LastFailureAt) > l.policy.Window {
+		return nil
+	}
+	if wait := attempt.LastFailureAt.Add(l.policy.delay(attempt.Failures)).Sub(now); wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Fail records a failure and reports whether it locked the key, and until when.
+func (l *AttemptLimiter) Fail(key string) (bool, time.Time) {
+	if key == "" {
+		return false, time.Time{}
+	}
+	attempt, err := l.store.IncrementAttempts(key, l.policy.Window)
+	if err != nil {
+		log.Printf("Failed to record attempt for %s: %v", key, err)
+		return false, time.Time{}
+	}
+	if l.policy.LockoutAfter <= 0 || attempt.Failures < l.policy.LockoutAfter {
+		return false, time.Time{}
+	}
+
+	until := l.now().Add(l.policy.LockoutDuration)
+	if err := l.store.LockAttempts(key, until); err != nil {
+		log.Printf("Failed to lock %s: %v", key, err)
+		return false, time.Time{}
+	}
+	return true, until
+}
+
+// Reset forgets all failures for the key.
+func (l *AttemptLimiter) Reset(key string) {
+	if key == "" {
+		return
+	}
+	if err := l.store.ResetAttempts(key); err != nil {
+		log.Printf("Failed to reset attempts for %s: %v", key, err)
+	}
+}
+
+// AuthThrottle groups the limiters protecting login, password reset and
+// verification emails.
+type AuthThrottle struct {
+	// Account is keyed by email: failed logins against one account.
+	Account *AttemptLimiter
+	// Client is keyed by IP: failed logins, reset and verification requests and
+	// bad reset tokens from one address. Its limits are looser since many users
+	// can share an IP.
+	Client *AttemptLimiter
+	// ResetEmail is keyed by email: password reset or verification emails sent
+	// to one address.
+	ResetEmail *AttemptLimiter
+}
+
+// NewAuthThrottle returns the default auth limits backed by store.
+func NewAuthThrottle(store repository.AttemptRepository) *AuthThrottle {
+	return &AuthThrottle{
+		Account: NewAttemptLimiter(store, AttemptPolicy{
+			FreeAttempts:    3,
+			BaseDelay:       1 * time.Second,
+			MaxDelay:        30 * time.Second,
+			LockoutAfter:    10,
+			LockoutDuration: 15 * time.Minute,
+			Window:          1 * time.Hour,
+		}),
+		Client: NewAttemptLimiter(store, AttemptPolicy{
+			FreeAttempts:    20,
+			BaseDelay:       1 * time.Second,
+			MaxDelay:        1 * time.Minute,
+			LockoutAfter:    100,
+			LockoutDuration: 30 * time.Minute,
+			Window:          1 * time.Hour,
+		}),
+		ResetEmail: NewAttemptLimiter(store, AttemptPolicy{
+			FreeAttempts:    3,
+			BaseDelay:       1 * time.Minute,
+			MaxDelay:        15 * time.Minute,
+			LockoutAfter:    10,
+			LockoutDuration: 1 * time.Hour,
+			Window:          1 * time.Hour,
+		}),
+	}
+}
+
+func accountKey(scope, email string) string {
+	return scope + ":email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// clientKey returns "" when the IP is unknown; limiters ignore empty keys.
+func clientKey(scope, ipAddress string) string {
+	if ipAddress == "" {
+		return ""
+	}
+	return scope + ":ip:" + ipAddress
+}
+
+func userKey(scope string, userID primitive.ObjectID) string {
+	return scope + ":user:" + userID.Hex()
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"lissanai.com/backend/internal/repository"
+)
+
+var testPolicy = AttemptPolicy{
+	FreeAttempts:    2,
+	BaseDelay:       1 * time.Second,
+	MaxDelay:        4 * time.Second,
+	LockoutAfter:    5,
+	LockoutDuration: 1 * time.Minute,
+	Window:          10 * time.Minute,
+}
+
+// testClock is a clock that only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time             { return c.now }
+func (c *testClock) Advance(step time.Duration) { c.now = c.now.Add(step) }
+
+func newTestLimiter() (*AttemptLimiter, repository.AttemptRepository, *testClock) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := repository.NewInMemoryAttemptRepositoryWithClock(clock.Now)
+	return NewAttemptLimiterWithClock(store, testPolicy, clock.Now), store, clock
+}
+
+// throttled returns the ThrottledError of err, failing the test if there is none.
+func throttled(t *testing.T, err error) *ThrottledError {
+	t.Helper()
+	var throttledErr *ThrottledError
+	if !errors.As(err, &throttledErr) {
+		t.Fatalf("expected a ThrottledError, got %v", err)
+	}
+	return throttledErr
+}
+
+func TestAttemptPolicyDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 1 * time.Second},
+		{3, 2 * time.Second},
+		{4, 4 * time.Second},
+		{9, 4 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := testPolicy.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestAttemptLimiterProgressiveDelay(t *testing.T) {
+	limiter, _, clock := newTestLimiter()
+	const key = "login:email:learner@example.com"
+
+	limiter.Fail(key)
+	if err := limiter.Check(key); err != nil {
+		t.Fatalf("first failure should be free, got %v", err)
+	}
+
+	for _, wait := range []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second} {
+		limiter.Fail(key)
+		err := throttled(t, limiter.Check(key))
+		if err.Locked || err.RetryAfter != wait {
+			t.Fatalf("expected to wait %v, got %v (locked %v)", wait, err.RetryAfter, err.Locked)
+		}
+
+		clock.Advance(wait - time.Millisecond)
+		throttled(t, limiter.Check(key))
+		clock.Advance(time.Millisecond)
+		if err := limiter.Check(key); err != nil {
+			t.Fatalf("expected to be let through after %v, got %v", wait, err)
+		}
+	}
+}
+
+func TestAttemptLimiterLockout(t *testing.T) {
+	limiter, _, clock := newTestLimiter()
+	const key = "login:email:learner@example.com"
+
+	for i := 1; i < testPolicy.LockoutAfter; i++ {
+		if locked, _ := limiter.Fail(key); locked {
+			t.Fatalf("locked after %d failures", i)
+		}
+	}
+	locked, until := limiter.Fail(key)
+	if !locked {
+		t.Fatalf("expected a lockout after %d failures", testPolicy.LockoutAfter)
+	}
+	if want := clock.Now().Add(testPolicy.LockoutDuration); !until.Equal(want) {
+		t.Fatalf("locked until %v, want %v", until, want)
+	}
+
+	err := throttled(t, limiter.Check(key))
+	if !err.Locked || err.RetryAfter != testPolicy.LockoutDuration {
+		t.Fatalf("expected a %v lockout, got %v (locked %v)", testPolicy.LockoutDuration, err.RetryAfter, err.Locked)
+	}
+
+	clock.Advance(testPolicy.LockoutDuration)
+	if err := limiter.Check(key); err != nil {
+		t.Fatalf("expected the lockout to end, got %v", err)
+	}
+}
+
+func TestAttemptLimiterWindowExpiry(t *testing.T) {
+	limiter, store, clock := newTestLimiter()
+	const key = "login:ip:203.0.113.7"
+
+	for i := 0; i < testPolicy.LockoutAfter-1; i++ {
+		limiter.Fail(key)
+	}
+	throttled(t, limiter.Check(key))
+
+	clock.Advance(testPolicy.Window + time.Second)
+	if err := limiter.Check(key); err != nil {
+		t.Fatalf("expected old failures to be forgotten, got %v", err)
+	}
+
+	limiter.Fail(key)
+	attempt, err := store.GetAttempts(key)
+	if err != nil || attempt == nil {
+		t.Fatalf("expected a counter, got %v (%v)", attempt, err)
+	}
+	if attempt.Failures != 1 {
+		t.Fatalf("expected the count to start again, got %d failures", attempt.Failures)
+	}
+}
+
+func TestAttemptLimiterReset(t *testing.T) {
+	limiter, store, _ := newTestLimiter()
+	const key = "login:email:learner@example.com"
+
+	for i := 0; i < testPolicy.LockoutAfter; i++ {
+		limiter.Fail(key)
+	}
+	throttled(t, limiter.Check(key))
+
+	limiter.Reset(key)
+	if err := limiter.Check(key); err != nil {
+		t.Fatalf("expected reset to lift the lockout, got %v", err)
+	}
+	if attempt, _ := store.GetAttempts(key); attempt != nil {
+		t.Fatalf("expected no counter after reset, got %+v", attempt)
+	}
+}
+
+func TestAttemptLimiterIgnoresEmptyKey(t *testing.T) {
+	limiter, _, _ := newTestLimiter()
+
+	for i := 0; i < testPolicy.LockoutAfter; i++ {
+		if locked, _ := limiter.Fail(""); locked {
+			t.Fatal("an empty key must never lock")
+		}
+	}
+	if err := limiter.Check(""); err != nil {
+		t.Fatalf("an empty key must never be throttled, got %v", err)
+	}
+}
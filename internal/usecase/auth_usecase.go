@@ -2,7 +2,9 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,62 +14,94 @@ import (
 	"lissanai.com/backend/internal/service"
 )
 
+// How long a refresh token, and the session it belongs to, lasts unused
+const refreshTokenLifetime = 7 * 24 * time.Hour
+
 type AuthUsecase interface {
 	Register(req *domain.RegisterRequest) (*domain.AuthResponse, error)
-	Login(req *domain.LoginRequest) (*domain.AuthResponse, error)
-	SocialAuth(req *domain.SocialAuthRequest) (*domain.AuthResponse, error)
+	Login(req *domain.LoginRequest) (*domain.LoginResponse, error)
+	SocialAuth(req *domain.SocialAuthRequest) (*domain.LoginResponse, error)
+	VerifyMFA(req *domain.MFAVerifyRequest) (*domain.AuthResponse, error)
 	Logout(userID primitive.ObjectID, refreshToken string) error
 	RefreshToken(req *domain.RefreshTokenRequest) (*domain.TokenResponse, error)
 	ForgotPassword(req *domain.ForgotPasswordRequest) error
 	ResetPassword(req *domain.ResetPasswordRequest) error
+	VerifyEmail(req *domain.VerifyEmailRequest) error
+	ResendVerification(req *domain.ResendVerificationRequest) error
 }
 
 type UserUsecase interface {
 	GetProfile(userID primitive.ObjectID) (*domain.User, error)
 	UpdateProfile(userID primitive.ObjectID, req *domain.UpdateProfileRequest) (*domain.User, error)
-	DeleteAccount(userID primitive.ObjectID) error
 	AddPushToken(userID primitive.ObjectID, req *domain.PushTokenRequest) error
+	RemovePushToken(userID primitive.ObjectID, req *domain.RemovePushTokenRequest) error
+	IsEmailVerified(userID primitive.ObjectID) (bool, error)
 }
 
 type authUsecase struct {
 	userRepo          repository.UserRepository
 	refreshTokenRepo  repository.RefreshTokenRepository
 	passwordResetRepo repository.PasswordResetRepository
+	verificationRepo  repository.EmailVerificationRepository
 	jwtService        service.JWTService
 	passwordService   service.PasswordService
 	emailService      service.EmailService
+	socialAuthService service.SocialAuthService
+	throttle          *AuthThrottle
+	mfa               *mfaVerifier
+	sessionRepo       repository.UserSessionRepository
+	sessions          *sessionRevoker
 }
 
 type userUsecase struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.UserSessionRepository
+	settingsService  service.SettingsService
+	sessions         *sessionRevoker
 }
 
 func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
 	passwordResetRepo repository.PasswordResetRepository,
+	verificationRepo repository.EmailVerificationRepository,
+	sessionRepo repository.UserSessionRepository,
 	jwtService service.JWTService,
 	passwordService service.PasswordService,
 	emailService service.EmailService,
+	socialAuthService service.SocialAuthService,
+	totpService service.TOTPService,
+	throttle *AuthThrottle,
 ) AuthUsecase {
 	return &authUsecase{
 		userRepo:          userRepo,
 		refreshTokenRepo:  refreshTokenRepo,
 		passwordResetRepo: passwordResetRepo,
+		verificationRepo:  verificationRepo,
 		jwtService:        jwtService,
 		passwordService:   passwordService,
 		emailService:      emailService,
+		socialAuthService: socialAuthService,
+		throttle:          throttle,
+		mfa:               newMFAVerifier(userRepo, totpService, passwordService, throttle),
+		sessionRepo:       sessionRepo,
+		sessions:          newSessionRevoker(userRepo, refreshTokenRepo, sessionRepo),
 	}
 }
 
 func NewUserUsecase(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	settingsService service.SettingsService,
 ) UserUsecase {
 	return &userUsecase{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		settingsService:  settingsService,
+		sessions:         newSessionRevoker(userRepo, refreshTokenRepo, sessionRepo),
 	}
 }
 
@@ -85,11 +119,13 @@ func (u *authUsecase) Register(req *domain.RegisterRequest) (*domain.AuthRespons
 	}
 
 	// Create user
+	settings := domain.DefaultSettings()
 	newUser := &domain.User{
 		Name:         req.Name,
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
-		Settings:     make(map[string]interface{}),
+		Settings:     &settings,
+		Roles:        []string{domain.RoleLearner},
 	}
 
 	user, err := u.userRepo.CreateUser(newUser)
@@ -97,97 +133,269 @@ func (u *authUsecase) Register(req *domain.RegisterRequest) (*domain.AuthRespons
 		return nil, errors.New("failed to create user")
 	}
 
-	return u.generateAuthResponse(user)
+	// Ask the user to confirm the address; registration succeeds either way
+	if err := u.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID.Hex(), err)
+	}
+
+	return u.generateAuthResponse(user, req.ClientInfo)
 }
 
-func (u *authUsecase) Login(req *domain.LoginRequest) (*domain.AuthResponse, error) {
+func (u *authUsecase) Login(req *domain.LoginRequest) (*domain.LoginResponse, error) {
+	// Refuse early while the account or client is cooling down or locked out
+	if err := u.throttle.Account.Check(accountKey("login", req.Email)); err != nil {
+		return nil, err
+	}
+	if err := u.throttle.Client.Check(clientKey("login", req.IPAddress)); err != nil {
+		return nil, err
+	}
+
 	// Get user by email
 	user, err := u.userRepo.GetUserByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		return nil, u.loginFailed(req, nil)
 	}
 
 	// Check password
 	if !u.passwordService.CheckPassword(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid email or password")
+		return nil, u.loginFailed(req, user)
 	}
 
-	return u.generateAuthResponse(user)
+	u.throttle.Account.Reset(accountKey("login", req.Email))
+	return u.startLogin(user, req.ClientInfo)
 }
 
-func (u *authUsecase) SocialAuth(req *domain.SocialAuthRequest) (*domain.AuthResponse, error) {
-	// In a real implementation, you would validate the access token with the provider
-	// For now, we'll assume the token is valid and extract user info
+// loginFailed counts a failed login against the email and the client IP and
+// returns the error to report. Unknown emails are counted the same way so
+// lockouts don't reveal which accounts exist; only real accounts get an email.
+func (u *authUsecase) loginFailed(req *domain.LoginRequest, user *domain.User) error {
+	u.throttle.Client.Fail(clientKey("login", req.IPAddress))
+
+	locked, until := u.throttle.Account.Fail(accountKey("login", req.Email))
+	if !locked {
+		return errors.New("invalid email or password")
+	}
+
+	if user != nil {
+		log.Printf("Locked account %s until %s after repeated failed logins", user.ID.Hex(), until.Format(time.RFC3339))
+		if err := u.emailService.SendAccountLockedEmail(user, until); err != nil {
+			log.Printf("Failed to send lockout email to user %s: %v", user.ID.Hex(), err)
+		}
+	}
+	return &ThrottledError{RetryAfter: time.Until(until), Locked: true}
+}
 
-	// Try to find existing user by provider
-	user, err := u.userRepo.GetUserByProviderID(req.Provider, req.AccessToken)
+func (u *authUsecase) SocialAuth(req *domain.SocialAuthRequest) (*domain.LoginResponse, error) {
+	// Verify the token with the identity provider; never trust identity fields from the client body
+	identity, err := u.socialAuthService.VerifyToken(context.Background(), req.Provider, req.AccessToken)
 	if err != nil {
-		// User doesn't exist, try to find by email
-		if req.Email != "" {
-			user, err = u.userRepo.GetUserByEmail(req.Email)
-			if err != nil {
-				// Create new user
-				user = &domain.User{
-					Name:       req.Name,
-					Email:      req.Email,
-					Provider:   req.Provider,
-					ProviderID: req.AccessToken,
-					Settings:   make(map[string]interface{}),
-				}
-				user, err = u.userRepo.CreateUser(user)
-				if err != nil {
-					return nil, errors.New("failed to create user")
-				}
-			} else {
-				// Update existing user with provider info
-				user.Provider = req.Provider
-				user.ProviderID = req.AccessToken
-				err = u.userRepo.UpdateUser(user)
-				if err != nil {
-					return nil, errors.New("failed to update user")
-				}
+		if errors.Is(err, service.ErrUnsupportedProvider) {
+			return nil, service.ErrUnsupportedProvider
+		}
+		return nil, service.ErrInvalidSocialToken
+	}
+
+	// Try to find existing user by the provider's stable subject
+	user, err := u.userRepo.GetUserByProviderID(identity.Provider, identity.Subject)
+	if err == nil {
+		return u.startLogin(user, req.ClientInfo)
+	}
+
+	// Only link or create accounts by email when the provider vouches for it
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.New("a verified email is required for social authentication")
+	}
+
+	user, err = u.userRepo.GetUserByEmail(identity.Email)
+	if err != nil {
+		// Apple only shares the user's name with the client, on first sign-in
+		name := identity.Name
+		if name == "" {
+			name = req.Name
+		}
+
+		// Create new user; the provider has already verified the email
+		now := time.Now()
+		settings := domain.DefaultSettings()
+		user = &domain.User{
+			Name:            name,
+			Email:           identity.Email,
+			Provider:        identity.Provider,
+			ProviderID:      identity.Subject,
+			Settings:        &settings,
+			Roles:           []string{domain.RoleLearner},
+			EmailVerified:   true,
+			EmailVerifiedAt: &now,
+		}
+		user, err = u.userRepo.CreateUser(user)
+		if err != nil {
+			return nil, errors.New("failed to create user")
+		}
+	} else {
+		if !user.EmailVerified {
+			// Whoever registered this email never proved they own it, so they
+			// may have pre-registered it to take over the real owner's account:
+			// drop their password, two-factor setup and sessions. The email is
+			// only marked verified once they are gone, so a retry after a
+			// failure starts over.
+			if err := u.userRepo.ClearPassword(user.ID); err != nil {
+				return nil, errors.New("failed to update user")
+			}
+			if err := u.userRepo.DisableMFA(user.ID); err != nil {
+				return nil, errors.New("failed to update user")
+			}
+			if err := u.sessions.revokeAll(user.ID, primitive.NilObjectID); err != nil {
+				return nil, err
 			}
-		} else {
-			return nil, errors.New("email is required for social authentication")
+			if err := u.refreshTokenRepo.DeleteUserRefreshTokens(user.ID); err != nil {
+				return nil, errors.New("failed to revoke existing sessions")
+			}
+			if err := u.userRepo.MarkEmailVerified(user.ID); err != nil {
+				return nil, errors.New("failed to update user")
+			}
+		}
+
+		// Update existing user with provider info
+		if err := u.userRepo.LinkProvider(user.ID, identity.Provider, identity.Subject); err != nil {
+			return nil, errors.New("failed to update user")
+		}
+		if user, err = u.userRepo.GetUserByID(user.ID); err != nil {
+			return nil, errors.New("failed to update user")
 		}
 	}
 
-	return u.generateAuthResponse(user)
+	return u.startLogin(user, req.ClientInfo)
 }
 
 func (u *authUsecase) Logout(userID primitive.ObjectID, refreshToken string) error {
 	if refreshToken != "" {
+		// Revoke the whole family so older rotated tokens of this session die too
+		token, err := u.refreshTokenRepo.GetRefreshToken(refreshToken)
+		if err != nil || token.UserID != userID {
+			return nil
+		}
+		if sessionID, err := primitive.ObjectIDFromHex(familyOf(token)); err == nil {
+			if session, err := u.sessionRepo.GetSessionByID(sessionID); err == nil {
+				return u.sessions.revoke(session)
+			}
+		}
+		if err := u.refreshTokenRepo.RevokeRefreshTokenFamily(familyOf(token)); err != nil {
+			return err
+		}
 		return u.refreshTokenRepo.DeleteRefreshToken(refreshToken)
 	}
+	if err := u.sessions.revokeAll(userID, primitive.NilObjectID); err != nil {
+		return err
+	}
 	return u.refreshTokenRepo.DeleteUserRefreshTokens(userID)
 }
 
 func (u *authUsecase) RefreshToken(req *domain.RefreshTokenRequest) (*domain.TokenResponse, error) {
 	// Get refresh token from database
 	refreshToken, err := u.refreshTokenRepo.GetRefreshToken(req.RefreshToken)
-	if err != nil {
+	if err != nil || refreshToken.Revoked {
 		return nil, errors.New("invalid refresh token")
 	}
 
+	// A token that was already rotated is being replayed: assume it leaked and kill the session
+	if refreshToken.RotatedAt != nil {
+		u.revokeFamilyOnReuse(refreshToken)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
 	// Check if token is expired
 	if refreshToken.ExpiresAt.Before(time.Now()) {
 		u.refreshTokenRepo.DeleteRefreshToken(req.RefreshToken)
 		return nil, errors.New("refresh token expired")
 	}
 
+	// Reload the user so suspensions and role changes apply on refresh
+	user, err := u.userRepo.GetUserByID(refreshToken.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if user.Suspended {
+		return nil, errors.New("account suspended")
+	}
+
+	// Generate the replacement refresh token in the same family
+	newRefreshToken, err := u.jwtService.GenerateRefreshToken()
+	if err != nil {
+		return nil, errors.New("failed to generate refresh token")
+	}
+
+	rotated, err := u.refreshTokenRepo.RotateRefreshToken(refreshToken.Token, newRefreshToken)
+	if err != nil {
+		return nil, errors.New("failed to rotate refresh token")
+	}
+	if !rotated {
+		// Someone else rotated this token between our read and write
+		u.revokeFamilyOnReuse(refreshToken)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	familyID := familyOf(refreshToken)
+	if err := u.saveRefreshToken(refreshToken.UserID, familyID, newRefreshToken); err != nil {
+		return nil, err
+	}
+
+	// Record device activity on the session this token family belongs to
+	if sessionID, err := primitive.ObjectIDFromHex(familyID); err == nil {
+		if err := u.sessionRepo.TouchSession(sessionID, req.IPAddress, req.UserAgent, time.Now().Add(refreshTokenLifetime)); err != nil {
+			log.Printf("Failed to update session %s: %v", familyID, err)
+		}
+	}
+
 	// Generate new access token
-	accessToken, err := u.jwtService.GenerateAccessToken(refreshToken.UserID)
+	accessToken, err := u.jwtService.GenerateAccessToken(user.ID, familyID, user.EffectiveRoles())
 	if err != nil {
 		return nil, errors.New("failed to generate access token")
 	}
 
 	return &domain.TokenResponse{
-		AccessToken: accessToken,
-		ExpiresIn:   15 * 60, // 15 minutes
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    int64(u.jwtService.AccessTokenTTL().Seconds()),
 	}, nil
 }
 
+func (u *authUsecase) revokeFamilyOnReuse(token *domain.RefreshToken) {
+	familyID := familyOf(token)
+	log.Printf("Refresh token reuse detected for user %s, revoking token family %s", token.UserID.Hex(), familyID)
+	// Ending the session also ends its access tokens, which may have leaked too
+	if sessionID, err := primitive.ObjectIDFromHex(familyID); err == nil {
+		if session, err := u.sessionRepo.GetSessionByID(sessionID); err == nil {
+			if err := u.sessions.revoke(session); err != nil {
+				log.Printf("Failed to revoke session %s: %v", familyID, err)
+			}
+			return
+		}
+	}
+	if err := u.refreshTokenRepo.RevokeRefreshTokenFamily(familyID); err != nil {
+		log.Printf("Failed to revoke token family %s: %v", familyID, err)
+	}
+}
+
+// familyOf returns the token's family. Tokens issued before rotation existed
+// have no family and start one named after their own ID.
+func familyOf(token *domain.RefreshToken) string {
+	if token.FamilyID == "" {
+		return token.ID.Hex()
+	}
+	return token.FamilyID
+}
+
 func (u *authUsecase) ForgotPassword(req *domain.ForgotPasswordRequest) error {
+	// Every request counts, whether or not the account exists
+	if err := u.throttle.ResetEmail.Check(accountKey("reset", req.Email)); err != nil {
+		return err
+	}
+	if err := u.throttle.Client.Check(clientKey("reset", req.IPAddress)); err != nil {
+		return err
+	}
+	u.throttle.ResetEmail.Fail(accountKey("reset", req.Email))
+	u.throttle.Client.Fail(clientKey("reset", req.IPAddress))
+
 	// Check if user exists
 	user, err := u.userRepo.GetUserByEmail(req.Email)
 	if err != nil {
@@ -211,22 +419,25 @@ func (u *authUsecase) ForgotPassword(req *domain.ForgotPasswordRequest) error {
 		return errors.New("failed to create password reset")
 	}
 
-	// Send password reset email
-	err = u.emailService.SendPasswordResetEmail(user.Email, resetToken, user.Name)
-	if err != nil {
-		// Log the error but don't fail the request - user still gets success response
-		// This prevents revealing whether email sending failed
-		// In production, you might want to log this error for monitoring
-		return nil
+	// Queue failures are logged but not reported, so the response never reveals whether the email exists
+	if err := u.emailService.SendPasswordResetEmail(user, resetToken); err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", user.ID.Hex(), err)
 	}
 
 	return nil
 }
 
 func (u *authUsecase) ResetPassword(req *domain.ResetPasswordRequest) error {
+	// Throttle token guessing per client
+	tokenKey := clientKey("reset-token", req.IPAddress)
+	if err := u.throttle.Client.Check(tokenKey); err != nil {
+		return err
+	}
+
 	// Get password reset record
 	passwordReset, err := u.passwordResetRepo.GetPasswordReset(req.Token)
 	if err != nil {
+		u.throttle.Client.Fail(tokenKey)
 		return errors.New("invalid or expired reset token")
 	}
 
@@ -243,8 +454,7 @@ func (u *authUsecase) ResetPassword(req *domain.ResetPasswordRequest) error {
 	}
 
 	// Update user password
-	user.PasswordHash = hashedPassword
-	err = u.userRepo.UpdateUser(user)
+	err = u.userRepo.SetPasswordHash(user.ID, hashedPassword)
 	if err != nil {
 		return errors.New("failed to update password")
 	}
@@ -255,15 +465,151 @@ func (u *authUsecase) ResetPassword(req *domain.ResetPasswordRequest) error {
 		return errors.New("failed to mark reset token as used")
 	}
 
-	// Delete all refresh tokens for this user
+	// Log out every device and delete all refresh tokens for this user
+	u.sessions.revokeAll(user.ID, primitive.NilObjectID)
 	u.refreshTokenRepo.DeleteUserRefreshTokens(user.ID)
 
+	// Proving control of the mailbox lifts any login lockout
+	u.throttle.Account.Reset(accountKey("login", user.Email))
+
+	return nil
+}
+
+func (u *authUsecase) VerifyEmail(req *domain.VerifyEmailRequest) error {
+	verification, err := u.verificationRepo.GetEmailVerification(req.Token)
+	if err != nil {
+		return errors.New("invalid or expired verification token")
+	}
+
+	user, err := u.userRepo.GetUserByID(verification.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	// The token only proves ownership of the address it was sent to
+	if user.Email != verification.Email {
+		return errors.New("invalid or expired verification token")
+	}
+
+	if err := u.userRepo.MarkEmailVerified(user.ID); err != nil {
+		return errors.New("failed to verify email")
+	}
+
+	if err := u.verificationRepo.MarkEmailVerificationUsed(req.Token); err != nil {
+		return errors.New("failed to mark verification token as used")
+	}
+
+	return nil
+}
+
+func (u *authUsecase) ResendVerification(req *domain.ResendVerificationRequest) error {
+	// Every request counts, whether or not the account exists
+	if err := u.throttle.ResetEmail.Check(accountKey("verify", req.Email)); err != nil {
+		return err
+	}
+	if err := u.throttle.Client.Check(clientKey("verify", req.IPAddress)); err != nil {
+		return err
+	}
+	u.throttle.ResetEmail.Fail(accountKey("verify", req.Email))
+	u.throttle.Client.Fail(clientKey("verify", req.IPAddress))
+
+	user, err := u.userRepo.GetUserByEmail(req.Email)
+	if err != nil || user.EmailVerified {
+		// Don't reveal if email exists or is already verified
+		return nil
+	}
+
+	if err := u.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to resend verification email to user %s: %v", user.ID.Hex(), err)
+	}
 	return nil
 }
 
-func (u *authUsecase) generateAuthResponse(user *domain.User) (*domain.AuthResponse, error) {
+// sendVerificationEmail replaces any outstanding verification token with a new one and emails it.
+func (u *authUsecase) sendVerificationEmail(user *domain.User) error {
+	if err := u.verificationRepo.DeleteUserEmailVerifications(user.ID); err != nil {
+		return err
+	}
+
+	verification := &domain.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     uuid.New().String(),
+		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hour expiry
+		Used:      false,
+	}
+	if err := u.verificationRepo.CreateEmailVerification(verification); err != nil {
+		return err
+	}
+
+	return u.emailService.SendVerificationEmail(user, verification.Token)
+}
+
+// startLogin finishes a login whose first factor checked out. Accounts with
+// two-factor authentication get an mfa token instead of a session.
+func (u *authUsecase) startLogin(user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	if user.Suspended {
+		return nil, errors.New("account suspended")
+	}
+
+	if user.MFAEnabled {
+		mfaToken, err := u.jwtService.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, errors.New("failed to generate mfa token")
+		}
+		return &domain.LoginResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+			MFAExpires:  int64(u.jwtService.MFATokenTTL().Seconds()),
+		}, nil
+	}
+
+	response, err := u.generateAuthResponse(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResponse{AuthResponse: response}, nil
+}
+
+func (u *authUsecase) VerifyMFA(req *domain.MFAVerifyRequest) (*domain.AuthResponse, error) {
+	userID, err := u.jwtService.ValidateMFAToken(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil || !user.MFAEnabled {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	if err := u.mfa.verify(user, req.Code); err != nil {
+		return nil, err
+	}
+
+	return u.generateAuthResponse(user, req.ClientInfo)
+}
+
+func (u *authUsecase) generateAuthResponse(user *domain.User, client domain.ClientInfo) (*domain.AuthResponse, error) {
+	if user.Suspended {
+		return nil, errors.New("account suspended")
+	}
+
+	// Start a device session; its ID is also the refresh token family
+	session := &domain.UserSession{
+		ID:         primitive.NewObjectID(),
+		UserID:     user.ID,
+		DeviceName: client.DeviceName,
+		Platform:   client.Platform,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		ExpiresAt:  time.Now().Add(refreshTokenLifetime),
+	}
+	if err := u.sessionRepo.CreateSession(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
 	// Generate access token
-	accessToken, err := u.jwtService.GenerateAccessToken(user.ID)
+	accessToken, err := u.jwtService.GenerateAccessToken(user.ID, session.ID.Hex(), user.EffectiveRoles())
 	if err != nil {
 		return nil, errors.New("failed to generate access token")
 	}
@@ -274,16 +620,10 @@ func (u *authUsecase) generateAuthResponse(user *domain.User) (*domain.AuthRespo
 		return nil, errors.New("failed to generate refresh token")
 	}
 
-	// Save refresh token to database
-	refreshToken := &domain.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshTokenString,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 days
-	}
-
-	err = u.refreshTokenRepo.CreateRefreshToken(refreshToken)
+	// Save refresh token to database, starting a new token family for this session
+	err = u.saveRefreshToken(user.ID, session.ID.Hex(), refreshTokenString)
 	if err != nil {
-		return nil, errors.New("failed to save refresh token")
+		return nil, err
 	}
 
 	// Remove sensitive data from user response
@@ -294,10 +634,24 @@ func (u *authUsecase) generateAuthResponse(user *domain.User) (*domain.AuthRespo
 		User:         &userResponse,
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenString,
-		ExpiresIn:    15 * 60, // 15 minutes
+		ExpiresIn:    int64(u.jwtService.AccessTokenTTL().Seconds()),
 	}, nil
 }
 
+func (u *authUsecase) saveRefreshToken(userID primitive.ObjectID, familyID, token string) error {
+	refreshToken := &domain.RefreshToken{
+		UserID:    userID,
+		Token:     token,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(refreshTokenLifetime),
+	}
+
+	if err := u.refreshTokenRepo.CreateRefreshToken(refreshToken); err != nil {
+		return errors.New("failed to save refresh token")
+	}
+	return nil
+}
+
 // User Usecase Implementation
 func (u *userUsecase) GetProfile(userID primitive.ObjectID) (*domain.User, error) {
 	user, err := u.userRepo.GetUserByID(userID)
@@ -305,53 +659,106 @@ func (u *userUsecase) GetProfile(userID primitive.ObjectID) (*domain.User, error
 		return nil, errors.New("user not found")
 	}
 
+	// Users created before settings existed get the defaults
+	settings := user.CurrentSettings()
+	user.Settings = &settings
+
 	// Remove sensitive data
 	user.PasswordHash = ""
 	return user, nil
 }
 
 func (u *userUsecase) UpdateProfile(userID primitive.ObjectID, req *domain.UpdateProfileRequest) (*domain.User, error) {
-	user, err := u.userRepo.GetUserByID(userID)
-	if err != nil {
+	if _, err := u.userRepo.GetUserByID(userID); err != nil {
 		return nil, errors.New("user not found")
 	}
 
 	// Update fields if provided
-	if req.Name != nil {
-		user.Name = *req.Name
+	handle := req.Handle
+	if handle != nil {
+		normalized, err := domain.NormalizeHandle(*handle)
+		if err != nil {
+			return nil, err
+		}
+		handle = &normalized
 	}
-	if req.Settings != nil {
-		if user.Settings == nil {
-			user.Settings = make(map[string]interface{})
+	if req.Name != nil || handle != nil {
+		err := u.userRepo.UpdateProfileFields(userID, req.Name, handle)
+		if errors.Is(err, repository.ErrHandleTaken) {
+			return nil, err
 		}
-		for key, value := range req.Settings {
-			user.Settings[key] = value
+		if err != nil {
+			return nil, errors.New("failed to update user")
 		}
 	}
-
-	err = u.userRepo.UpdateUser(user)
-	if err != nil {
-		return nil, errors.New("failed to update user")
+	if req.Settings != nil {
+		// Settings are written by the settings service only
+		if _, err := u.settingsService.UpdateSettings(context.Background(), userID, req.Settings); err != nil {
+			return nil, err
+		}
 	}
 
-	// Remove sensitive data
-	user.PasswordHash = ""
-	return user, nil
+	return u.GetProfile(userID)
 }
 
-func (u *userUsecase) DeleteAccount(userID primitive.ObjectID) error {
-	// Delete all refresh tokens
-	u.refreshTokenRepo.DeleteUserRefreshTokens(userID)
-
-	// Delete user
-	return u.userRepo.DeleteUser(userID)
+func (u *userUsecase) IsEmailVerified(userID primitive.ObjectID) (bool, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false, errors.New("user not found")
+	}
+	return user.EmailVerified, nil
 }
 
 func (u *userUsecase) AddPushToken(userID primitive.ObjectID, req *domain.PushTokenRequest) error {
 	pushToken := domain.PushToken{
-		Token:    req.Token,
-		Platform: req.Platform,
+		Token:     req.Token,
+		Platform:  req.Platform,
+		Provider:  req.Provider,
+		SessionID: req.SessionID,
+	}
+	if pushToken.Provider == "" {
+		pushToken.Provider = domain.PushProviderFCM
+	}
+
+	if err := u.userRepo.AddPushToken(userID, pushToken); err != nil {
+		return err
+	}
+
+	// Link the token to the device session so revoking the device removes it
+	if sessionID, err := primitive.ObjectIDFromHex(req.SessionID); err == nil {
+		if err := u.sessionRepo.SetSessionPushToken(sessionID, req.Token); err != nil {
+			log.Printf("Failed to link push token to session %s: %v", req.SessionID, err)
+		}
 	}
+	return nil
+}
 
-	return u.userRepo.AddPushToken(userID, pushToken)
+func (u *userUsecase) RemovePushToken(userID primitive.ObjectID, req *domain.RemovePushTokenRequest) error {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	var removed []domain.PushToken
+	for _, token := range user.PushTokens {
+		if req.Token != "" && token.Token == req.Token ||
+			req.Token == "" && req.SessionID != "" && token.SessionID == req.SessionID {
+			removed = append(removed, token)
+		}
+	}
+	if len(removed) == 0 {
+		return errors.New("push token not found")
+	}
+
+	for _, token := range removed {
+		if err := u.userRepo.RemovePushToken(userID, token.Token); err != nil {
+			return errors.New("failed to remove push token")
+		}
+		if sessionID, err := primitive.ObjectIDFromHex(token.SessionID); err == nil {
+			if err := u.sessionRepo.SetSessionPushToken(sessionID, ""); err != nil {
+				log.Printf("Failed to unlink push token from session %s: %v", token.SessionID, err)
+			}
+		}
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -89,8 +90,8 @@ func (u *ChatUsecase) GetNextQuestion(sessionID string) (*models.NextQuestionRet
 	return &models.NextQuestionReturn{Question: question}, nil
 }
 
-// SubmitAnswer stores the answer and returns structured feedback
-func (u *ChatUsecase) SubmitAnswer(sessionID, answerText string) (*models.Feedback, error) {
+// SubmitAnswer stores the answer with its structured feedback
+func (u *ChatUsecase) SubmitAnswer(ctx context.Context, sessionID, answerText string) (*models.Message, error) {
 	session, err := u.sessionRepo.GetSessionByID(sessionID)
 	if err != nil {
 		return nil, err
@@ -102,24 +103,25 @@ func (u *ChatUsecase) SubmitAnswer(sessionID, answerText string) (*models.Feedba
 
 	question := u.questions[session.CompletedQuestions]
 
-	feedback, err := u.aiService.GenerateFeedback(sessionID, question, answerText)
+	feedback, err := u.aiService.GenerateFeedback(ctx, sessionID, question, answerText)
 	if err != nil {
 		return nil, err
 	}
 
 	msg := &models.Message{
-		SessionID: sessionID,
-		Answer:    answerText,
-		Feedback:  feedback,
-		CreatedAt: time.Now(),
-		Question:  question,
+		SessionID:      sessionID,
+		Answer:         answerText,
+		Feedback:       feedback,
+		CreatedAt:      time.Now(),
+		Question:       question,
+		QuestionNumber: session.CompletedQuestions,
 	}
 
 	if err := u.messageRepo.AddMessage(msg); err != nil {
 		return nil, err
 	}
 
-	return feedback, nil
+	return msg, nil
 }
 
 // EndSession returns final summary with score
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"lissanai.com/backend/internal/domain/interfaces"
 	"lissanai.com/backend/internal/domain/models"
 )
@@ -15,6 +17,6 @@ func NewGrammarUsecase(aiService interfaces.AiServiceInterface) *GrammarUsecase
 	return &GrammarUsecase{AiService: aiService}
 }
 
-func (g *GrammarUsecase) CheckGrammar(text string) (*models.GrammarResponse, error) {
-	return g.AiService.CheckGrammar(text)
+func (g *GrammarUsecase) CheckGrammar(ctx context.Context, text string) (*models.GrammarResponse, error) {
+	return g.AiService.CheckGrammar(ctx, text)
 }
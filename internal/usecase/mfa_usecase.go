@@ -0,0 +1,237 @@
+// internal/usecase/mfa_usecase.go
+package usecase
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+	"lissanai.com/backend/internal/service"
+)
+
+const recoveryCodeCount = 10
+
+// MFAUsecase manages TOTP two-factor enrollment for the authenticated user.
+type MFAUsecase interface {
+	Enroll(userID primitive.ObjectID) (*domain.MFAEnrollResponse, error)
+	Confirm(userID primitive.ObjectID, req *domain.MFACodeRequest) (*domain.MFARecoveryCodesResponse, error)
+	Disable(userID primitive.ObjectID, req *domain.MFACodeRequest) error
+	RegenerateRecoveryCodes(userID primitive.ObjectID, req *domain.MFACodeRequest) (*domain.MFARecoveryCodesResponse, error)
+}
+
+type mfaUsecase struct {
+	userRepo repository.UserRepository
+	totp     service.TOTPService
+	verifier *mfaVerifier
+}
+
+func NewMFAUsecase(
+	userRepo repository.UserRepository,
+	totpService service.TOTPService,
+	passwordService service.PasswordService,
+	throttle *AuthThrottle,
+) MFAUsecase {
+	return &mfaUsecase{
+		userRepo: userRepo,
+		totp:     totpService,
+		verifier: newMFAVerifier(userRepo, totpService, passwordService, throttle),
+	}
+}
+
+func (u *mfaUsecase) Enroll(userID primitive.ObjectID) (*domain.MFAEnrollResponse, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFAEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	// The secret only becomes active once a code from it is confirmed
+	secret, err := u.totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+	if err := u.userRepo.SetPendingMFASecret(userID, secret); err != nil {
+		return nil, errors.New("failed to start enrollment")
+	}
+
+	return &domain.MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: u.totp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+func (u *mfaUsecase) Confirm(userID primitive.ObjectID, req *domain.MFACodeRequest) (*domain.MFARecoveryCodesResponse, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFAEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.MFAPendingSecret == "" {
+		return nil, errors.New("two-factor enrollment not started")
+	}
+
+	if err := u.verifier.throttle.Account.Check(userKey("mfa", userID)); err != nil {
+		return nil, err
+	}
+	if _, ok := u.totp.ValidateCode(user.MFAPendingSecret, req.Code); !ok {
+		u.verifier.throttle.Account.Fail(userKey("mfa", userID))
+		return nil, errors.New("invalid verification code")
+	}
+
+	codes, hashes, err := u.verifier.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.EnableMFA(userID, user.MFAPendingSecret, hashes); err != nil {
+		return nil, errors.New("failed to enable two-factor authentication")
+	}
+
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *mfaUsecase) Disable(userID primitive.ObjectID, req *domain.MFACodeRequest) error {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !user.MFAEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := u.verifier.verify(user, req.Code); err != nil {
+		return err
+	}
+	if err := u.userRepo.DisableMFA(userID); err != nil {
+		return errors.New("failed to disable two-factor authentication")
+	}
+	return nil
+}
+
+func (u *mfaUsecase) RegenerateRecoveryCodes(userID primitive.ObjectID, req *domain.MFACodeRequest) (*domain.MFARecoveryCodesResponse, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.MFAEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := u.verifier.verify(user, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := u.verifier.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.SetMFARecoveryCodes(userID, hashes); err != nil {
+		return nil, errors.New("failed to save recovery codes")
+	}
+
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// mfaVerifier checks a second factor: the current authenticator code or an
+// unused recovery code. Failures are throttled per user.
+type mfaVerifier struct {
+	userRepo        repository.UserRepository
+	totp            service.TOTPService
+	passwordService service.PasswordService
+	throttle        *AuthThrottle
+}
+
+func newMFAVerifier(
+	userRepo repository.UserRepository,
+	totpService service.TOTPService,
+	passwordService service.PasswordService,
+	throttle *AuthThrottle,
+) *mfaVerifier {
+	return &mfaVerifier{
+		userRepo:        userRepo,
+		totp:            totpService,
+		passwordService: passwordService,
+		throttle:        throttle,
+	}
+}
+
+func (v *mfaVerifier) verify(user *domain.User, code string) error {
+	key := userKey("mfa", user.ID)
+	if err := v.throttle.Account.Check(key); err != nil {
+		return err
+	}
+
+	ok, err := v.match(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		v.throttle.Account.Fail(key)
+		return errors.New("invalid verification code")
+	}
+
+	v.throttle.Account.Reset(key)
+	return nil
+}
+
+func (v *mfaVerifier) match(user *domain.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	// Authenticator code; each time step is accepted only once
+	if step, ok := v.totp.ValidateCode(user.MFASecret, code); ok {
+		used, err := v.userRepo.UseMFAStep(user.ID, step)
+		if err != nil {
+			return false, errors.New("failed to verify code")
+		}
+		return used, nil
+	}
+
+	// Recovery code; spent on use
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	for _, hash := range user.MFARecoveryCodes {
+		if !v.passwordService.CheckPassword(normalized, hash) {
+			continue
+		}
+		used, err := v.userRepo.UseMFARecoveryCode(user.ID, hash)
+		if err != nil {
+			return false, errors.New("failed to verify code")
+		}
+		if used {
+			log.Printf("User %s logged in with a recovery code, %d left", user.ID.Hex(), len(user.MFARecoveryCodes)-1)
+		}
+		return used, nil
+	}
+	return false, nil
+}
+
+// newRecoveryCodes returns fresh recovery codes and the hashes to store.
+func (v *mfaVerifier) newRecoveryCodes() ([]string, []string, error) {
+	codes, err := v.totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate recovery codes")
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hash, err := v.passwordService.HashPassword(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, nil, errors.New("failed to generate recovery codes")
+		}
+		hashes[i] = hash
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with or without the dash, in any case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,146 @@
+// internal/usecase/session_usecase.go
+package usecase
+
+import (
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+type SessionUsecase interface {
+	ListSessions(userID, currentSessionID primitive.ObjectID) ([]*domain.SessionResponse, error)
+	RevokeSession(userID primitive.ObjectID, sessionID string) error
+	RevokeOtherSessions(userID, currentSessionID primitive.ObjectID) error
+	IsSessionActive(userID, sessionID primitive.ObjectID) (bool, error)
+}
+
+type sessionUsecase struct {
+	sessionRepo repository.UserSessionRepository
+	revoker     *sessionRevoker
+}
+
+func NewSessionUsecase(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+) SessionUsecase {
+	return &sessionUsecase{
+		sessionRepo: sessionRepo,
+		revoker:     newSessionRevoker(userRepo, refreshTokenRepo, sessionRepo),
+	}
+}
+
+func (u *sessionUsecase) ListSessions(userID, currentSessionID primitive.ObjectID) ([]*domain.SessionResponse, error) {
+	sessions, err := u.sessionRepo.GetUserSessions(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch sessions")
+	}
+
+	responses := make([]*domain.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, &domain.SessionResponse{
+			ID:         session.ID.Hex(),
+			DeviceName: session.DeviceName,
+			Platform:   session.Platform,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			Current:    session.ID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+		})
+	}
+	return responses, nil
+}
+
+func (u *sessionUsecase) RevokeSession(userID primitive.ObjectID, sessionID string) error {
+	id, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return repository.ErrSessionNotFound
+	}
+
+	session, err := u.sessionRepo.GetSessionByID(id)
+	if err != nil || session.UserID != userID {
+		return repository.ErrSessionNotFound
+	}
+
+	return u.revoker.revoke(session)
+}
+
+func (u *sessionUsecase) RevokeOtherSessions(userID, currentSessionID primitive.ObjectID) error {
+	if currentSessionID.IsZero() {
+		return errors.New("current session unknown, please log in again")
+	}
+	return u.revoker.revokeAll(userID, currentSessionID)
+}
+
+// IsSessionActive reports whether the user's session still exists, i.e. has
+// not been revoked or logged out.
+func (u *sessionUsecase) IsSessionActive(userID, sessionID primitive.ObjectID) (bool, error) {
+	session, err := u.sessionRepo.GetSessionByID(sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return session.UserID == userID, nil
+}
+
+// sessionRevoker ends device sessions: it revokes the session's refresh token
+// family, drops the push token the device registered and deletes the session.
+type sessionRevoker struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.UserSessionRepository
+}
+
+func newSessionRevoker(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+) *sessionRevoker {
+	return &sessionRevoker{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+	}
+}
+
+func (r *sessionRevoker) revoke(session *domain.UserSession) error {
+	if err := r.refreshTokenRepo.RevokeRefreshTokenFamily(session.ID.Hex()); err != nil {
+		return errors.New("failed to revoke session")
+	}
+
+	if session.PushToken != "" {
+		if err := r.userRepo.RemovePushToken(session.UserID, session.PushToken); err != nil {
+			log.Printf("Failed to remove push token for session %s: %v", session.ID.Hex(), err)
+		}
+	}
+
+	if err := r.sessionRepo.DeleteSession(session.ID); err != nil {
+		return errors.New("failed to revoke session")
+	}
+	return nil
+}
+
+// revokeAll ends every session of the user except the one given (pass
+// primitive.NilObjectID to end all of them).
+func (r *sessionRevoker) revokeAll(userID, except primitive.ObjectID) error {
+	sessions, err := r.sessionRepo.GetUserSessions(userID)
+	if err != nil {
+		return errors.New("failed to fetch sessions")
+	}
+
+	for _, session := range sessions {
+		if session.ID == except {
+			continue
+		}
+		if err := r.revoke(session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// scripts/grant_role/main.go
+//
+// Grants roles to an existing user, e.g. to bootstrap the first admin:
+//
+//	go run ./scripts/grant_role -email admin@lissanai.com -roles admin
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/repository"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user to update")
+	rolesFlag := flag.String("roles", "", "comma separated roles (learner, content_editor, admin)")
+	flag.Parse()
+
+	if *email == "" || *rolesFlag == "" {
+		flag.Usage()
+		log.Fatal("both -email and -roles are required")
+	}
+
+	var roles []string
+	for _, role := range strings.Split(*rolesFlag, ",") {
+		role = strings.TrimSpace(role)
+		if !domain.IsValidRole(role) {
+			log.Fatalf("unknown role %q", role)
+		}
+		roles = append(roles, role)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Connect to database
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.GetUserByEmail(*email)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+	if err := userRepo.UpdateUserRoles(user.ID, roles); err != nil {
+		log.Fatalf("Failed to update roles: %v", err)
+	}
+
+	fmt.Printf("✅ %s now has roles: %s (effective after the next login or token refresh)\n", *email, strings.Join(roles, ", "))
+}
@@ -0,0 +1,69 @@
+// scripts/migrate_email_verified/main.go
+//
+// Accounts created before email verification existed have no email_verified
+// field and read as unverified, so turning on VERIFIED_EMAIL_REQUIRED_FOR
+// would lock them out. This marks them verified; accounts created since are
+// left alone. Run it before setting VERIFIED_EMAIL_REQUIRED_FOR:
+//
+//	go run ./scripts/migrate_email_verified
+//	go run ./scripts/migrate_email_verified -created-before 2026-10-01 -social-only
+//
+// Old accounts that were saved since then have email_verified stored as false;
+// -created-before grandfathers every unverified account created before the
+// given day, i.e. before verification was deployed. With -social-only only
+// accounts that signed in with Google, Apple or Facebook are grandfathered and
+// password accounts have to verify their address. Running it again changes
+// nothing.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"lissanai.com/backend/internal/database"
+)
+
+func main() {
+	createdBefore := flag.String("created-before", "", "also grandfather unverified accounts created before this day (YYYY-MM-DD)")
+	socialOnly := flag.Bool("social-only", false, "only grandfather accounts that signed in with a social provider")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Connect to database
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	filter := bson.M{"email_verified": bson.M{"$exists": false}}
+	if *createdBefore != "" {
+		day, err := time.Parse("2006-01-02", *createdBefore)
+		if err != nil {
+			log.Fatalf("Invalid -created-before day: %v", err)
+		}
+		filter = bson.M{"email_verified": bson.M{"$ne": true}, "created_at": bson.M{"$lt": day}}
+	}
+	if *socialOnly {
+		filter["provider"] = bson.M{"$in": []string{"google", "apple", "facebook"}}
+	}
+
+	// email_verified_at stays unset: we do not know when, or whether, the
+	// address was confirmed
+	result, err := db.Collection("users").UpdateMany(context.Background(), filter, bson.M{
+		"$set": bson.M{"email_verified": true, "updated_at": time.Now()},
+	})
+	if err != nil {
+		log.Fatalf("Failed to grandfather users: %v", err)
+	}
+
+	fmt.Printf("✅ Marked %d existing users as verified\n", result.ModifiedCount)
+}
@@ -0,0 +1,77 @@
+// scripts/migrate_settings/main.go
+//
+// Rewrites every user's settings at the current schema version. Settings are
+// also migrated when read, so this only saves doing it on every read:
+//
+//	go run ./scripts/migrate_settings
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Connect to database
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	ctx := context.Background()
+	users := db.Collection("users")
+
+	// Missing settings or an older version; documents are read raw so any
+	// shape of legacy settings can be migrated
+	filter := bson.M{"$or": []bson.M{
+		{"settings": bson.M{"$exists": false}},
+		{"settings.version": bson.M{"$exists": false}},
+		{"settings.version": bson.M{"$lt": domain.CurrentSettingsVersion}},
+	}}
+	cursor, err := users.Find(ctx, filter)
+	if err != nil {
+		log.Fatalf("Failed to find users: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID     `bson:"_id"`
+			Settings map[string]interface{} `bson:"settings"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			log.Printf("Skipping unreadable user: %v", err)
+			continue
+		}
+
+		settings := domain.SettingsFromDocument(doc.Settings)
+		_, err := users.UpdateOne(ctx,
+			bson.M{"_id": doc.ID},
+			bson.M{"$set": bson.M{"settings": settings, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			log.Printf("Failed to migrate settings of user %s: %v", doc.ID.Hex(), err)
+			continue
+		}
+		migrated++
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Failed to read users: %v", err)
+	}
+
+	fmt.Printf("✅ Migrated settings of %d users to version %d\n", migrated, domain.CurrentSettingsVersion)
+}
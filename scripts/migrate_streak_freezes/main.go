@@ -0,0 +1,108 @@
+// scripts/migrate_streak_freezes/main.go
+//
+// Moves users from the old streak_frozen flag and monthly freeze_count to the
+// freeze inventory in streak_freezes. A streak frozen under the old scheme
+// keeps the day after its last activity covered, recorded as a used freeze;
+// every user then gets this month's freeze:
+//
+//	go run ./scripts/migrate_streak_freezes
+//
+// Running it again changes nothing.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
+	"lissanai.com/backend/internal/service"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Connect to database
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	ctx := context.Background()
+	users := db.Collection("users")
+	// Creates the streak_freezes indexes the inserts below rely on
+	streakService := service.NewStreakService(db, nil, nil, nil)
+
+	cursor, err := users.Find(ctx, bson.M{"streak_frozen": true, "current_streak": bson.M{"$gt": 0}})
+	if err != nil {
+		log.Fatalf("Failed to find frozen streaks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	converted := 0
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			log.Printf("Skipping unreadable user: %v", err)
+			continue
+		}
+		if err := convertFrozenStreak(ctx, db, &user); err != nil {
+			log.Printf("Failed to convert frozen streak of user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		converted++
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Failed to read users: %v", err)
+	}
+
+	result, err := users.UpdateMany(ctx,
+		bson.M{"$or": []bson.M{{"streak_frozen": bson.M{"$exists": true}}, {"freeze_count": bson.M{"$exists": true}}}},
+		bson.M{"$unset": bson.M{"streak_frozen": "", "freeze_count": ""}},
+	)
+	if err != nil {
+		log.Fatalf("Failed to remove old freeze fields: %v", err)
+	}
+
+	if err := streakService.GrantMonthlyFreezes(ctx); err != nil {
+		log.Fatalf("Failed to grant monthly freezes: %v", err)
+	}
+
+	fmt.Printf("✅ Converted %d frozen streaks and cleaned up %d users\n", converted, result.ModifiedCount)
+}
+
+// convertFrozenStreak records the old freeze as a used freeze covering the
+// first day after the last activity.
+func convertFrozenStreak(ctx context.Context, db *mongo.Database, user *domain.User) error {
+	day := time.Date(user.LastActivityDate.Year(), user.LastActivityDate.Month(), user.LastActivityDate.Day()+1, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	_, err := db.Collection("streak_freezes").InsertOne(ctx, domain.StreakFreeze{
+		ID:         primitive.NewObjectID(),
+		UserID:     user.ID,
+		Source:     domain.FreezeSourceMonthly,
+		SourceKey:  "migrated",
+		EarnedAt:   now,
+		UsedOn:     &day,
+		UsedReason: domain.FreezeUseManual,
+		Note:       "frozen before the freeze inventory",
+		UsedAt:     &now,
+	})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	_, err = db.Collection("users").UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{
+		"$max": bson.M{"streak_frozen_until": day},
+	})
+	return err
+}
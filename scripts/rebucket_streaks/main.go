@@ -0,0 +1,240 @@
+// scripts/rebucket_streaks/main.go
+//
+// Moves streak activities recorded before streaks were time-zone aware onto
+// the day they happened in each user's time zone, and rebuilds the activity
+// calendar and last activity date from them:
+//
+//	go run ./scripts/rebucket_streaks -dry-run
+//	go run ./scripts/rebucket_streaks
+//
+// Streaks are only ever raised: a user whose streak was broken because
+// late-night practice landed on the wrong day gets it back, but a streak kept
+// alive by freezes is left alone. Running it again changes nothing.
+//
+// It also merges duplicate calendar days left by concurrent requests, which
+// keep the server from creating its unique (user_id, date) index; restart the
+// server afterwards.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lissanai.com/backend/internal/database"
+	"lissanai.com/backend/internal/domain"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	// Connect to database
+	db, err := database.NewMongoConnection()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	ctx := context.Background()
+	cursor, err := db.Collection("users").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{
+		"settings": 1, "current_streak": 1, "longest_streak": 1, "last_activity_date": 1,
+	}))
+	if err != nil {
+		log.Fatalf("Failed to find users: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users, moved, restored int
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			log.Printf("Skipping unreadable user: %v", err)
+			continue
+		}
+		m, r, err := rebucketUser(ctx, db, &user, *dryRun)
+		if err != nil {
+			log.Printf("Failed to re-bucket activities of user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		users++
+		moved += m
+		if r {
+			restored++
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Failed to read users: %v", err)
+	}
+
+	verb := "Moved"
+	if *dryRun {
+		verb = "Would move"
+	}
+	fmt.Printf("✅ %s %d activities of %d users to their local day; %d streaks restored\n", verb, moved, users, restored)
+}
+
+// rebucketUser returns how many of the user's activities changed day and
+// whether their streak was raised.
+func rebucketUser(ctx context.Context, db *mongo.Database, user *domain.User, dryRun bool) (int, bool, error) {
+	settings := user.CurrentSettings()
+	activities := db.Collection("streak_activities")
+
+	cursor, err := activities.Find(ctx, bson.M{"user_id": user.ID}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return 0, false, err
+	}
+	var records []domain.StreakActivity
+	if err := cursor.All(ctx, &records); err != nil {
+		return 0, false, err
+	}
+	if len(records) == 0 {
+		return 0, false, nil
+	}
+
+	moved := 0
+	var days []string
+	summaries := make(map[string]*domain.DailyActivitySummary)
+	for _, activity := range records {
+		day := settings.Day(activity.CreatedAt)
+		if !activity.Date.Equal(day) {
+			moved++
+			if !dryRun {
+				if _, err := activities.UpdateOne(ctx, bson.M{"_id": activity.ID}, bson.M{"$set": bson.M{"date": day}}); err != nil {
+					return moved, false, err
+				}
+			}
+		}
+
+		date := day.Format("2006-01-02")
+		summary, ok := summaries[date]
+		if !ok {
+			summary = &domain.DailyActivitySummary{
+				UserID:        user.ID,
+				Date:          date,
+				ActivityTypes: []string{},
+				FirstActivity: activity.CreatedAt,
+				CreatedAt:     activity.CreatedAt,
+			}
+			summaries[date] = summary
+			days = append(days, date)
+		}
+		summary.ActivityCount++
+		if !containsString(summary.ActivityTypes, activity.ActivityType) {
+			summary.ActivityTypes = append(summary.ActivityTypes, activity.ActivityType)
+		}
+		summary.LastActivity = activity.CreatedAt
+		summary.UpdatedAt = activity.CreatedAt
+	}
+
+	lastDay, _ := time.Parse("2006-01-02", days[len(days)-1])
+	run := 1
+	for i := len(days) - 1; i > 0; i-- {
+		prev, _ := time.Parse("2006-01-02", days[i-1])
+		current, _ := time.Parse("2006-01-02", days[i])
+		if !prev.AddDate(0, 0, 1).Equal(current) {
+			break
+		}
+		run++
+	}
+	// Only a streak still alive today can be restored
+	yesterday := settings.Day(time.Now()).AddDate(0, 0, -1)
+	restore := !lastDay.Before(yesterday) && run > user.CurrentStreak
+
+	if dryRun {
+		return moved, restore, nil
+	}
+
+	// Rebuild the calendar; summaries are keyed by day, so a rerun replaces them
+	summaryCollection := db.Collection("daily_activity_summaries")
+	if err := deleteDuplicateSummaries(ctx, summaryCollection, user.ID); err != nil {
+		return moved, false, err
+	}
+	for _, date := range days {
+		summary := summaries[date]
+		_, err := summaryCollection.UpdateOne(ctx,
+			bson.M{"user_id": user.ID, "date": date},
+			bson.M{
+				"$set": bson.M{
+					"activity_count": summary.ActivityCount,
+					"activity_types": summary.ActivityTypes,
+					"first_activity": summary.FirstActivity,
+					"last_activity":  summary.LastActivity,
+					"updated_at":     summary.UpdatedAt,
+				},
+				"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": summary.CreatedAt},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return moved, false, err
+		}
+	}
+	if _, err := summaryCollection.DeleteMany(ctx, bson.M{"user_id": user.ID, "date": bson.M{"$nin": days}}); err != nil {
+		return moved, false, err
+	}
+
+	set := bson.M{"last_activity_date": lastDay}
+	if restore {
+		set["current_streak"] = run
+		if run > user.LongestStreak {
+			set["longest_streak"] = run
+		}
+		log.Printf("🔥 Restoring %d day streak of user %s (was %d)", run, user.ID.Hex(), user.CurrentStreak)
+	}
+	if _, err := db.Collection("users").UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": set}); err != nil {
+		return moved, false, err
+	}
+	return moved, restore, nil
+}
+
+// deleteDuplicateSummaries keeps one summary per day, so that the unique
+// (user_id, date) index can be built. The one kept is then rewritten from the
+// activities.
+func deleteDuplicateSummaries(ctx context.Context, collection *mongo.Collection, userID primitive.ObjectID) error {
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetProjection(bson.M{"date": 1}))
+	if err != nil {
+		return err
+	}
+	var existing []struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Date string             `bson:"date"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var duplicates []primitive.ObjectID
+	for _, summary := range existing {
+		if seen[summary.Date] {
+			duplicates = append(duplicates, summary.ID)
+		}
+		seen[summary.Date] = true
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	_, err = collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicates}})
+	return err
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}